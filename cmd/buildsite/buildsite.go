@@ -0,0 +1,83 @@
+package buildsite
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	"github.com/urfave/cli/v2"
+
+	"github.com/kieranajp/the-bluer-book/internal/application/site"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/config"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/repository"
+)
+
+// pageSize is how many recipes are fetched per ListRecipes call while
+// gathering the whole book.
+const pageSize = 100
+
+var Command = &cli.Command{
+	Name:  "build-site",
+	Usage: "Render every non-archived recipe to a static, read-only HTML site",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "db-user", EnvVars: []string{"DB_USER"}},
+		&cli.StringFlag{Name: "db-pass", EnvVars: []string{"DB_PASS"}},
+		&cli.StringFlag{Name: "db-name", EnvVars: []string{"DB_NAME"}},
+		&cli.StringFlag{Name: "db-host", EnvVars: []string{"DB_HOST"}},
+		&cli.StringFlag{Name: "db-port", EnvVars: []string{"DB_PORT"}},
+		&cli.StringFlag{
+			Name:    "out",
+			Usage:   "Directory to write the site into (created if missing)",
+			Value:   "site",
+			Aliases: []string{"o"},
+		},
+		&cli.StringFlag{
+			Name:  "title",
+			Usage: "Site title shown in the header",
+			Value: "The Bluer Book",
+		},
+	},
+	Action: run,
+}
+
+func run(c *cli.Context) error {
+	log := logger.New(logger.LogLevelInfo)
+	ctx := c.Context
+
+	sqlDB, err := sql.Open("postgres", config.New(c).DBDSN())
+	if err != nil {
+		return fmt.Errorf("open db: %w", err)
+	}
+	defer sqlDB.Close()
+	if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("ping db: %w", err)
+	}
+
+	// Same repository the server reads through, so the site shows exactly what
+	// the API would. ListRecipes already excludes archived recipes.
+	repo := repository.NewRecipeRepository(db.New(sqlDB), sqlDB, log)
+
+	var recipes []*recipe.Recipe
+	for offset := 0; ; offset += pageSize {
+		page, total, err := repo.ListRecipes(ctx, pageSize, offset, "", nil, "name", false, nil)
+		if err != nil {
+			return fmt.Errorf("list recipes: %w", err)
+		}
+		recipes = append(recipes, page...)
+		if len(page) < pageSize || len(recipes) >= total {
+			break
+		}
+	}
+
+	out := c.String("out")
+	builder := site.Builder{OutDir: out, Title: c.String("title")}
+	if err := builder.Build(recipes); err != nil {
+		return fmt.Errorf("build site: %w", err)
+	}
+
+	log.Info().Int("recipes", len(recipes)).Str("out", out).Msg("Static site built")
+	return nil
+}
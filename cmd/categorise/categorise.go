@@ -0,0 +1,235 @@
+package categorise
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/genai"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry/service"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/config"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/metrics"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/repository"
+)
+
+var Command = &cli.Command{
+	Name:  "categorise-ingredients",
+	Usage: "File ingredients under grocery categories, from the keyword table and then Gemini for the rest",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "db-user", EnvVars: []string{"DB_USER"}},
+		&cli.StringFlag{Name: "db-pass", EnvVars: []string{"DB_PASS"}},
+		&cli.StringFlag{Name: "db-name", EnvVars: []string{"DB_NAME"}},
+		&cli.StringFlag{Name: "db-host", EnvVars: []string{"DB_HOST"}},
+		&cli.StringFlag{Name: "db-port", EnvVars: []string{"DB_PORT"}},
+		&cli.StringFlag{
+			Name:    "google-api-key",
+			Usage:   "Google AI Studio API key. Without one only the keyword table is used",
+			EnvVars: []string{"GOOGLE_API_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "model",
+			Usage:   "Gemini model to use",
+			EnvVars: []string{"GEMINI_MODEL"},
+			Value:   "gemini-3.5-flash",
+		},
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "Re-categorise ingredients that already have a category",
+		},
+		&cli.IntFlag{
+			Name:  "batch-size",
+			Usage: "Number of ingredients sent to Gemini per request",
+			Value: 50,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Print proposed categories without writing to the database",
+		},
+		&cli.BoolFlag{
+			Name:  "continue-on-error",
+			Usage: "Exit 0 even if some ingredients failed to categorise (intended for deploy-time init containers)",
+		},
+	},
+	Action: run,
+}
+
+// geminiResponse is the model's pick of category for each ingredient it was
+// given, by name.
+type geminiResponse []struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+func run(c *cli.Context) error {
+	log := logger.New(logger.LogLevelInfo)
+	ctx := c.Context
+
+	sqlDB, err := sql.Open("postgres", config.New(c).DBDSN())
+	if err != nil {
+		return fmt.Errorf("open db: %w", err)
+	}
+	defer sqlDB.Close()
+	if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("ping db: %w", err)
+	}
+
+	repo := repository.NewPantryRepository(db.New(sqlDB), sqlDB, log)
+	svc := service.NewPantryService(repo, metrics.NoopPantryProbe{})
+
+	ingredients, err := repo.ListIngredientCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("list ingredients: %w", err)
+	}
+	var todo []string
+	for _, ing := range ingredients {
+		if ing.Category == "" || c.Bool("all") {
+			todo = append(todo, ing.Ingredient)
+		}
+	}
+	log.Info().Int("count", len(todo)).Msg("Ingredients to categorise")
+
+	// The keyword table first: it's free, and right for the common cases.
+	picks := map[string]string{}
+	var unknown []string
+	for _, name := range todo {
+		if cat := pantry.GuessCategory(name); cat != "" {
+			picks[name] = cat
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	log.Info().Int("matched", len(picks)).Int("unmatched", len(unknown)).Msg("Keyword pass done")
+
+	categorised, failed := 0, 0
+	if len(unknown) > 0 {
+		if apiKey := c.String("google-api-key"); apiKey == "" {
+			log.Warn().Int("count", len(unknown)).Msg("No GOOGLE_API_KEY; leaving unmatched ingredients uncategorised")
+		} else {
+			client, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: apiKey})
+			if err != nil {
+				return fmt.Errorf("create genai client: %w", err)
+			}
+			cfg := buildGenerateConfig()
+			size := max(c.Int("batch-size"), 1)
+			for start := 0; start < len(unknown); start += size {
+				batch := unknown[start:min(start+size, len(unknown))]
+				resp, err := callGemini(ctx, client, c.String("model"), cfg, batch)
+				if err != nil {
+					log.Error().Err(err).Int("batch", start/size).Msg("Gemini categorising failed")
+					failed += len(batch)
+					continue
+				}
+				for _, pick := range resp {
+					if containsName(batch, pick.Name) {
+						picks[pick.Name] = pick.Category
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range todo {
+		cat, ok := picks[name]
+		if !ok {
+			continue
+		}
+		log.Info().Str("ingredient", name).Str("category", cat).Msg("Categorised")
+		if c.Bool("dry-run") {
+			categorised++
+			continue
+		}
+		if err := svc.SetIngredientCategory(ctx, name, cat); err != nil {
+			log.Error().Err(err).Str("ingredient", name).Msg("Failed to set category")
+			failed++
+			continue
+		}
+		categorised++
+	}
+
+	log.Info().
+		Int("categorised", categorised).
+		Int("skipped", len(todo)-categorised-failed).
+		Int("failed", failed).
+		Msg("Done")
+
+	if failed > 0 && !c.Bool("continue-on-error") {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func buildGenerateConfig() *genai.GenerateContentConfig {
+	temp := float32(0.1)
+	return &genai.GenerateContentConfig{
+		Temperature:      &temp,
+		ResponseMIMEType: "application/json",
+		ResponseSchema: &genai.Schema{
+			Type: genai.TypeArray,
+			Items: &genai.Schema{
+				Type:     genai.TypeObject,
+				Required: []string{"name", "category"},
+				Properties: map[string]*genai.Schema{
+					"name":     {Type: genai.TypeString, Description: "The ingredient name exactly as given"},
+					"category": {Type: genai.TypeString, Enum: pantry.Categories},
+				},
+			},
+		},
+		SystemInstruction: &genai.Content{
+			Role: "system",
+			Parts: []*genai.Part{{
+				Text: "You file groceries under the supermarket section they're bought from. Only return categories from the supplied enum. " +
+					"produce is fresh fruit, vegetables and fresh herbs; tins covers cupboard staples (tins, jars, pasta, rice, flour, oils, sauces); " +
+					"spices is dried herbs, spices and seasoning. Use other only when nothing else fits.",
+			}},
+		},
+	}
+}
+
+func callGemini(
+	ctx context.Context,
+	client *genai.Client,
+	model string,
+	cfg *genai.GenerateContentConfig,
+	names []string,
+) (geminiResponse, error) {
+	prompt := fmt.Sprintf(
+		"Ingredients:\n- %s\n\nGive each ingredient its grocery category.",
+		strings.Join(names, "\n- "),
+	)
+
+	resp, err := client.Models.GenerateContent(ctx, model, []*genai.Content{
+		{Role: "user", Parts: []*genai.Part{{Text: prompt}}},
+	}, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(resp.Text())
+	if text == "" {
+		return nil, fmt.Errorf("empty response")
+	}
+
+	var out geminiResponse
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		return nil, fmt.Errorf("decode response %q: %w", text, err)
+	}
+	return out, nil
+}
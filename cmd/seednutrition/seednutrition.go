@@ -0,0 +1,80 @@
+package seednutrition
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+
+	_ "github.com/lib/pq"
+	"github.com/urfave/cli/v2"
+
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/config"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/nutrition"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/repository"
+)
+
+var Command = &cli.Command{
+	Name:  "seed-nutrition",
+	Usage: "Load a nutrient table (per 100 g) from CSV, replacing existing rows for the same ingredients",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "db-user", EnvVars: []string{"DB_USER"}},
+		&cli.StringFlag{Name: "db-pass", EnvVars: []string{"DB_PASS"}},
+		&cli.StringFlag{Name: "db-name", EnvVars: []string{"DB_NAME"}},
+		&cli.StringFlag{Name: "db-host", EnvVars: []string{"DB_HOST"}},
+		&cli.StringFlag{Name: "db-port", EnvVars: []string{"DB_PORT"}},
+		&cli.StringFlag{
+			Name:    "file",
+			Usage:   "CSV to load (ingredient,calories,protein,fat,carbs,fibre,salt,density,piece_weight); defaults to the bundled table",
+			Aliases: []string{"f"},
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Parse and report the table without writing to the database",
+		},
+	},
+	Action: run,
+}
+
+func run(c *cli.Context) error {
+	log := logger.New(logger.LogLevelInfo)
+	ctx := c.Context
+
+	var src io.Reader = bytes.NewReader(nutrition.Bundled)
+	if path := c.String("file"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", path, err)
+		}
+		defer f.Close()
+		src = f
+	}
+	profiles, err := nutrition.ParseCSV(src)
+	if err != nil {
+		return fmt.Errorf("parse nutrient table: %w", err)
+	}
+	if c.Bool("dry-run") {
+		log.Info().Int("ingredients", len(profiles)).Msg("Dry run: nutrient table parsed, nothing written")
+		return nil
+	}
+
+	sqlDB, err := sql.Open("postgres", config.New(c).DBDSN())
+	if err != nil {
+		return fmt.Errorf("open db: %w", err)
+	}
+	defer sqlDB.Close()
+	if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("ping db: %w", err)
+	}
+
+	repo := repository.NewRecipeRepository(db.New(sqlDB), sqlDB, log)
+	if err := repo.SaveNutrientProfiles(ctx, profiles); err != nil {
+		return fmt.Errorf("save nutrient table: %w", err)
+	}
+
+	log.Info().Int("ingredients", len(profiles)).Msg("Nutrient table seeded")
+	return nil
+}
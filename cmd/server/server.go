@@ -120,7 +120,7 @@ func run(c *cli.Context) error {
 	// every sqlc query without the repository needing to know about metrics.
 	queries := db.New(metrics.NewInstrumentedDBTX(sqlDB))
 	repo := repository.NewRecipeRepository(queries, sqlDB, log)
-	pantryRepo := repository.NewPantryRepository(queries, log)
+	pantryRepo := repository.NewPantryRepository(queries, sqlDB, log)
 
 	// Create probes
 	recipeProbe := metrics.NewRecipeProbe(log)
@@ -145,7 +145,9 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to listen on MCP address %s: %w", mcpAddr, err)
 	}
-	httpMCPServer := server.NewStreamableHTTPServer(mcpServer)
+	httpMCPServer := server.NewStreamableHTTPServer(mcpServer,
+		server.WithHTTPContextFunc(mcp.RevisionSourceContext),
+	)
 	go func() {
 		log.Info().Str("address", mcpAddr).Msg("Starting MCP server")
 		if err := http.Serve(mcpListener, httpMCPServer); err != nil && err != http.ErrServerClosed {
@@ -16,36 +16,24 @@ import (
 	"github.com/urfave/cli/v2"
 	"google.golang.org/genai"
 
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/repository"
 )
 
-// Taxonomy mirrors the canonical (type, name) pairs locked down by
-// migrations/00007_label_taxonomy.sql. Keep these in sync — any value the
-// model returns that isn't in this map will be skipped.
-var taxonomy = map[string][]string{
-	"course": {
-		"main", "side", "starter", "dessert", "breakfast", "lunch", "snack",
-		"soup", "stew", "salad", "sauce", "bread", "pastry", "drink", "condiment",
-	},
-	"cuisine": {
-		"british", "irish", "german", "french", "spanish", "italian", "greek",
-		"mediterranean", "middle_eastern", "indian", "thai", "chinese", "korean",
-		"japanese", "vietnamese", "indonesian", "mexican", "american", "moroccan",
-		"african", "georgian",
-	},
-	"diet": {
-		"vegetarian", "vegan", "gluten_free", "dairy_free", "egg_free", "nut_free",
-		"low_fodmap", "low_carb", "low_calorie",
-	},
-	"method": {
-		"slow_cooked", "baked", "grilled", "fried", "roasted", "raw", "no_cook",
-		"fermented", "microwave", "sous_vide", "stir_fry",
-	},
+// untaggable are labels the model is never asked for. diet:low_calorie is a
+// matter of arithmetic, not judgement, so it is left to be added by hand with
+// the nutrition estimate (GET /api/recipes/{id}/nutrition) to go on. Nothing
+// applies it automatically; saves and lint-labels only warn when the estimate
+// contradicts it.
+var untaggable = map[string]bool{
+	"diet:low_calorie": true,
 }
 
 var Command = &cli.Command{
 	Name:  "tag-recipes",
-	Usage: "Use Gemini to tag every recipe with the canonical label taxonomy",
+	Usage: "Use Gemini to tag every recipe from the label taxonomy",
 	Flags: []cli.Flag{
 		&cli.StringFlag{Name: "db-user", EnvVars: []string{"DB_USER"}},
 		&cli.StringFlag{Name: "db-pass", EnvVars: []string{"DB_PASS"}},
@@ -96,12 +84,9 @@ type recipeForTagging struct {
 	Ingredients []string
 }
 
-type geminiResponse struct {
-	Course  string   `json:"course"`
-	Cuisine []string `json:"cuisine"`
-	Diet    []string `json:"diet"`
-	Method  []string `json:"method"`
-}
+// geminiResponse maps each label type to the model's pick: a single name for
+// a type a recipe carries one of, a list otherwise.
+type geminiResponse map[string]any
 
 func run(c *cli.Context) error {
 	log := logger.New(logger.LogLevelInfo)
@@ -117,21 +102,31 @@ func run(c *cli.Context) error {
 		c.String("db-host"), c.String("db-port"),
 		c.String("db-name"),
 	)
-	db, err := sql.Open("postgres", dsn)
+	sqlDB, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return fmt.Errorf("open db: %w", err)
 	}
-	defer db.Close()
-	if err := db.Ping(); err != nil {
+	defer sqlDB.Close()
+	if err := sqlDB.Ping(); err != nil {
 		return fmt.Errorf("ping db: %w", err)
 	}
 
+	repo := repository.NewRecipeRepository(db.New(sqlDB), sqlDB, log)
+	full, err := repo.ListTaxonomy(ctx)
+	if err != nil {
+		return fmt.Errorf("load taxonomy: %w", err)
+	}
+	taxonomy := taggable(full)
+	if len(taxonomy) == 0 {
+		return fmt.Errorf("the label taxonomy is empty")
+	}
+
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: apiKey})
 	if err != nil {
 		return fmt.Errorf("create genai client: %w", err)
 	}
 
-	recipes, err := loadRecipes(ctx, db, !c.Bool("all"))
+	recipes, err := loadRecipes(ctx, sqlDB, !c.Bool("all"))
 	if err != nil {
 		return fmt.Errorf("load recipes: %w", err)
 	}
@@ -143,12 +138,12 @@ func run(c *cli.Context) error {
 		return nil
 	}
 
-	labelIDs, err := loadLabelIDs(ctx, db)
+	labelIDs, err := loadLabelIDs(ctx, sqlDB)
 	if err != nil {
 		return fmt.Errorf("load label ids: %w", err)
 	}
 
-	cfg := buildGenerateConfig()
+	cfg := buildGenerateConfig(taxonomy)
 
 	sem := make(chan struct{}, c.Int("concurrency"))
 	var wg sync.WaitGroup
@@ -171,7 +166,7 @@ func run(c *cli.Context) error {
 				return
 			}
 
-			labels := normaliseTags(tags)
+			labels := normaliseTags(taxonomy, tags)
 			if len(labels) == 0 {
 				log.Warn().Str("recipe", r.Name).Msg("No valid labels returned")
 				mu.Lock()
@@ -192,7 +187,7 @@ func run(c *cli.Context) error {
 				return
 			}
 
-			if err := applyLabels(ctx, db, r.UUID, labels, labelIDs, &mu); err != nil {
+			if err := applyLabels(ctx, sqlDB, r.UUID, labels, labelIDs, &mu); err != nil {
 				log.Error().Err(err).Str("recipe", r.Name).Msg("Failed to apply labels")
 				mu.Lock()
 				failed++
@@ -271,47 +266,61 @@ func loadLabelIDs(ctx context.Context, db *sql.DB) (map[string]uuid.UUID, error)
 	return ids, rows.Err()
 }
 
-func buildGenerateConfig() *genai.GenerateContentConfig {
-	courseEnum := taxonomy["course"]
-	cuisineEnum := taxonomy["cuisine"]
-	dietEnum := taxonomy["diet"]
-	methodEnum := taxonomy["method"]
+// taggable is the part of t the model may pick from: every type with at
+// least one label, less the untaggable ones.
+func taggable(t recipe.Taxonomy) recipe.Taxonomy {
+	var out recipe.Taxonomy
+	for _, lt := range t {
+		var labels []recipe.LabelDefinition
+		for _, d := range lt.Labels {
+			if !untaggable[d.Type+":"+d.Name] {
+				labels = append(labels, d)
+			}
+		}
+		if len(labels) > 0 {
+			lt.Labels = labels
+			out = append(out, lt)
+		}
+	}
+	return out
+}
+
+func buildGenerateConfig(taxonomy recipe.Taxonomy) *genai.GenerateContentConfig {
+	properties := map[string]*genai.Schema{}
+	for _, lt := range taxonomy {
+		about := lt.Description
+		if about == "" {
+			about = lt.DisplayName
+		}
+		if lt.Multiple {
+			properties[lt.Name] = &genai.Schema{
+				Type:        genai.TypeArray,
+				Description: about + ". Empty if none clearly apply.",
+				Items:       &genai.Schema{Type: genai.TypeString, Enum: taxonomy.Names(lt.Name)},
+			}
+		} else {
+			properties[lt.Name] = &genai.Schema{
+				Type:        genai.TypeString,
+				Enum:        taxonomy.Names(lt.Name),
+				Description: about + ". Pick exactly one.",
+			}
+		}
+	}
 
 	temp := float32(0.1)
 	return &genai.GenerateContentConfig{
 		Temperature:      &temp,
 		ResponseMIMEType: "application/json",
 		ResponseSchema: &genai.Schema{
-			Type:     genai.TypeObject,
-			Required: []string{"course", "cuisine", "diet", "method"},
-			Properties: map[string]*genai.Schema{
-				"course": {
-					Type:        genai.TypeString,
-					Enum:        courseEnum,
-					Description: "The primary course this recipe is. Pick exactly one.",
-				},
-				"cuisine": {
-					Type:        genai.TypeArray,
-					Description: "Cuisine(s) the recipe belongs to. Empty if not clearly tied to any.",
-					Items:       &genai.Schema{Type: genai.TypeString, Enum: cuisineEnum},
-				},
-				"diet": {
-					Type:        genai.TypeArray,
-					Description: "Diets the recipe naturally satisfies. Empty if none apply.",
-					Items:       &genai.Schema{Type: genai.TypeString, Enum: dietEnum},
-				},
-				"method": {
-					Type:        genai.TypeArray,
-					Description: "Cooking methods used. Empty if none of the listed methods clearly apply.",
-					Items:       &genai.Schema{Type: genai.TypeString, Enum: methodEnum},
-				},
-			},
+			Type:       genai.TypeObject,
+			Required:   taxonomy.TypeNames(),
+			Properties: properties,
 		},
 		SystemInstruction: &genai.Content{
 			Role: "system",
 			Parts: []*genai.Part{{
 				Text: "You categorise recipes against a fixed taxonomy. Only return values from the supplied enums. " +
-					"Be conservative: if a recipe isn't clearly tied to a cuisine, diet, or method, return an empty list rather than guessing. " +
+					"Be conservative: if a recipe isn't clearly tied to any value of a list-valued type, return an empty list rather than guessing. " +
 					"For 'diet': only include a tag if the recipe genuinely satisfies it as written (no meat = vegetarian; no animal products at all = vegan; etc.).",
 			}},
 		},
@@ -324,7 +333,7 @@ func callGemini(
 	model string,
 	cfg *genai.GenerateContentConfig,
 	r recipeForTagging,
-) (*geminiResponse, error) {
+) (geminiResponse, error) {
 	prompt := fmt.Sprintf(
 		"Recipe name: %s\n\nDescription: %s\n\nIngredients: %s\n\nTag this recipe with the appropriate taxonomy values.",
 		r.Name,
@@ -348,34 +357,35 @@ func callGemini(
 	if err := json.Unmarshal([]byte(text), &out); err != nil {
 		return nil, fmt.Errorf("decode response %q: %w", text, err)
 	}
-	return &out, nil
+	return out, nil
 }
 
 // normaliseTags converts the model output into a flat list of (type, name)
-// pairs and drops any value not in the canonical taxonomy.
-func normaliseTags(g *geminiResponse) map[string][]string {
+// pairs, resolving aliases, and drops any value not in the taxonomy.
+func normaliseTags(taxonomy recipe.Taxonomy, g geminiResponse) map[string][]string {
 	out := map[string][]string{}
-	add := func(typ, name string) {
-		name = strings.ToLower(strings.TrimSpace(name))
-		if name == "" {
+	add := func(typ string, v any) {
+		name, _ := v.(string)
+		d, ok := taxonomy.Lookup(typ, name)
+		if !ok || untaggable[d.Type+":"+d.Name] {
 			return
 		}
-		for _, allowed := range taxonomy[typ] {
-			if allowed == name {
-				out[typ] = append(out[typ], name)
+		for _, seen := range out[d.Type] {
+			if seen == d.Name {
 				return
 			}
 		}
+		out[d.Type] = append(out[d.Type], d.Name)
 	}
-	add("course", g.Course)
-	for _, v := range g.Cuisine {
-		add("cuisine", v)
-	}
-	for _, v := range g.Diet {
-		add("diet", v)
-	}
-	for _, v := range g.Method {
-		add("method", v)
+	for _, lt := range taxonomy {
+		switch v := g[lt.Name].(type) {
+		case []any:
+			for _, item := range v {
+				add(lt.Name, item)
+			}
+		default:
+			add(lt.Name, v)
+		}
 	}
 	return out
 }
@@ -417,9 +427,9 @@ func applyLabels(
 			id, ok := labelIDs[key]
 			mu.Unlock()
 			if !ok {
-				// Label row doesn't exist yet (shouldn't happen for taxonomy
-				// rows since the migration seeded them, but be defensive for
-				// any future additions). Insert it and cache the id.
+				// Label row doesn't exist yet (shouldn't happen, since the
+				// taxonomy is read from the labels table, but be defensive).
+				// Insert it and cache the id.
 				id = uuid.New()
 				_, err := tx.ExecContext(ctx, `
 					INSERT INTO labels (uuid, type, name, created_at, updated_at)
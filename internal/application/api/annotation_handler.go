@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// annotationIDFromPath reads the {annotation} path parameter. Like
+// recipeIDFromPath it writes the error response itself and returns ok=false
+// on failure.
+func (h *RecipeHandler) annotationIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("annotation"))
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_annotation_id", "Annotation ID must be a valid UUID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// writeAnnotationError maps the errors shared by the annotation endpoints,
+// falling back to a 500 with the given code and message.
+func (h *RecipeHandler) writeAnnotationError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, recipe.ErrRecipeNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "recipe_not_found", "Recipe not found")
+	case errors.Is(err, recipe.ErrAnnotationNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "annotation_not_found", "Annotation not found")
+	case errors.Is(err, recipe.ErrInvalidAnnotation):
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_annotation", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
+
+// POST /api/recipes/{id}/annotations - Add a note to the recipe, or to one of
+// its steps when step (the step's position) is given.
+func (h *RecipeHandler) AddAnnotation(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+		Step int16  `json:"step"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	annotation, err := h.recipeService.AddAnnotation(r.Context(), recipeID, recipe.Annotation{Note: body.Note, Step: body.Step})
+	if err != nil {
+		h.writeAnnotationError(w, err, "annotation_failed", "Failed to add annotation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(annotation)
+}
+
+// PUT /api/recipes/{id}/annotations/{annotation} - Reword a note
+func (h *RecipeHandler) UpdateAnnotation(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	annotationID, ok := h.annotationIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	annotation, err := h.recipeService.UpdateAnnotation(r.Context(), recipeID, annotationID, body.Note)
+	if err != nil {
+		h.writeAnnotationError(w, err, "annotation_failed", "Failed to update annotation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(annotation)
+}
+
+// DELETE /api/recipes/{id}/annotations/{annotation} - Remove a note
+func (h *RecipeHandler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	annotationID, ok := h.annotationIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.recipeService.DeleteAnnotation(r.Context(), recipeID, annotationID); err != nil {
+		h.writeAnnotationError(w, err, "annotation_failed", "Failed to delete annotation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
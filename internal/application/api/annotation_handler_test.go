@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestAddAnnotation_Step(t *testing.T) {
+	svc := &stubRecipeService{}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/annotations", strings.NewReader(`{"note":"use the big pan","step":2}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.AddAnnotation(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got recipe.Annotation
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.Note != "use the big pan" || got.Step != 2 || got.UUID == uuid.Nil {
+		t.Errorf("unexpected annotation: %+v", got)
+	}
+}
+
+func TestAddAnnotation_StepOutOfRange(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/annotations", strings.NewReader(`{"note":"hm","step":9}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.AddAnnotation(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestUpdateAndDeleteAnnotation(t *testing.T) {
+	id := uuid.New()
+	svc := &stubRecipeService{annotations: []recipe.Annotation{{UUID: id, Note: "too hot"}}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/recipes/"+revisionTestRecipeID+"/annotations/"+id.String(), strings.NewReader(`{"note":"halve the chilli for the kids"}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	req.SetPathValue("annotation", id.String())
+	rec := httptest.NewRecorder()
+	h.UpdateAnnotation(rec, req)
+	if rec.Code != http.StatusOK || svc.annotations[0].Note != "halve the chilli for the kids" {
+		t.Fatalf("update: got %d, annotations %+v", rec.Code, svc.annotations)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/recipes/"+revisionTestRecipeID+"/annotations/"+id.String(), nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	req.SetPathValue("annotation", id.String())
+	rec = httptest.NewRecorder()
+	h.DeleteAnnotation(rec, req)
+	if rec.Code != http.StatusNoContent || len(svc.annotations) != 0 {
+		t.Fatalf("delete: got %d, annotations %+v", rec.Code, svc.annotations)
+	}
+
+	rec = httptest.NewRecorder()
+	h.DeleteAnnotation(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 deleting it again, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// GET /api/ingredients/catalogue - Every ingredient with its aliases, how
+// many recipes use it and whether it's in the pantry.
+func (h *RecipeHandler) GetIngredientCatalogue(w http.ResponseWriter, r *http.Request) {
+	catalogue, err := h.recipeService.IngredientCatalogue(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to load ingredient catalogue")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to load ingredient catalogue")
+		return
+	}
+	if catalogue == nil {
+		catalogue = recipe.IngredientCatalogue{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"ingredients": catalogue})
+}
+
+// PUT /api/ingredients/{name}/aliases - Replace the other names an
+// ingredient goes by, given as {"aliases": ["scallions"]}.
+func (h *RecipeHandler) SetIngredientAliases(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Aliases []string `json:"aliases"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	name := r.PathValue("name")
+	saved, err := h.recipeService.SetIngredientAliases(r.Context(), name, body.Aliases)
+	if err != nil {
+		h.writeCatalogueError(w, err, "alias_save_failed", "Failed to save ingredient aliases")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+	h.logger.Info().Str("ingredient", saved.Name).Strs("aliases", saved.Aliases).Msg("Ingredient aliases saved")
+}
+
+// POST /api/ingredients/rename - Rename an ingredient, given as {"name":
+// "Spring Onions", "to": "spring onion"}. Recipes and the pantry keep it, and
+// the old name stays as an alias.
+func (h *RecipeHandler) RenameIngredient(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+		To   string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	renamed, err := h.recipeService.RenameIngredient(r.Context(), body.Name, body.To)
+	if err != nil {
+		h.writeCatalogueError(w, err, "ingredient_rename_failed", "Failed to rename ingredient")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(renamed)
+	h.logger.Info().Str("from", body.Name).Str("to", renamed.Name).Msg("Ingredient renamed")
+}
+
+// POST /api/ingredients/merge - Fold one ingredient into another, given as
+// {"from": "scallions", "into": "spring onion"}. Recipe lines and the pantry
+// move over, and from's names become aliases of into.
+func (h *RecipeHandler) MergeIngredients(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		From string `json:"from"`
+		Into string `json:"into"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	merged, err := h.recipeService.MergeIngredients(r.Context(), body.From, body.Into)
+	if err != nil {
+		h.writeCatalogueError(w, err, "ingredient_merge_failed", "Failed to merge ingredients")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merged)
+	h.logger.Info().Str("from", body.From).Str("into", merged.Name).Msg("Ingredients merged")
+}
+
+func (h *RecipeHandler) writeCatalogueError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, recipe.ErrIngredientNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "ingredient_not_found", err.Error())
+	case errors.Is(err, recipe.ErrInvalidIngredient):
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_ingredient", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
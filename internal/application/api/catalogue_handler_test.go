@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+var stubCatalogue = recipe.IngredientCatalogue{
+	{Name: "spring onion", Aliases: []string{"scallions"}},
+	{Name: "tomato"},
+}
+
+func TestSetIngredientAliases_Taken(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{catalogue: stubCatalogue}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/ingredients/tomato/aliases", strings.NewReader(`{"aliases":["Scallions"]}`))
+	req.SetPathValue("name", "tomato")
+	rec := httptest.NewRecorder()
+	h.SetIngredientAliases(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMergeIngredients(t *testing.T) {
+	for body, want := range map[string]int{
+		`{"from":"tomato","into":"spring onion"}`: http.StatusOK,
+		`{"from":"tomatoes","into":"tomato"}`:     http.StatusNotFound,
+		`{"from":`:                                http.StatusBadRequest,
+	} {
+		h := NewRecipeHandler(&stubRecipeService{catalogue: stubCatalogue}, &noopLogger{})
+		req := httptest.NewRequest(http.MethodPost, "/api/ingredients/merge", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.MergeIngredients(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", body, want, rec.Code)
+		}
+	}
+}
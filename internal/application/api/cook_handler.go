@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// writeCookError maps the errors shared by the cook log endpoints, falling
+// back to a 500 with the given code and message.
+func (h *RecipeHandler) writeCookError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, recipe.ErrRecipeNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "recipe_not_found", "Recipe not found")
+	case errors.Is(err, recipe.ErrInvalidCook):
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_cook", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
+
+// POST /api/recipes/{id}/cooks - Record that the recipe was cooked. Every
+// field is optional; date defaults to today.
+func (h *RecipeHandler) LogCook(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Date     string   `json:"date"`
+		Rating   int16    `json:"rating"`
+		Notes    string   `json:"notes"`
+		Servings int16    `json:"servings"`
+		Photos   []string `json:"photos"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	cook := recipe.Cook{Rating: body.Rating, Notes: body.Notes, Servings: body.Servings}
+	if body.Date != "" {
+		day, err := time.Parse(time.DateOnly, body.Date)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid_date", "date must be formatted YYYY-MM-DD")
+			return
+		}
+		cook.CookedOn = day
+	}
+	for _, url := range body.Photos {
+		if url != "" {
+			cook.Photos = append(cook.Photos, recipe.Photo{URL: url})
+		}
+	}
+
+	logged, err := h.recipeService.LogCook(r.Context(), recipeID, cook)
+	if err != nil {
+		h.writeCookError(w, err, "cook_log_failed", "Failed to record cook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(logged)
+
+	h.logger.Info().Str("recipe_id", recipeID.String()).Msg("Cook logged via API")
+}
+
+// GET /api/recipes/{id}/cooks - The recipe's cook log, most recent first
+func (h *RecipeHandler) ListCooks(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	cooks, err := h.recipeService.ListCooks(r.Context(), recipeID)
+	if err != nil {
+		h.writeCookError(w, err, "listing_failed", "Failed to list cooks")
+		return
+	}
+	if cooks == nil {
+		cooks = []recipe.Cook{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"cooks": cooks,
+		"total": len(cooks),
+	})
+}
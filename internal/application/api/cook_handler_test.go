@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestLogCook_Success(t *testing.T) {
+	svc := &stubRecipeService{}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	body := `{"date":"2026-10-18","rating":5,"notes":"needed more salt","servings":4,"photos":["https://images.example/dinner.jpg"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/cooks", strings.NewReader(body))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.LogCook(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["cookedOn"] != "2026-10-18" || resp["rating"] != 5.0 || resp["notes"] != "needed more salt" {
+		t.Errorf("unexpected cook: %v", resp)
+	}
+	if len(svc.cooks) != 1 || len(svc.cooks[0].Photos) != 1 {
+		t.Errorf("expected the cook and its photo to be recorded, got %+v", svc.cooks)
+	}
+}
+
+func TestLogCook_InvalidCook(t *testing.T) {
+	// The service validates; the handler only has to map its error to a 400.
+	svc := &stubRecipeService{err: recipe.InvalidCookError{Reason: "rating must be between 1 and 5"}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/cooks", strings.NewReader(`{"rating":7}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.LogCook(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestLogCook_InvalidDate(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/cooks", strings.NewReader(`{"date":"last tuesday"}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.LogCook(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestListCooks_Empty(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/cooks", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.ListCooks(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"cooks":[],"total":0}` {
+		t.Errorf("unexpected body: %s", got)
+	}
+}
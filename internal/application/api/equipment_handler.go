@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// equipmentFromPath reads the {name} path parameter, normalised to the stored
+// snake_case form. It writes an error response and returns ok=false when
+// there's no name left.
+func (h *RecipeHandler) equipmentFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := recipe.NormalizeEquipmentName(r.PathValue("name"))
+	if name == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "missing_equipment", "Equipment name is required")
+		return "", false
+	}
+	return name, true
+}
+
+// GET /api/equipment - The kitchen equipment the household owns.
+func (h *RecipeHandler) ListEquipment(w http.ResponseWriter, r *http.Request) {
+	items, err := h.recipeService.ListEquipment(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list equipment")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list equipment")
+		return
+	}
+	if items == nil {
+		items = []recipe.EquipmentItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"equipment": items})
+}
+
+// PUT /api/equipment/{name} - Record that the household owns a piece of
+// equipment. Idempotent.
+func (h *RecipeHandler) AddEquipment(w http.ResponseWriter, r *http.Request) {
+	name, ok := h.equipmentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.recipeService.AddEquipment(r.Context(), name); err != nil {
+		h.logger.Error().Err(err).Str("equipment", name).Msg("Failed to add equipment")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "equipment_add_failed", "Failed to add equipment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("equipment", name).Msg("Equipment added")
+}
+
+// DELETE /api/equipment/{name} - The inverse. Removing equipment that isn't
+// owned is a no-op.
+func (h *RecipeHandler) RemoveEquipment(w http.ResponseWriter, r *http.Request) {
+	name, ok := h.equipmentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.recipeService.RemoveEquipment(r.Context(), name); err != nil {
+		h.logger.Error().Err(err).Str("equipment", name).Msg("Failed to remove equipment")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "equipment_remove_failed", "Failed to remove equipment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("equipment", name).Msg("Equipment removed")
+}
+
+// ownedEquipmentOnly reads the ?equipment= filter on recipe listings: "owned"
+// leaves out recipes needing anything the household doesn't have.
+func ownedEquipmentOnly(r *http.Request) bool {
+	return strings.EqualFold(r.URL.Query().Get("equipment"), "owned")
+}
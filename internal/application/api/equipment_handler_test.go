@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestAddEquipment_NormalisesName(t *testing.T) {
+	svc := &stubRecipeService{}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/equipment/Stand%20Mixer", nil)
+	req.SetPathValue("name", "Stand Mixer")
+	rec := httptest.NewRecorder()
+	h.AddEquipment(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(svc.equipment) != 1 || svc.equipment[0].Name != "stand_mixer" {
+		t.Errorf("expected stand_mixer to be added, got %+v", svc.equipment)
+	}
+}
+
+func TestAddEquipment_BlankName(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/equipment/%20-%20", nil)
+	req.SetPathValue("name", " - ")
+	rec := httptest.NewRecorder()
+	h.AddEquipment(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestListEquipment_Empty(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	rec := httptest.NewRecorder()
+	h.ListEquipment(rec, httptest.NewRequest(http.MethodGet, "/api/equipment", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Equipment []recipe.EquipmentItem `json:"equipment"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Equipment == nil {
+		t.Error("expected an empty list, not null")
+	}
+}
+
+func TestListRecipes_OwnedEquipmentFilter(t *testing.T) {
+	for query, want := range map[string]bool{
+		"/api/recipes":                 false,
+		"/api/recipes?equipment=owned": true,
+		"/api/recipes?equipment=any":   false,
+	} {
+		svc := &stubRecipeService{}
+		h := NewRecipeHandler(svc, &noopLogger{})
+		h.ListRecipes(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, query, nil))
+		if svc.ownedOnly != want {
+			t.Errorf("%s: ownedEquipmentOnly = %v, want %v", query, svc.ownedOnly, want)
+		}
+	}
+}
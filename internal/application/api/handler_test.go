@@ -6,7 +6,9 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
@@ -25,6 +27,23 @@ func (n *noopLogger) Error() *zerolog.Event { l := zerolog.Nop(); return l.Error
 type stubRecipeService struct {
 	units       []recipe.Unit
 	ingredients []recipe.Ingredient
+	mealPlan    []*recipe.Recipe
+	revisions   []recipe.Revision
+	diff        recipe.Diff
+	reverted    int
+	cooks       []recipe.Cook
+	annotations []recipe.Annotation
+	equipment   []recipe.EquipmentItem
+	ownedOnly   bool
+	nutrition   *recipe.NutritionEstimate
+	people      []recipe.Person
+	eaters      recipe.Eaters
+	taxonomy    recipe.Taxonomy
+	renamed     [2]string
+	merged      [2]recipe.Label
+	dryRun      bool
+	catalogue   recipe.IngredientCatalogue
+	unitReviews []recipe.UnitReview
 	err         error
 }
 
@@ -34,8 +53,10 @@ func (s *stubRecipeService) CreateRecipe(_ context.Context, _ recipe.Recipe) (*r
 func (s *stubRecipeService) GetRecipe(_ context.Context, _ uuid.UUID) (*recipe.Recipe, error) {
 	return nil, nil
 }
-func (s *stubRecipeService) ListRecipes(_ context.Context, _, _ int, _ string, _ []string, _ string) ([]*recipe.Recipe, int, error) {
-	return nil, 0, nil
+func (s *stubRecipeService) ListRecipes(_ context.Context, _, _ int, _ string, _ []string, _ string, ownedEquipmentOnly bool, eaters recipe.Eaters) ([]*recipe.Recipe, int, error) {
+	s.ownedOnly = ownedEquipmentOnly
+	s.eaters = eaters
+	return nil, 0, s.err
 }
 func (s *stubRecipeService) UpdateRecipe(_ context.Context, _ uuid.UUID, _ recipe.Recipe) (*recipe.Recipe, error) {
 	return nil, nil
@@ -47,17 +68,194 @@ func (s *stubRecipeService) RestoreRecipe(_ context.Context, _ uuid.UUID) (*reci
 func (s *stubRecipeService) ListArchivedRecipes(_ context.Context, _, _ int) ([]*recipe.Recipe, int, error) {
 	return nil, 0, nil
 }
-func (s *stubRecipeService) AddToMealPlan(_ context.Context, _ uuid.UUID) error    { return nil }
+func (s *stubRecipeService) AddToMealPlan(_ context.Context, _ uuid.UUID, _ *time.Time) error {
+	return nil
+}
 func (s *stubRecipeService) RemoveFromMealPlan(_ context.Context, _ uuid.UUID) error { return nil }
-func (s *stubRecipeService) ListMealPlanRecipes(_ context.Context) ([]*recipe.Recipe, error) {
-	return nil, nil
+func (s *stubRecipeService) ListMealPlanRecipes(_ context.Context, eaters []string) ([]*recipe.Recipe, error) {
+	s.eaters = recipe.Eaters{Names: eaters}
+	return s.mealPlan, s.err
+}
+
+func (s *stubRecipeService) ListRevisions(_ context.Context, _ uuid.UUID) ([]recipe.Revision, error) {
+	return s.revisions, s.err
+}
+func (s *stubRecipeService) GetRevision(_ context.Context, id uuid.UUID, number int) (*recipe.Revision, error) {
+	for _, rev := range s.revisions {
+		if rev.Number == number {
+			return &rev, s.err
+		}
+	}
+	return nil, recipe.RevisionNotFoundError{RecipeID: id, Revision: number}
+}
+func (s *stubRecipeService) DiffRevisions(_ context.Context, _ uuid.UUID, _, _ int) (recipe.Diff, error) {
+	return s.diff, s.err
+}
+func (s *stubRecipeService) RevertToRevision(_ context.Context, id uuid.UUID, number int) (*recipe.Recipe, error) {
+	if _, err := s.GetRevision(context.Background(), id, number); err != nil {
+		return nil, err
+	}
+	s.reverted = number
+	return &recipe.Recipe{UUID: id}, s.err
+}
+
+func (s *stubRecipeService) LogCook(_ context.Context, recipeID uuid.UUID, cook recipe.Cook) (*recipe.Cook, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	cook.UUID = uuid.New()
+	cook.RecipeID = recipeID
+	s.cooks = append(s.cooks, cook)
+	return &cook, nil
+}
+func (s *stubRecipeService) ListCooks(_ context.Context, _ uuid.UUID) ([]recipe.Cook, error) {
+	return s.cooks, s.err
+}
+
+func (s *stubRecipeService) AddAnnotation(_ context.Context, _ uuid.UUID, a recipe.Annotation) (*recipe.Annotation, error) {
+	if err := recipe.ValidateAnnotation(recipe.Recipe{Steps: make([]recipe.Step, 3)}, a); err != nil {
+		return nil, err
+	}
+	a.UUID = uuid.New()
+	s.annotations = append(s.annotations, a)
+	return &a, s.err
+}
+func (s *stubRecipeService) UpdateAnnotation(_ context.Context, recipeID, annotationID uuid.UUID, note string) (*recipe.Annotation, error) {
+	for i := range s.annotations {
+		if s.annotations[i].UUID == annotationID {
+			s.annotations[i].Note = note
+			return &s.annotations[i], s.err
+		}
+	}
+	return nil, recipe.AnnotationNotFoundError{RecipeID: recipeID, AnnotationID: annotationID}
+}
+func (s *stubRecipeService) DeleteAnnotation(_ context.Context, recipeID, annotationID uuid.UUID) error {
+	for i := range s.annotations {
+		if s.annotations[i].UUID == annotationID {
+			s.annotations = append(s.annotations[:i], s.annotations[i+1:]...)
+			return s.err
+		}
+	}
+	return recipe.AnnotationNotFoundError{RecipeID: recipeID, AnnotationID: annotationID}
+}
+
+func (s *stubRecipeService) ListEquipment(_ context.Context) ([]recipe.EquipmentItem, error) {
+	return s.equipment, s.err
+}
+func (s *stubRecipeService) AddEquipment(_ context.Context, name string) error {
+	s.equipment = append(s.equipment, recipe.EquipmentItem{Name: name})
+	return s.err
+}
+func (s *stubRecipeService) RemoveEquipment(_ context.Context, name string) error {
+	for i, e := range s.equipment {
+		if e.Name == name {
+			s.equipment = append(s.equipment[:i], s.equipment[i+1:]...)
+			break
+		}
+	}
+	return s.err
+}
+func (s *stubRecipeService) Nutrition(_ context.Context, _ uuid.UUID) (*recipe.NutritionEstimate, error) {
+	return s.nutrition, s.err
+}
+func (s *stubRecipeService) ListPeople(_ context.Context) ([]recipe.Person, error) {
+	return s.people, s.err
+}
+func (s *stubRecipeService) SavePerson(_ context.Context, p recipe.Person) (*recipe.Person, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	recipe.NormalizePerson(&p)
+	if err := recipe.ValidatePerson(p); err != nil {
+		return nil, err
+	}
+	s.people = append(s.people, p)
+	return &p, nil
+}
+func (s *stubRecipeService) DeletePerson(_ context.Context, name string) error {
+	if s.err != nil {
+		return s.err
+	}
+	for i, p := range s.people {
+		if strings.EqualFold(p.Name, name) {
+			s.people = append(s.people[:i], s.people[i+1:]...)
+			return nil
+		}
+	}
+	return recipe.PersonNotFoundError{Name: name}
+}
+func (s *stubRecipeService) CheckLabels(_ context.Context, r recipe.Recipe) ([]recipe.LabelWarning, error) {
+	return recipe.CheckDietLabels(r), s.err
+}
+func (s *stubRecipeService) ForkRecipe(_ context.Context, parentID uuid.UUID, changes recipe.Recipe) (*recipe.Recipe, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	v := changes
+	v.UUID = uuid.New()
+	v.Parent = &recipe.RecipeRef{UUID: parentID}
+	return &v, nil
+}
+
+func (s *stubRecipeService) ExpandSubRecipes(_ context.Context, _ *recipe.Recipe) error {
+	return s.err
 }
 
 func (s *stubRecipeService) ListLabels(_ context.Context) ([]recipe.LabelSummary, error) {
 	return nil, nil
 }
+func (s *stubRecipeService) Taxonomy(_ context.Context) (recipe.Taxonomy, error) {
+	return s.taxonomy, s.err
+}
+func (s *stubRecipeService) SaveLabelType(_ context.Context, lt recipe.LabelType) (*recipe.LabelType, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	recipe.NormalizeLabelType(&lt)
+	if err := recipe.ValidateLabelType(lt); err != nil {
+		return nil, err
+	}
+	return &lt, nil
+}
+func (s *stubRecipeService) SaveLabel(_ context.Context, d recipe.LabelDefinition) (*recipe.LabelDefinition, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	recipe.NormalizeLabelDefinition(&d)
+	if err := s.taxonomy.ValidateLabelDefinition(d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+func (s *stubRecipeService) RenameLabel(_ context.Context, label recipe.Label, to string, dryRun bool) (*recipe.LabelChange, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	d, ok := s.taxonomy.Lookup(label.Type, label.Name)
+	if !ok {
+		return nil, recipe.LabelNotFoundError{Type: label.Type, Name: label.Name}
+	}
+	s.renamed = [2]string{label.Type + ":" + label.Name, to}
+	s.dryRun = dryRun
+	change := &recipe.LabelChange{From: label, Label: d, Recipes: []recipe.RecipeRef{}, DryRun: dryRun}
+	change.Label.Aliases = append(d.Aliases, d.Name)
+	change.Label.Name = to
+	return change, nil
+}
+func (s *stubRecipeService) MergeLabels(_ context.Context, from, into recipe.Label, dryRun bool) (*recipe.LabelChange, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	d, ok := s.taxonomy.Lookup(into.Type, into.Name)
+	if !ok {
+		return nil, recipe.LabelNotFoundError{Type: into.Type, Name: into.Name}
+	}
+	s.merged = [2]recipe.Label{from, into}
+	s.dryRun = dryRun
+	return &recipe.LabelChange{From: from, Label: d, Recipes: []recipe.RecipeRef{}, DryRun: dryRun}, nil
+}
 
-func (s *stubRecipeService) ListUnits(_ context.Context) ([]recipe.Unit, error) {
+func (s *stubRecipeService) ListUnits(_ context.Context) (recipe.UnitRegistry, error) {
 	return s.units, s.err
 }
 
@@ -65,6 +263,93 @@ func (s *stubRecipeService) ListIngredients(_ context.Context) ([]recipe.Ingredi
 	return s.ingredients, s.err
 }
 
+func (s *stubRecipeService) IngredientCatalogue(_ context.Context) (recipe.IngredientCatalogue, error) {
+	return s.catalogue, s.err
+}
+func (s *stubRecipeService) SetIngredientAliases(_ context.Context, name string, aliases []string) (*recipe.IngredientSummary, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	entry, ok := s.catalogue.Find(name)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: name}
+	}
+	entry.Aliases = recipe.NormalizeIngredientAliases(entry.Name, aliases)
+	if err := s.catalogue.CheckNames(entry.Name, entry.Aliases...); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+func (s *stubRecipeService) RenameIngredient(_ context.Context, name, to string) (*recipe.IngredientSummary, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	entry, ok := s.catalogue.Find(name)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: name}
+	}
+	entry.Name = to
+	return &entry, nil
+}
+func (s *stubRecipeService) MergeIngredients(_ context.Context, from, into string) (*recipe.IngredientSummary, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if _, ok := s.catalogue.Find(from); !ok {
+		return nil, recipe.IngredientNotFoundError{Name: from}
+	}
+	entry, ok := s.catalogue.Find(into)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: into}
+	}
+	return &entry, nil
+}
+
+func (s *stubRecipeService) SaveUnit(_ context.Context, u recipe.Unit) (*recipe.Unit, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	u = recipe.NormalizeUnit(u)
+	if err := recipe.ValidateUnit(u); err != nil {
+		return nil, err
+	}
+	if err := recipe.UnitRegistry(s.units).CheckNames(u.Name, u.Aliases...); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *stubRecipeService) MergeUnits(_ context.Context, from, into string) (*recipe.Unit, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if _, ok := recipe.UnitRegistry(s.units).Find(from); !ok {
+		return nil, recipe.UnitNotFoundError{Name: from}
+	}
+	u, ok := recipe.UnitRegistry(s.units).Find(into)
+	if !ok {
+		return nil, recipe.UnitNotFoundError{Name: into}
+	}
+	return &u, nil
+}
+
+func (s *stubRecipeService) UnitReviews(_ context.Context) ([]recipe.UnitReview, error) {
+	return s.unitReviews, s.err
+}
+
+func (s *stubRecipeService) DismissUnitReview(_ context.Context, name string) error {
+	if s.err != nil {
+		return s.err
+	}
+	for i, r := range s.unitReviews {
+		if r.Name == name {
+			s.unitReviews = append(s.unitReviews[:i], s.unitReviews[i+1:]...)
+			return nil
+		}
+	}
+	return recipe.UnitNotFoundError{Name: name}
+}
+
 // --- Tests ---
 
 func TestListUnits_Success(t *testing.T) {
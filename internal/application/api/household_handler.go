@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// GET /api/household - The people we cook for.
+func (h *RecipeHandler) ListHousehold(w http.ResponseWriter, r *http.Request) {
+	people, err := h.recipeService.ListPeople(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list household")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list household")
+		return
+	}
+	if people == nil {
+		people = []recipe.Person{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"people": people})
+}
+
+// PUT /api/household/{name} - Add a household member, or replace the one of
+// that name. The body carries their allergens and diets.
+func (h *RecipeHandler) SavePerson(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Allergens []recipe.DietFlag `json:"allergens"`
+		Diets     []string          `json:"diets"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	p := recipe.Person{Name: r.PathValue("name"), Allergens: body.Allergens, Diets: body.Diets}
+	saved, err := h.recipeService.SavePerson(r.Context(), p)
+	if errors.Is(err, recipe.ErrInvalidPerson) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_person", err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("person", p.Name).Msg("Failed to save person")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "person_save_failed", "Failed to save person")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+	h.logger.Info().Str("person", saved.Name).Msg("Household member saved")
+}
+
+// DELETE /api/household/{name}
+func (h *RecipeHandler) DeletePerson(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := h.recipeService.DeletePerson(r.Context(), name)
+	if errors.Is(err, recipe.ErrPersonNotFound) {
+		h.writeErrorResponse(w, http.StatusNotFound, "person_not_found", err.Error())
+		return
+	}
+	if errors.Is(err, recipe.ErrInvalidPerson) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_person", err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("person", name).Msg("Failed to delete person")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "person_delete_failed", "Failed to delete person")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("person", name).Msg("Household member removed")
+}
+
+// eatersFromQuery reads who a listing is for: ?for= takes comma-separated
+// household member names, or "everyone". Recipes any of them can't eat are
+// left out unless ?conflicts=flag, which keeps them and lists the clashes.
+func eatersFromQuery(r *http.Request) recipe.Eaters {
+	var names []string
+	for _, n := range strings.Split(r.URL.Query().Get("for"), ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return recipe.Eaters{
+		Names:   names,
+		Exclude: !strings.EqualFold(r.URL.Query().Get("conflicts"), "flag"),
+	}
+}
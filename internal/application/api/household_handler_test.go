@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestSavePerson(t *testing.T) {
+	svc := &stubRecipeService{}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/household/Sam", strings.NewReader(`{"allergens":["Gluten"],"diets":["vegetarian"]}`))
+	req.SetPathValue("name", "Sam")
+	rec := httptest.NewRecorder()
+	h.SavePerson(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(svc.people) != 1 || svc.people[0].Allergens[0] != recipe.FlagGluten {
+		t.Errorf("expected Sam to be saved allergic to gluten, got %+v", svc.people)
+	}
+}
+
+func TestSavePerson_UnknownAllergen(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/household/Sam", strings.NewReader(`{"allergens":["kryptonite"]}`))
+	req.SetPathValue("name", "Sam")
+	rec := httptest.NewRecorder()
+	h.SavePerson(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestDeletePerson_NotFound(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/household/Sam", nil)
+	req.SetPathValue("name", "Sam")
+	rec := httptest.NewRecorder()
+	h.DeletePerson(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestListRecipes_Eaters(t *testing.T) {
+	for query, want := range map[string]recipe.Eaters{
+		"/api/recipes":                           {Exclude: true},
+		"/api/recipes?for=Sam":                   {Names: []string{"Sam"}, Exclude: true},
+		"/api/recipes?for=everyone,%20Sam,":      {Names: []string{"everyone", "Sam"}, Exclude: true},
+		"/api/recipes?for=Sam&conflicts=flag":    {Names: []string{"Sam"}},
+		"/api/recipes?for=Sam&conflicts=exclude": {Names: []string{"Sam"}, Exclude: true},
+	} {
+		svc := &stubRecipeService{}
+		h := NewRecipeHandler(svc, &noopLogger{})
+		h.ListRecipes(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, query, nil))
+		if !reflect.DeepEqual(svc.eaters, want) {
+			t.Errorf("%s: eaters = %+v, want %+v", query, svc.eaters, want)
+		}
+	}
+}
+
+func TestListRecipes_UnknownEater(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{err: recipe.PersonNotFoundError{Name: "Sam"}}, &noopLogger{})
+
+	rec := httptest.NewRecorder()
+	h.ListRecipes(rec, httptest.NewRequest(http.MethodGet, "/api/recipes?for=Sam", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,331 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+)
+
+// listFormat is one of the renderings the shopping list and pantry endpoints
+// can negotiate. JSON is what the app uses; the rest are for pasting into
+// messages, spreadsheets and notes apps, or for the public share page.
+type listFormat string
+
+const (
+	formatJSON     listFormat = "json"
+	formatText     listFormat = "text"
+	formatCSV      listFormat = "csv"
+	formatMarkdown listFormat = "markdown"
+	formatHTML     listFormat = "html"
+)
+
+var listFormatContentTypes = map[listFormat]string{
+	formatJSON:     "application/json",
+	formatText:     "text/plain; charset=utf-8",
+	formatCSV:      "text/csv; charset=utf-8",
+	formatMarkdown: "text/markdown; charset=utf-8",
+	formatHTML:     "text/html; charset=utf-8",
+}
+
+// listFormatAliases are the ?format= values accepted as an override for the
+// Accept header, for links and clients that can't set headers.
+var listFormatAliases = map[string]listFormat{
+	"json":     formatJSON,
+	"txt":      formatText,
+	"text":     formatText,
+	"csv":      formatCSV,
+	"md":       formatMarkdown,
+	"markdown": formatMarkdown,
+	"html":     formatHTML,
+}
+
+// negotiateListFormat picks a rendering from offers, preferring ?format= and
+// then the Accept header (honouring q-values). With neither, the first offer
+// wins. ok is false when the client asked for something we can't produce.
+func negotiateListFormat(r *http.Request, offers ...listFormat) (listFormat, bool) {
+	if v := r.URL.Query().Get("format"); v != "" {
+		f, known := listFormatAliases[strings.ToLower(v)]
+		if !known {
+			return "", false
+		}
+		for _, o := range offers {
+			if o == f {
+				return f, true
+			}
+		}
+		return "", false
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	type mediaRange struct {
+		typ string
+		q   float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		typ, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, mediaRange{typ: typ, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, mr := range ranges {
+		for _, o := range offers {
+			ct, _, _ := mime.ParseMediaType(listFormatContentTypes[o])
+			if mr.typ == "*/*" || mr.typ == ct || mr.typ == strings.Split(ct, "/")[0]+"/*" {
+				return o, true
+			}
+		}
+	}
+	return "", false
+}
+
+// shoppingSourceHeadings label the groups on a rendered shopping list. A
+// source without a heading here is shown under its raw name.
+var shoppingSourceHeadings = map[string]string{
+	pantry.ShoppingSourceMealPlan:   "For the meal plan",
+	pantry.ShoppingSourceCustom:     "Extras",
+	pantry.ShoppingSourceIngredient: "Extras",
+}
+
+type shoppingListGroup struct {
+	Heading string
+	Items   []listLine
+}
+
+// listLine is one rendered line, ticked when it's checked off.
+type listLine struct {
+	Name    string
+	Checked bool
+}
+
+// groupShoppingList groups items by source heading, in the order the headings
+// first appear (the service already puts meal-plan items first). Sources that
+// share a heading share a group.
+func groupShoppingList(items []pantry.ShoppingListItem) []shoppingListGroup {
+	var groups []shoppingListGroup
+	index := map[string]int{}
+	for _, item := range items {
+		heading := shoppingSourceHeadings[item.Source]
+		if heading == "" {
+			heading = item.Source
+		}
+		i, seen := index[heading]
+		if !seen {
+			i = len(groups)
+			index[heading] = i
+			groups = append(groups, shoppingListGroup{Heading: heading})
+		}
+		groups[i].Items = append(groups[i].Items, listLine{Name: item.Name, Checked: item.Checked})
+	}
+	return groups
+}
+
+// aisleGroups turns aisle-ordered sections into rendered groups.
+func aisleGroups(sections []pantry.ShoppingSection) []shoppingListGroup {
+	groups := make([]shoppingListGroup, len(sections))
+	for i, s := range sections {
+		groups[i].Heading = s.Heading
+		for _, item := range s.Items {
+			groups[i].Items = append(groups[i].Items, listLine{Name: item.Name, Checked: item.Checked})
+		}
+	}
+	return groups
+}
+
+// writeShoppingList renders the shopping list in the given format, grouped by
+// source, or by aisle when sections is non-nil.
+func writeShoppingList(w http.ResponseWriter, format listFormat, items []pantry.ShoppingListItem, sections []pantry.ShoppingSection) {
+	w.Header().Set("Content-Type", listFormatContentTypes[format])
+	groups := groupShoppingList(items)
+	if sections != nil {
+		groups = aisleGroups(sections)
+	}
+
+	switch format {
+	case formatText:
+		fmt.Fprint(w, "Shopping list\n")
+		if len(groups) == 0 {
+			fmt.Fprint(w, "\nNothing to buy.\n")
+		}
+		for _, g := range groups {
+			fmt.Fprintf(w, "\n%s\n", g.Heading)
+			for _, line := range g.Items {
+				fmt.Fprintf(w, "%s %s\n", checkbox(line.Checked), line.Name)
+			}
+		}
+	case formatMarkdown:
+		fmt.Fprint(w, "# Shopping list\n")
+		if len(groups) == 0 {
+			fmt.Fprint(w, "\nNothing to buy.\n")
+		}
+		for _, g := range groups {
+			fmt.Fprintf(w, "\n## %s\n\n", g.Heading)
+			for _, line := range g.Items {
+				fmt.Fprintf(w, "- %s %s\n", checkbox(line.Checked), escapeMarkdown(line.Name))
+			}
+		}
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if sections == nil {
+			cw.Write([]string{"name", "source"})
+			for _, item := range items {
+				cw.Write([]string{item.Name, item.Source})
+			}
+		} else {
+			cw.Write([]string{"name", "source", "category"})
+			for _, s := range sections {
+				for _, item := range s.Items {
+					cw.Write([]string{item.Name, item.Source, item.Category})
+				}
+			}
+		}
+		cw.Flush()
+	case formatHTML:
+		sharedListPage.Execute(w, sharedListView{Title: "Shopping list", Empty: "Nothing to buy.", Groups: groups})
+	default:
+		body := map[string]any{
+			"items": items,
+			"total": len(items),
+		}
+		if sections != nil {
+			body["sections"] = sections
+		}
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// writePantry renders the pantry in the given format.
+func writePantry(w http.ResponseWriter, format listFormat, items []pantry.PantryItem) {
+	w.Header().Set("Content-Type", listFormatContentTypes[format])
+
+	switch format {
+	case formatText:
+		fmt.Fprint(w, "Pantry\n\n")
+		if len(items) == 0 {
+			fmt.Fprint(w, "Nothing in stock.\n")
+		}
+		for _, item := range items {
+			fmt.Fprintf(w, "- %s\n", item.Ingredient)
+		}
+	case formatMarkdown:
+		fmt.Fprint(w, "# Pantry\n\n")
+		if len(items) == 0 {
+			fmt.Fprint(w, "Nothing in stock.\n")
+		}
+		for _, item := range items {
+			fmt.Fprintf(w, "- %s\n", escapeMarkdown(item.Ingredient))
+		}
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"ingredient", "added_at"})
+		for _, item := range items {
+			cw.Write([]string{item.Ingredient, item.AddedAt.Format(time.RFC3339)})
+		}
+		cw.Flush()
+	case formatHTML:
+		lines := make([]listLine, len(items))
+		for i, item := range items {
+			lines[i] = listLine{Name: item.Ingredient}
+		}
+		view := sharedListView{Title: "Pantry", Empty: "Nothing in stock."}
+		if len(lines) > 0 {
+			view.Groups = []shoppingListGroup{{Items: lines}}
+		}
+		sharedListPage.Execute(w, view)
+	default:
+		json.NewEncoder(w).Encode(map[string]any{
+			"items": items,
+			"total": len(items),
+		})
+	}
+}
+
+// checkbox is the plain-text tick box for a line, as Markdown task lists
+// write it too.
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// escapeMarkdown stops item names from being read as formatting. Names are
+// short free text, so only the characters that matter inline are escaped.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"*", `\*`,
+		"_", `\_`,
+		"`", "\\`",
+		"[", `\[`,
+		"]", `\]`,
+	).Replace(s)
+}
+
+type sharedListView struct {
+	Title  string
+	Empty  string
+	Groups []shoppingListGroup
+}
+
+// sharedListPage is the read-only page a share link opens. Deliberately
+// self-contained: whoever opens it likely has nothing but a phone browser.
+var sharedListPage = template.Must(template.New("shared-list").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 32rem; margin: 1.5rem auto; padding: 0 1rem; line-height: 1.5; }
+h2 { font-size: 1rem; margin-top: 1.5rem; color: #456; }
+ul { list-style: none; padding: 0; }
+li { padding: 0.35rem 0; border-bottom: 1px solid #e4e8ee; }
+li::before { content: "\2610\00a0\00a0"; }
+li.checked { color: #789; text-decoration: line-through; }
+li.checked::before { content: "\2611\00a0\00a0"; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if not .Groups}}
+<p>{{.Empty}}</p>
+{{- end}}
+{{- range .Groups}}
+{{- if .Heading}}
+<h2>{{.Heading}}</h2>
+{{- end}}
+<ul>
+{{- range .Items}}
+<li{{if .Checked}} class="checked"{{end}}>{{.Name}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
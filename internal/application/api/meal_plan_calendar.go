@@ -0,0 +1,219 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// defaultDinnerTime is when a scheduled recipe should be on the table unless
+// the subscriber asks for something else via ?dinner=HH:MM.
+const defaultDinnerTime = "19:00"
+
+// mealPlanEventLength is how long a dated meal-plan event blocks out. It's the
+// meal itself, not the cooking; the alarm covers the cooking.
+const mealPlanEventLength = time.Hour
+
+// GET /api/meal-plan.ics - Subscribe-able calendar of the meal plan.
+//
+// Recipes scheduled for a day become an event ending at dinner time (floating,
+// so it follows the subscriber's timezone) with an alarm prep+cook minutes
+// before it, telling you when to start cooking. Unscheduled recipes become an
+// all-day event on the day they were added, without an alarm.
+//
+// Query params: dinner=HH:MM (default 19:00), alarms=false to drop alarms.
+func (h *RecipeHandler) MealPlanCalendar(w http.ResponseWriter, r *http.Request) {
+	dinnerParam := r.URL.Query().Get("dinner")
+	if dinnerParam == "" {
+		dinnerParam = defaultDinnerTime
+	}
+	dinner, err := time.Parse("15:04", dinnerParam)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_dinner_time", "dinner must be formatted HH:MM")
+		return
+	}
+
+	alarms := true
+	if v := r.URL.Query().Get("alarms"); v != "" {
+		alarms, err = strconv.ParseBool(v)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid_alarms", "alarms must be true or false")
+			return
+		}
+	}
+
+	recipes, err := h.recipeService.ListMealPlanRecipes(r.Context(), nil)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list meal plan recipes for calendar")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list meal plan recipes")
+		return
+	}
+
+	cal := mealPlanCalendar{
+		baseURL: requestBaseURL(r),
+		dinner:  dinner,
+		alarms:  alarms,
+		now:     time.Now().UTC(),
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="meal-plan.ics"`)
+	w.Write([]byte(cal.render(recipes)))
+}
+
+// requestBaseURL rebuilds the scheme and host the client used to reach us,
+// honouring the usual reverse-proxy headers, so links in the feed point back
+// at something the subscriber can open.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	host := r.Host
+	if fwd := r.Header.Get("X-Forwarded-Host"); fwd != "" {
+		host = strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+	return scheme + "://" + host
+}
+
+// mealPlanCalendar renders meal-plan recipes as an RFC 5545 VCALENDAR.
+type mealPlanCalendar struct {
+	baseURL string
+	dinner  time.Time // only the clock part is used
+	alarms  bool
+	now     time.Time
+}
+
+func (c mealPlanCalendar) render(recipes []*recipe.Recipe) string {
+	var b strings.Builder
+	line := func(name, value string) { writeICalLine(&b, name+":"+value) }
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//the-bluer-book//meal plan//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "Meal plan")
+
+	for _, rec := range recipes {
+		link := c.baseURL + "/api/recipes/" + rec.UUID.String()
+		total := rec.PrepTime + rec.CookTime
+
+		line("BEGIN", "VEVENT")
+		line("UID", rec.UUID.String()+"@the-bluer-book")
+		line("DTSTAMP", c.now.Format("20060102T150405Z"))
+		line("SUMMARY", escapeICalText(rec.Name))
+
+		if rec.PlannedFor != nil {
+			day := *rec.PlannedFor
+			start := time.Date(day.Year(), day.Month(), day.Day(), c.dinner.Hour(), c.dinner.Minute(), 0, 0, time.UTC)
+			// Floating local time: no Z, no TZID.
+			line("DTSTART", start.Format("20060102T150405"))
+			line("DTEND", start.Add(mealPlanEventLength).Format("20060102T150405"))
+		} else {
+			day := rec.MealPlanAddedAt
+			line("DTSTART;VALUE=DATE", day.Format("20060102"))
+			line("DTEND;VALUE=DATE", day.AddDate(0, 0, 1).Format("20060102"))
+		}
+
+		line("DESCRIPTION", escapeICalText(mealPlanEventDescription(rec, link)))
+		line("URL", link)
+
+		if c.alarms && rec.PlannedFor != nil && total > 0 {
+			line("BEGIN", "VALARM")
+			line("ACTION", "DISPLAY")
+			line("DESCRIPTION", escapeICalText("Start cooking "+rec.Name))
+			line("TRIGGER", fmt.Sprintf("-PT%dM", total))
+			line("END", "VALARM")
+		}
+
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// mealPlanEventDescription is the plain-text body of an event: timings, the
+// ingredient list, and where the recipe lives.
+func mealPlanEventDescription(rec *recipe.Recipe, link string) string {
+	var b strings.Builder
+	if total := rec.PrepTime + rec.CookTime; total > 0 {
+		fmt.Fprintf(&b, "Total time: %s (prep %d min, cook %d min)\n", formatMinutes(total), rec.PrepTime, rec.CookTime)
+	}
+	if rec.Servings > 0 {
+		fmt.Fprintf(&b, "Serves %d\n", rec.Servings)
+	}
+	if len(rec.Ingredients) > 0 {
+		b.WriteString("\nIngredients:\n")
+		for _, ri := range rec.Ingredients {
+			b.WriteString("- " + ri.Text() + "\n")
+		}
+	}
+	b.WriteString("\nRecipe: " + link)
+	if rec.Url != "" {
+		b.WriteString("\nSource: " + rec.Url)
+	}
+	return b.String()
+}
+
+func formatMinutes(m int32) string {
+	if m < 60 {
+		return fmt.Sprintf("%d min", m)
+	}
+	if m%60 == 0 {
+		return fmt.Sprintf("%d h", m/60)
+	}
+	return fmt.Sprintf("%d h %d min", m/60, m%60)
+}
+
+// escapeICalText escapes a TEXT value per RFC 5545 §3.3.11.
+func escapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeICalLine writes one content line, folded at 75 octets (RFC 5545
+// §3.1) without splitting a UTF-8 sequence, and terminated with CRLF.
+func writeICalLine(b *strings.Builder, s string) {
+	const limit = 75
+	first := true
+	for len(s) > 0 {
+		max := limit
+		if !first {
+			max-- // continuation lines start with a space
+		}
+		if len(s) <= max {
+			if !first {
+				b.WriteByte(' ')
+			}
+			b.WriteString(s)
+			break
+		}
+		cut := max
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if !first {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n")
+		s = s[cut:]
+		first = false
+	}
+	b.WriteString("\r\n")
+}
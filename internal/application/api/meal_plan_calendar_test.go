@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestMealPlanCalendar_ScheduledRecipe(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	svc := &stubRecipeService{mealPlan: []*recipe.Recipe{{
+		UUID:       id,
+		Name:       "Dhal, with rice",
+		PrepTime:   10,
+		CookTime:   35,
+		PlannedFor: &day,
+		Ingredients: []recipe.RecipeIngredient{
+			{Ingredient: recipe.Ingredient{Name: "red lentils"}, Unit: recipe.Unit{Name: "grams", Abbreviation: "g"}, Quantity: 250},
+			{Ingredient: recipe.Ingredient{Name: "onion"}, Quantity: 1, Preparation: "diced"},
+		},
+	}}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/meal-plan.ics?dinner=18:30", nil)
+	req.Host = "book.example"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rec := httptest.NewRecorder()
+	h.MealPlanCalendar(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("expected text/calendar, got %q", ct)
+	}
+
+	body := rec.Body.String()
+	unfolded := strings.ReplaceAll(body, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:" + id.String() + "@the-bluer-book\r\n",
+		"SUMMARY:Dhal\\, with rice\r\n",
+		"DTSTART:20260314T183000\r\n",
+		"DTEND:20260314T193000\r\n",
+		"URL:https://book.example/api/recipes/" + id.String() + "\r\n",
+		"TRIGGER:-PT45M\r\n",
+		"- 250 g red lentils\\n",
+		"- 1 onion\\, diced\\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("calendar missing %q\n%s", want, body)
+		}
+	}
+
+	for _, l := range strings.Split(body, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line longer than 75 octets: %q", l)
+		}
+	}
+}
+
+func TestMealPlanCalendar_UnscheduledRecipeIsAllDayWithoutAlarm(t *testing.T) {
+	svc := &stubRecipeService{mealPlan: []*recipe.Recipe{{
+		UUID:            uuid.New(),
+		Name:            "Soup",
+		CookTime:        30,
+		MealPlanAddedAt: time.Date(2026, 3, 10, 21, 15, 0, 0, time.UTC),
+	}}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	rec := httptest.NewRecorder()
+	h.MealPlanCalendar(rec, httptest.NewRequest(http.MethodGet, "/api/meal-plan.ics", nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "DTSTART;VALUE=DATE:20260310\r\n") || !strings.Contains(body, "DTEND;VALUE=DATE:20260311\r\n") {
+		t.Errorf("expected all-day event on the day it was added\n%s", body)
+	}
+	if strings.Contains(body, "VALARM") {
+		t.Errorf("unscheduled recipe should not have an alarm\n%s", body)
+	}
+}
+
+func TestMealPlanCalendar_AlarmsCanBeDisabled(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	svc := &stubRecipeService{mealPlan: []*recipe.Recipe{{
+		UUID: uuid.New(), Name: "Stew", CookTime: 120, PlannedFor: &day,
+	}}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	rec := httptest.NewRecorder()
+	h.MealPlanCalendar(rec, httptest.NewRequest(http.MethodGet, "/api/meal-plan.ics?alarms=false", nil))
+
+	if strings.Contains(rec.Body.String(), "VALARM") {
+		t.Errorf("expected no alarms\n%s", rec.Body.String())
+	}
+}
+
+func TestMealPlanCalendar_BadDinnerTime(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	rec := httptest.NewRecorder()
+	h.MealPlanCalendar(rec, httptest.NewRequest(http.MethodGet, "/api/meal-plan.ics?dinner=late", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestMealPlanCalendar_ServiceError(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{err: errors.New("db down")}, &noopLogger{})
+
+	rec := httptest.NewRecorder()
+	h.MealPlanCalendar(rec, httptest.NewRequest(http.MethodGet, "/api/meal-plan.ics", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+}
+
+func TestWriteICalLine_FoldsWithoutSplittingRunes(t *testing.T) {
+	var b strings.Builder
+	writeICalLine(&b, "SUMMARY:"+strings.Repeat("é", 80))
+
+	for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line longer than 75 octets: %d", len(l))
+		}
+		if !strings.HasPrefix(l, "SUMMARY:") && !strings.HasPrefix(l, " ") {
+			t.Errorf("continuation line should start with a space: %q", l)
+		}
+	}
+	if got := strings.ReplaceAll(b.String(), "\r\n ", ""); got != "SUMMARY:"+strings.Repeat("é", 80)+"\r\n" {
+		t.Errorf("unfolding did not round-trip: %q", got)
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// RevisionSource tags every request's context with where changes made through
+// it come from, so recipe revisions record their author.
+func RevisionSource(source string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(recipe.WithRevisionSource(r.Context(), source)))
+	})
+}
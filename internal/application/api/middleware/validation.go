@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
 )
@@ -14,6 +15,10 @@ type contextKey string
 
 const ValidatedRecipeKey contextKey = "validatedRecipe"
 
+// maxStepSectionLength bounds a step's section heading, which is meant to be
+// a short phase title rather than more instructions.
+const maxStepSectionLength = 100
+
 type ValidationMiddleware struct {
 	logger logger.Logger
 }
@@ -58,13 +63,44 @@ func (m *ValidationMiddleware) ValidateCreateRecipe(next http.Handler) http.Hand
 				m.writeValidationError(w, "missing_step_description", "Step description is required")
 				return
 			}
+			if len(step.Section) > maxStepSectionLength {
+				m.writeValidationError(w, "invalid_step_section", fmt.Sprintf("Step sections must be at most %d characters", maxStepSectionLength))
+				return
+			}
+			for _, timer := range step.Timers {
+				if timer.Seconds <= 0 || (timer.MaxSeconds != 0 && timer.MaxSeconds < timer.Seconds) {
+					m.writeValidationError(w, "invalid_timer", "Timers need a positive duration, and a range must not end before it starts")
+					return
+				}
+			}
+			for _, temp := range step.Temperatures {
+				if temp.Unit != recipe.TemperatureCelsius && temp.Unit != recipe.TemperatureFahrenheit && temp.Unit != recipe.TemperatureGasMark {
+					m.writeValidationError(w, "invalid_temperature_unit", "Temperature unit must be C, F or gas")
+					return
+				}
+			}
+			for _, si := range step.Ingredients {
+				if si.Line < 0 || si.Line >= len(rec.Ingredients) {
+					m.writeValidationError(w, "invalid_step_ingredient", "Step ingredients must refer to a line in the recipe's ingredients")
+					return
+				}
+			}
 			// Update step order to match index + 1 if not properly ordered
 			rec.Steps[i].Order = int16(i + 1)
 		}
 
 		// Validate ingredients have required fields
 		for _, ingredient := range rec.Ingredients {
-			if ingredient.Ingredient.Name == "" {
+			if ingredient.SubRecipe != nil {
+				if ingredient.SubRecipe.UUID == uuid.Nil {
+					m.writeValidationError(w, "missing_sub_recipe_id", "Sub-recipe lines need the UUID of the recipe they use")
+					return
+				}
+				if _, ok := recipe.NormalizeSubRecipeUnit(ingredient.Unit.Name); !ok {
+					m.writeValidationError(w, "invalid_sub_recipe_unit", "Sub-recipe quantities must be in servings or batches")
+					return
+				}
+			} else if ingredient.Ingredient.Name == "" {
 				m.writeValidationError(w, "missing_ingredient_name", "Ingredient name is required")
 				return
 			}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/rs/zerolog"
 )
@@ -129,6 +131,78 @@ func TestValidation_NegativeQuantity(t *testing.T) {
 	assertErrorCode(t, rec, "invalid_quantity")
 }
 
+func TestValidation_SubRecipeLine(t *testing.T) {
+	r := validRecipe()
+	r.Ingredients = append(r.Ingredients, recipe.RecipeIngredient{
+		Unit:      recipe.Unit{Name: "batch"},
+		Quantity:  1,
+		SubRecipe: &recipe.SubRecipe{UUID: uuid.New()},
+	})
+	rec := postRecipe(t, r)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a sub-recipe line without a name, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestValidation_SubRecipeBadUnit(t *testing.T) {
+	r := validRecipe()
+	r.Ingredients = append(r.Ingredients, recipe.RecipeIngredient{
+		Unit:      recipe.Unit{Name: "g"},
+		Quantity:  500,
+		SubRecipe: &recipe.SubRecipe{UUID: uuid.New()},
+	})
+	rec := postRecipe(t, r)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a sub-recipe in grams, got %d", rec.Code)
+	}
+	assertErrorCode(t, rec, "invalid_sub_recipe_unit")
+}
+
+func TestValidation_InvalidTimer(t *testing.T) {
+	r := validRecipe()
+	r.Steps[0].Timers = []recipe.Timer{{Label: "boil", Seconds: 600, MaxSeconds: 300}}
+	rec := postRecipe(t, r)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a backwards timer range, got %d", rec.Code)
+	}
+	assertErrorCode(t, rec, "invalid_timer")
+}
+
+func TestValidation_InvalidTemperatureUnit(t *testing.T) {
+	r := validRecipe()
+	r.Steps[0].Temperatures = []recipe.Temperature{{Value: 200, Unit: "kelvin"}}
+	rec := postRecipe(t, r)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown temperature unit, got %d", rec.Code)
+	}
+	assertErrorCode(t, rec, "invalid_temperature_unit")
+}
+
+func TestValidation_StepIngredientOutOfRange(t *testing.T) {
+	r := validRecipe()
+	r.Steps[0].Ingredients = []recipe.StepIngredient{{Line: len(r.Ingredients)}}
+	rec := postRecipe(t, r)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a step linking a missing line, got %d", rec.Code)
+	}
+	assertErrorCode(t, rec, "invalid_step_ingredient")
+}
+
+func TestValidation_StepSections(t *testing.T) {
+	r := validRecipe()
+	r.Steps[0].Section = "Make the sauce"
+	if rec := postRecipe(t, r); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a step with a section, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	r.Steps[0].Section = strings.Repeat("x", maxStepSectionLength+1)
+	rec := postRecipe(t, r)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an overlong section, got %d", rec.Code)
+	}
+	assertErrorCode(t, rec, "invalid_step_section")
+}
+
 func assertErrorCode(t *testing.T, rec *httptest.ResponseRecorder, want string) {
 	t.Helper()
 	var body struct {
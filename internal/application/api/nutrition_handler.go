@@ -0,0 +1,33 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// GET /api/recipes/{id}/nutrition - Estimated nutrition, in total and per
+// serving, with the percentage of ingredient lines the estimate covers and
+// why the rest were left out.
+func (h *RecipeHandler) GetNutrition(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	est, err := h.recipeService.Nutrition(r.Context(), recipeID)
+	if err != nil {
+		if errors.Is(err, recipe.ErrRecipeNotFound) {
+			h.writeErrorResponse(w, http.StatusNotFound, "recipe_not_found", "Recipe not found")
+			return
+		}
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to estimate nutrition")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "nutrition_failed", "Failed to estimate nutrition")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(est)
+}
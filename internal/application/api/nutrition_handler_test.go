@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestGetNutrition(t *testing.T) {
+	svc := &stubRecipeService{nutrition: &recipe.NutritionEstimate{
+		Total:     recipe.Nutrients{Calories: 976},
+		Coverage:  75,
+		Unmatched: []recipe.UnmatchedIngredient{{Ingredient: "coriander", Reason: "no nutrition data"}},
+	}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/nutrition", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.GetNutrition(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got recipe.NutritionEstimate
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.Total.Calories != 976 || got.Coverage != 75 || len(got.Unmatched) != 1 {
+		t.Errorf("unexpected estimate: %+v", got)
+	}
+}
+
+func TestGetNutrition_NotFound(t *testing.T) {
+	svc := &stubRecipeService{err: recipe.RecipeNotFoundError{ID: uuid.MustParse(revisionTestRecipeID)}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/nutrition", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.GetNutrition(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry/service"
@@ -53,8 +54,23 @@ func (h *PantryHandler) writeErrorResponse(w http.ResponseWriter, statusCode int
 	})
 }
 
-// GET /api/pantry - List everything currently in the pantry
+// exportFormats are what the list endpoints negotiate, JSON first so clients
+// that don't ask get what they always got.
+var exportFormats = []listFormat{formatJSON, formatText, formatCSV, formatMarkdown}
+
+func (h *PantryHandler) writeNotAcceptable(w http.ResponseWriter) {
+	h.writeErrorResponse(w, http.StatusNotAcceptable, "not_acceptable", "Supported formats: application/json, text/plain, text/csv, text/markdown")
+}
+
+// GET /api/pantry - List everything currently in the pantry. Negotiates JSON,
+// text/plain, text/csv and text/markdown (Accept header or ?format=).
 func (h *PantryHandler) ListPantry(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateListFormat(r, exportFormats...)
+	if !ok {
+		h.writeNotAcceptable(w)
+		return
+	}
+
 	items, err := h.pantryService.ListPantry(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to list pantry")
@@ -62,56 +78,205 @@ func (h *PantryHandler) ListPantry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]any{
-		"items": items,
-		"total": len(items),
-	})
+	writePantry(w, format, items)
 }
 
 // GET /api/shopping-list - Everything to buy: meal-plan ingredients not yet in
 // the pantry, plus any free-text custom items the user added or scanned.
+// Negotiates the same formats as the pantry; text renderings are a checklist
+// grouped by source. ?group=aisle groups by grocery category instead, in the
+// store layout's walking order, and JSON then adds "sections".
 func (h *PantryHandler) ShoppingList(w http.ResponseWriter, r *http.Request) {
-	items, err := h.pantryService.ShoppingList(r.Context())
+	format, ok := negotiateListFormat(r, exportFormats...)
+	if !ok {
+		h.writeNotAcceptable(w)
+		return
+	}
+
+	byAisle := false
+	switch group := r.URL.Query().Get("group"); group {
+	case "", "source":
+	case "aisle":
+		byAisle = true
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_group", "group must be source or aisle")
+		return
+	}
+
+	h.writeShoppingList(w, r, format, byAisle)
+}
+
+// writeShoppingList builds the shopping list and renders it, in sections by
+// aisle when byAisle is set. Items are then listed in walking order too.
+func (h *PantryHandler) writeShoppingList(w http.ResponseWriter, r *http.Request, format listFormat, byAisle bool) {
+	var items []pantry.ShoppingListItem
+	var sections []pantry.ShoppingSection
+	var err error
+	if byAisle {
+		sections, err = h.pantryService.ShoppingAisles(r.Context())
+		for _, s := range sections {
+			items = append(items, s.Items...)
+		}
+		if sections == nil {
+			sections = []pantry.ShoppingSection{}
+		}
+	} else {
+		items, err = h.pantryService.ShoppingList(r.Context())
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to build shopping list")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "shopping_list_failed", "Failed to build shopping list")
 		return
 	}
-
 	if items == nil {
 		items = []pantry.ShoppingListItem{}
 	}
 
+	writeShoppingList(w, format, items, sections)
+}
+
+// POST /api/shopping-list/share - Mint a short-lived public link to a
+// read-only view of the shopping list. Optional body: {"expiresInHours": 48}.
+func (h *PantryHandler) ShareShoppingList(w http.ResponseWriter, r *http.Request) {
+	h.shareList(w, r, pantry.ListShoppingList)
+}
+
+// POST /api/pantry/share - As above, for the pantry.
+func (h *PantryHandler) SharePantry(w http.ResponseWriter, r *http.Request) {
+	h.shareList(w, r, pantry.ListPantry)
+}
+
+func (h *PantryHandler) shareList(w http.ResponseWriter, r *http.Request, list string) {
+	var body struct {
+		ExpiresInHours float64 `json:"expiresInHours"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	if body.ExpiresInHours < 0 {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_expiry", "expiresInHours must be positive")
+		return
+	}
+
+	ttl := time.Duration(body.ExpiresInHours * float64(time.Hour))
+	share, err := h.pantryService.ShareList(r.Context(), list, ttl)
+	if err != nil {
+		h.logger.Error().Err(err).Str("list", list).Msg("Failed to share list")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "share_failed", "Failed to create share link")
+		return
+	}
+
+	h.logger.Info().Str("list", list).Time("expires_at", share.ExpiresAt).Msg("List shared")
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]any{
-		"items": items,
-		"total": len(items),
+		"url":       requestBaseURL(r) + "/share/" + share.Token,
+		"list":      share.List,
+		"expiresAt": share.ExpiresAt,
 	})
 }
 
-// POST /api/shopping-list - Add a free-text custom item, e.g. {"name": "washing-up liquid"}
-func (h *PantryHandler) AddCustomShoppingItem(w http.ResponseWriter, r *http.Request) {
+// GET /share/{token} - Public, read-only view of a shared list. Renders an
+// HTML page by default and negotiates the export formats too. Unknown and
+// expired tokens are both a plain 404.
+func (h *PantryHandler) ViewShare(w http.ResponseWriter, r *http.Request) {
+	format, ok := negotiateListFormat(r, formatHTML, formatText, formatMarkdown, formatCSV, formatJSON)
+	if !ok {
+		h.writeNotAcceptable(w)
+		return
+	}
+
+	share, err := h.pantryService.GetShare(r.Context(), r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, pantry.ErrShareNotFound) {
+			h.writeErrorResponse(w, http.StatusNotFound, "share_not_found", "This link has expired or doesn't exist")
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to resolve share link")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "share_failed", "Failed to load shared list")
+		return
+	}
+
+	// Shared pages are someone's shopping, not something to index or cache
+	// past the link's lifetime.
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	switch share.List {
+	case pantry.ListPantry:
+		items, err := h.pantryService.ListPantry(r.Context())
+		if err != nil {
+			h.logger.Error().Err(err).Msg("Failed to list pantry for share link")
+			h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list pantry")
+			return
+		}
+		writePantry(w, format, items)
+	default:
+		h.writeShoppingList(w, r, format, r.URL.Query().Get("group") == "aisle")
+	}
+}
+
+// POST /api/shopping-list - Add an item, e.g. {"name": "tomatoes"}. The name
+// is resolved against the known ingredients and the result returned: what was
+// added, and whether the name needs review. Exact, case, plural and alias
+// matches resolve; a close match never does, even a lone one-letter miss
+// ("beer" for beef), and comes back for review instead. Settle a review with
+// {"ingredient": "Cherry tomatoes"} to add one of its candidates, or with
+// {"name": "tomatoes", "custom": true} to add the name as free text.
+func (h *PantryHandler) AddShoppingItem(w http.ResponseWriter, r *http.Request) {
 	var body struct {
-		Name string `json:"name"`
+		Name       string `json:"name"`
+		Ingredient string `json:"ingredient"`
+		Custom     bool   `json:"custom"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
 		return
 	}
+
+	if strings.TrimSpace(body.Ingredient) != "" {
+		name, err := h.pantryService.AddIngredientShoppingItem(r.Context(), body.Ingredient)
+		if err != nil {
+			if errors.Is(err, pantry.ErrIngredientNotFound) {
+				h.writeErrorResponse(w, http.StatusNotFound, "ingredient_not_found", "No such ingredient")
+				return
+			}
+			h.logger.Error().Err(err).Str("ingredient", body.Ingredient).Msg("Failed to add ingredient to shopping list")
+			h.writeErrorResponse(w, http.StatusInternalServerError, "shopping_add_failed", "Failed to add item to shopping list")
+			return
+		}
+		h.logger.Info().Str("ingredient", name).Msg("Ingredient added to shopping list")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	if strings.TrimSpace(body.Name) == "" {
 		h.writeErrorResponse(w, http.StatusBadRequest, "missing_name", "Item name is required")
 		return
 	}
 
-	if err := h.pantryService.AddCustomShoppingItem(r.Context(), body.Name); err != nil {
-		h.logger.Error().Err(err).Str("name", body.Name).Msg("Failed to add custom shopping list item")
+	if body.Custom {
+		if err := h.pantryService.AddCustomShoppingItem(r.Context(), body.Name); err != nil {
+			h.logger.Error().Err(err).Str("name", body.Name).Msg("Failed to add custom shopping list item")
+			h.writeErrorResponse(w, http.StatusInternalServerError, "shopping_add_failed", "Failed to add item to shopping list")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		h.logger.Info().Str("name", body.Name).Msg("Custom shopping list item added")
+		return
+	}
+
+	additions, err := h.pantryService.AddShoppingItems(r.Context(), []string{body.Name})
+	if err != nil {
+		h.logger.Error().Err(err).Str("name", body.Name).Msg("Failed to add shopping list item")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "shopping_add_failed", "Failed to add item to shopping list")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
-	h.logger.Info().Str("name", body.Name).Msg("Custom shopping list item added")
+	h.logger.Info().Str("name", body.Name).Int("needs_review", len(additions.NeedsReview)).Msg("Shopping list item added")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(additions)
 }
 
 // DELETE /api/shopping-list/{name} - Remove a custom item from the shopping list
@@ -133,8 +298,9 @@ func (h *PantryHandler) RemoveCustomShoppingItem(w http.ResponseWriter, r *http.
 }
 
 // POST /api/shopping-list/scan - Upload a photo of a physical shopping list;
-// Gemini parses the items and they're added as custom items. Returns the names
-// that were added.
+// Gemini parses the items and they're added as POST /api/shopping-list adds
+// them, resolved against the known ingredients. Returns what was added and
+// the names that need review.
 func (h *PantryHandler) ScanShoppingList(w http.ResponseWriter, r *http.Request) {
 	if h.scanner == nil {
 		h.writeErrorResponse(w, http.StatusServiceUnavailable, "scan_unavailable", "Photo scanning is not configured")
@@ -176,42 +342,47 @@ func (h *PantryHandler) ScanShoppingList(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	added := make([]string, 0, len(names))
-	for _, name := range names {
-		if err := h.pantryService.AddCustomShoppingItem(r.Context(), name); err != nil {
-			h.logger.Error().Err(err).Str("name", name).Msg("Failed to add scanned shopping list item")
-			continue
-		}
-		added = append(added, name)
+	additions, err := h.pantryService.AddShoppingItems(r.Context(), names)
+	if err != nil {
+		h.logger.Error().Err(err).Int("scanned", len(names)).Msg("Failed to add scanned shopping list items")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "shopping_add_failed", "Failed to add scanned items to shopping list")
+		return
 	}
 
-	h.logger.Info().Int("added", len(added)).Msg("Scanned shopping list photo")
+	h.logger.Info().Int("added", len(additions.Added)).Int("needs_review", len(additions.NeedsReview)).Msg("Scanned shopping list photo")
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]any{
-		"added": added,
-		"total": len(added),
+		"added":        additions.Added,
+		"needs_review": additions.NeedsReview,
+		"total":        len(additions.Added),
 	})
 }
 
-// PUT /api/pantry/{ingredient} - Mark an ingredient as in the pantry (idempotent)
+// PUT /api/pantry/{ingredient} - Mark an ingredient as in the pantry
+// (idempotent). A name no recipe uses yet becomes a new pantry-only
+// ingredient: 201 with its name, rather than 204.
 func (h *PantryHandler) AddToPantry(w http.ResponseWriter, r *http.Request) {
 	ingredient, ok := h.ingredientFromPath(w, r)
 	if !ok {
 		return
 	}
 
-	if err := h.pantryService.AddToPantry(r.Context(), ingredient); err != nil {
-		if errors.Is(err, pantry.ErrIngredientNotFound) {
-			h.writeErrorResponse(w, http.StatusNotFound, "ingredient_not_found", "No such ingredient")
-			return
-		}
+	name, created, err := h.pantryService.AddToPantry(r.Context(), ingredient)
+	if err != nil {
 		h.logger.Error().Err(err).Str("ingredient", ingredient).Msg("Failed to add ingredient to pantry")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "pantry_add_failed", "Failed to add ingredient to pantry")
 		return
 	}
 
+	if created {
+		h.logger.Info().Str("ingredient", name).Msg("New pantry-only ingredient added to pantry")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"ingredient": name, "pantryOnly": true})
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
-	h.logger.Info().Str("ingredient", ingredient).Msg("Ingredient added to pantry")
+	h.logger.Info().Str("ingredient", name).Msg("Ingredient added to pantry")
 }
 
 // DELETE /api/pantry/{ingredient} - Remove an ingredient from the pantry
@@ -234,3 +405,110 @@ func (h *PantryHandler) RemoveFromPantry(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 	h.logger.Info().Str("ingredient", ingredient).Msg("Ingredient removed from pantry")
 }
+
+// PUT /api/ingredients/{ingredient}/category - File an ingredient under a
+// grocery category, e.g. {"category": "dairy"}. An empty category clears it,
+// so the shopping list guesses again.
+func (h *PantryHandler) SetIngredientCategory(w http.ResponseWriter, r *http.Request) {
+	ingredient, ok := h.ingredientFromPath(w, r)
+	if !ok {
+		return
+	}
+	category, ok := h.categoryFromBody(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pantryService.SetIngredientCategory(r.Context(), ingredient, category); err != nil {
+		h.writeCategoryError(w, err, "categorise_failed", "Failed to set ingredient category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("ingredient", ingredient).Str("category", category).Msg("Ingredient category set")
+}
+
+// PUT /api/shopping-list/{name}/category - As above, for a custom item
+func (h *PantryHandler) SetShoppingItemCategory(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "missing_name", "Item name is required")
+		return
+	}
+	category, ok := h.categoryFromBody(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pantryService.SetShoppingItemCategory(r.Context(), name, category); err != nil {
+		h.writeCategoryError(w, err, "categorise_failed", "Failed to set shopping list item category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("name", name).Str("category", category).Msg("Shopping list item category set")
+}
+
+// GET /api/store-layout - The walking order through the shop: every grocery
+// category, in the order the aisle-grouped shopping list uses.
+func (h *PantryHandler) GetStoreLayout(w http.ResponseWriter, r *http.Request) {
+	order, err := h.pantryService.StoreLayout(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to load store layout")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "store_layout_failed", "Failed to load store layout")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"categories": order})
+}
+
+// PUT /api/store-layout - Set the walking order, e.g. {"categories":
+// ["bakery", "produce", "dairy"]}. Categories left out follow in their
+// default order; "other" is always last.
+func (h *PantryHandler) SaveStoreLayout(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Categories []string `json:"categories"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	order, err := h.pantryService.SaveStoreLayout(r.Context(), body.Categories)
+	if err != nil {
+		h.writeCategoryError(w, err, "store_layout_failed", "Failed to save store layout")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"categories": order})
+	h.logger.Info().Strs("categories", order).Msg("Store layout saved")
+}
+
+// categoryFromBody reads {"category": ...}. It writes an error response and
+// returns ok=false on a malformed body.
+func (h *PantryHandler) categoryFromBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var body struct {
+		Category string `json:"category"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return "", false
+	}
+	return body.Category, true
+}
+
+func (h *PantryHandler) writeCategoryError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, pantry.ErrInvalidCategory):
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_category", err.Error())
+	case errors.Is(err, pantry.ErrIngredientNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "ingredient_not_found", "No such ingredient")
+	case errors.Is(err, pantry.ErrShoppingItemNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "item_not_found", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
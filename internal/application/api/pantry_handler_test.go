@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
 )
@@ -15,21 +16,32 @@ import (
 // --- Stub ---
 
 type stubPantryService struct {
-	items         []pantry.PantryItem
-	shopping      []pantry.ShoppingListItem
-	err           error
-	added         []string
-	removed       []string
-	customAdded   []string
-	customRemoved []string
+	items            []pantry.PantryItem
+	shopping         []pantry.ShoppingListItem
+	err              error
+	added            []string
+	removed          []string
+	customAdded      []string
+	customRemoved    []string
+	reviews          map[string][]string
+	checked          map[string]bool
+	staples          map[string]bool
+	newIngredient    bool
+	out              []string
+	trips            []pantry.ShoppingTrip
+	ingredientsAdded []string
+	share            *pantry.ListShare
+	sharedTTL        time.Duration
+	layout           pantry.StoreLayout
+	categorised      map[string]string
 }
 
-func (s *stubPantryService) AddToPantry(_ context.Context, ingredient string) error {
+func (s *stubPantryService) AddToPantry(_ context.Context, ingredient string) (string, bool, error) {
 	if s.err != nil {
-		return s.err
+		return "", false, s.err
 	}
 	s.added = append(s.added, ingredient)
-	return nil
+	return ingredient, s.newIngredient, nil
 }
 
 func (s *stubPantryService) RemoveFromPantry(_ context.Context, ingredient string) error {
@@ -44,6 +56,35 @@ func (s *stubPantryService) ListPantry(_ context.Context) ([]pantry.PantryItem,
 	return s.items, s.err
 }
 
+func (s *stubPantryService) Staples(context.Context) ([]pantry.Staple, error) {
+	staples := []pantry.Staple{}
+	for name, staple := range s.staples {
+		if staple {
+			staples = append(staples, pantry.Staple{Ingredient: name})
+		}
+	}
+	return staples, s.err
+}
+
+func (s *stubPantryService) SetStaple(_ context.Context, ingredient string, staple bool) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if s.staples == nil {
+		s.staples = map[string]bool{}
+	}
+	s.staples[ingredient] = staple
+	return ingredient, nil
+}
+
+func (s *stubPantryService) MarkOut(_ context.Context, ingredient string) (string, bool, error) {
+	if s.err != nil {
+		return "", false, s.err
+	}
+	s.out = append(s.out, ingredient)
+	return ingredient, s.staples[ingredient], nil
+}
+
 func (s *stubPantryService) ShoppingList(_ context.Context) ([]pantry.ShoppingListItem, error) {
 	return s.shopping, s.err
 }
@@ -56,6 +97,30 @@ func (s *stubPantryService) AddCustomShoppingItem(_ context.Context, name string
 	return nil
 }
 
+func (s *stubPantryService) AddShoppingItems(_ context.Context, names []string) (pantry.ShoppingAdditions, error) {
+	result := pantry.ShoppingAdditions{Added: []pantry.ShoppingListItem{}, NeedsReview: []pantry.ShoppingReview{}}
+	if s.err != nil {
+		return result, s.err
+	}
+	for _, name := range names {
+		if candidates := s.reviews[name]; len(candidates) > 0 {
+			result.NeedsReview = append(result.NeedsReview, pantry.ShoppingReview{Name: name, Candidates: candidates})
+			continue
+		}
+		s.customAdded = append(s.customAdded, name)
+		result.Added = append(result.Added, pantry.ShoppingListItem{Name: name, Source: pantry.ShoppingSourceCustom})
+	}
+	return result, nil
+}
+
+func (s *stubPantryService) AddIngredientShoppingItem(_ context.Context, ingredient string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.ingredientsAdded = append(s.ingredientsAdded, ingredient)
+	return ingredient, nil
+}
+
 func (s *stubPantryService) RemoveCustomShoppingItem(_ context.Context, name string) error {
 	if s.err != nil {
 		return s.err
@@ -64,6 +129,97 @@ func (s *stubPantryService) RemoveCustomShoppingItem(_ context.Context, name str
 	return nil
 }
 
+func (s *stubPantryService) SetShoppingItemChecked(_ context.Context, name string, checked bool) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.checked == nil {
+		s.checked = map[string]bool{}
+	}
+	s.checked[name] = checked
+	return nil
+}
+
+func (s *stubPantryService) CompleteShoppingTrip(context.Context) (pantry.ShoppingTrip, error) {
+	if s.err != nil {
+		return pantry.ShoppingTrip{}, s.err
+	}
+	trip := pantry.ShoppingTrip{Items: []string{}, Stocked: []string{}}
+	for _, item := range s.shopping {
+		if !item.Checked {
+			continue
+		}
+		trip.Items = append(trip.Items, item.Name)
+		if item.Source != pantry.ShoppingSourceCustom {
+			trip.Stocked = append(trip.Stocked, item.Name)
+		}
+	}
+	if len(trip.Items) == 0 {
+		return pantry.ShoppingTrip{}, pantry.ErrNothingChecked
+	}
+	s.trips = append(s.trips, trip)
+	return trip, nil
+}
+
+func (s *stubPantryService) ShoppingTrips(context.Context, int) ([]pantry.ShoppingTrip, error) {
+	return s.trips, s.err
+}
+
+func (s *stubPantryService) ShoppingAisles(_ context.Context) ([]pantry.ShoppingSection, error) {
+	return pantry.GroupByAisle(s.shopping, s.layout), s.err
+}
+
+func (s *stubPantryService) SetIngredientCategory(_ context.Context, ingredient, category string) error {
+	return s.categorise(ingredient, category)
+}
+
+func (s *stubPantryService) SetShoppingItemCategory(_ context.Context, name, category string) error {
+	return s.categorise(name, category)
+}
+
+func (s *stubPantryService) categorise(name, category string) error {
+	if s.err != nil {
+		return s.err
+	}
+	category, err := pantry.NormalizeCategory(category)
+	if err != nil {
+		return err
+	}
+	if s.categorised == nil {
+		s.categorised = map[string]string{}
+	}
+	s.categorised[name] = category
+	return nil
+}
+
+func (s *stubPantryService) StoreLayout(_ context.Context) ([]string, error) {
+	return s.layout.Order(), s.err
+}
+
+func (s *stubPantryService) SaveStoreLayout(_ context.Context, categories []string) ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	layout, err := pantry.NormalizeStoreLayout(categories)
+	if err != nil {
+		return nil, err
+	}
+	s.layout = layout
+	return layout.Order(), nil
+}
+
+func (s *stubPantryService) ShareList(_ context.Context, list string, ttl time.Duration) (pantry.ListShare, error) {
+	s.sharedTTL = ttl
+	return pantry.ListShare{Token: "tok", List: list, ExpiresAt: time.Now().Add(ttl)}, s.err
+}
+
+func (s *stubPantryService) GetShare(_ context.Context, token string) (pantry.ListShare, error) {
+	if s.share != nil && s.share.Token == token {
+		return *s.share, nil
+	}
+	return pantry.ListShare{}, pantry.ErrShareNotFound
+}
+
 // --- Tests ---
 
 func TestListPantry_Success(t *testing.T) {
@@ -173,9 +329,9 @@ func TestAddCustomShoppingItem_Success(t *testing.T) {
 	h := NewPantryHandler(svc, nil, &noopLogger{})
 
 	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list",
-		strings.NewReader(`{"name":"washing-up liquid"}`))
+		strings.NewReader(`{"name":"washing-up liquid","custom":true}`))
 	rec := httptest.NewRecorder()
-	h.AddCustomShoppingItem(rec, req)
+	h.AddShoppingItem(rec, req)
 
 	if rec.Code != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d", rec.Code)
@@ -192,13 +348,65 @@ func TestAddCustomShoppingItem_MissingName(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list",
 		strings.NewReader(`{"name":"  "}`))
 	rec := httptest.NewRecorder()
-	h.AddCustomShoppingItem(rec, req)
+	h.AddShoppingItem(rec, req)
 
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d", rec.Code)
 	}
 }
 
+func TestAddShoppingItem_NeedsReview(t *testing.T) {
+	svc := &stubPantryService{reviews: map[string][]string{"stock": {"beef stock", "chicken stock"}}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list", strings.NewReader(`{"name":"stock"}`))
+	rec := httptest.NewRecorder()
+	h.AddShoppingItem(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body pantry.ShoppingAdditions
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Added) != 0 || len(body.NeedsReview) != 1 || len(body.NeedsReview[0].Candidates) != 2 {
+		t.Errorf("expected stock held for review, got %+v", body)
+	}
+	if len(svc.customAdded) != 0 {
+		t.Errorf("expected nothing added, got %v", svc.customAdded)
+	}
+}
+
+func TestAddShoppingItem_PickedIngredient(t *testing.T) {
+	svc := &stubPantryService{}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list", strings.NewReader(`{"ingredient":"chicken stock"}`))
+	rec := httptest.NewRecorder()
+	h.AddShoppingItem(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if len(svc.ingredientsAdded) != 1 || svc.ingredientsAdded[0] != "chicken stock" {
+		t.Errorf("expected ingredient added, got %v", svc.ingredientsAdded)
+	}
+}
+
+func TestAddShoppingItem_UnknownIngredient(t *testing.T) {
+	svc := &stubPantryService{err: pantry.IngredientNotFoundError{Name: "unobtainium"}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list", strings.NewReader(`{"ingredient":"unobtainium"}`))
+	rec := httptest.NewRecorder()
+	h.AddShoppingItem(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
 func TestRemoveCustomShoppingItem_Success(t *testing.T) {
 	svc := &stubPantryService{}
 	h := NewPantryHandler(svc, nil, &noopLogger{})
@@ -259,6 +467,30 @@ func TestAddToPantry_Success(t *testing.T) {
 	}
 }
 
+func TestAddToPantry_NewIngredient(t *testing.T) {
+	svc := &stubPantryService{newIngredient: true}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/pantry/tinned%20tomatoes", nil)
+	req.SetPathValue("ingredient", "tinned tomatoes")
+	rec := httptest.NewRecorder()
+	h.AddToPantry(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	var body struct {
+		Ingredient string `json:"ingredient"`
+		PantryOnly bool   `json:"pantryOnly"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Ingredient != "tinned tomatoes" || !body.PantryOnly {
+		t.Errorf("expected a new pantry-only ingredient, got %+v", body)
+	}
+}
+
 func TestAddToPantry_MissingIngredient(t *testing.T) {
 	svc := &stubPantryService{}
 	h := NewPantryHandler(svc, nil, &noopLogger{})
@@ -295,7 +527,6 @@ func TestPantryMutations_UnknownIngredientIs404(t *testing.T) {
 		call func(*PantryHandler, http.ResponseWriter, *http.Request)
 		verb string
 	}{
-		{name: "add", call: (*PantryHandler).AddToPantry, verb: http.MethodPut},
 		{name: "remove", call: (*PantryHandler).RemoveFromPantry, verb: http.MethodDelete},
 	}
 
@@ -326,3 +557,246 @@ func TestPantryMutations_UnknownIngredientIs404(t *testing.T) {
 		})
 	}
 }
+
+func TestShoppingList_NegotiatesFormats(t *testing.T) {
+	svc := &stubPantryService{
+		shopping: []pantry.ShoppingListItem{
+			{Name: "onion", Source: pantry.ShoppingSourceMealPlan},
+			{Name: "bin bags", Source: pantry.ShoppingSourceCustom},
+		},
+	}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	tests := []struct {
+		name        string
+		accept      string
+		query       string
+		contentType string
+		want        string
+	}{
+		{
+			name:        "plain text checklist grouped by source",
+			accept:      "text/plain",
+			contentType: "text/plain",
+			want:        "Shopping list\n\nFor the meal plan\n[ ] onion\n\nExtras\n[ ] bin bags\n",
+		},
+		{
+			name:        "csv",
+			accept:      "text/csv",
+			contentType: "text/csv",
+			want:        "name,source\nonion,meal_plan\nbin bags,custom\n",
+		},
+		{
+			name:        "markdown",
+			accept:      "text/html;q=0.9, text/markdown",
+			contentType: "text/markdown",
+			want:        "# Shopping list\n\n## For the meal plan\n\n- [ ] onion\n\n## Extras\n\n- [ ] bin bags\n",
+		},
+		{
+			name:        "format query param overrides Accept",
+			accept:      "application/json",
+			query:       "?format=csv",
+			contentType: "text/csv",
+			want:        "name,source\nonion,meal_plan\nbin bags,custom\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/shopping-list"+tt.query, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			h.ShoppingList(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", ct, tt.contentType)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShoppingList_DefaultsToJSON(t *testing.T) {
+	svc := &stubPantryService{shopping: []pantry.ShoppingListItem{{Name: "onion", Source: pantry.ShoppingSourceMealPlan}}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/shopping-list", nil)
+	req.Header.Set("Accept", "*/*")
+	rec := httptest.NewRecorder()
+	h.ShoppingList(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
+func TestShoppingList_NotAcceptable(t *testing.T) {
+	h := NewPantryHandler(&stubPantryService{}, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/shopping-list", nil)
+	req.Header.Set("Accept", "image/png")
+	rec := httptest.NewRecorder()
+	h.ShoppingList(rec, req)
+
+	if rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("expected 406, got %d", rec.Code)
+	}
+}
+
+func TestListPantry_PlainText(t *testing.T) {
+	svc := &stubPantryService{items: []pantry.PantryItem{{Ingredient: "flour"}, {Ingredient: "salt"}}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pantry", nil)
+	req.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	h.ListPantry(rec, req)
+
+	if got, want := rec.Body.String(), "Pantry\n\n- flour\n- salt\n"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestShareShoppingList(t *testing.T) {
+	svc := &stubPantryService{}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list/share", strings.NewReader(`{"expiresInHours": 2}`))
+	req.Host = "book.example"
+	rec := httptest.NewRecorder()
+	h.ShareShoppingList(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if svc.sharedTTL != 2*time.Hour {
+		t.Errorf("ttl = %v, want 2h", svc.sharedTTL)
+	}
+	var body struct {
+		URL  string `json:"url"`
+		List string `json:"list"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.URL != "http://book.example/share/tok" {
+		t.Errorf("url = %q", body.URL)
+	}
+	if body.List != pantry.ListShoppingList {
+		t.Errorf("list = %q", body.List)
+	}
+}
+
+func TestViewShare_RendersHTML(t *testing.T) {
+	svc := &stubPantryService{
+		share:    &pantry.ListShare{Token: "tok", List: pantry.ListShoppingList},
+		shopping: []pantry.ShoppingListItem{{Name: "<b>onion</b>", Source: pantry.ShoppingSourceMealPlan}},
+	}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/share/tok", nil)
+	req.SetPathValue("token", "tok")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+	rec := httptest.NewRecorder()
+	h.ViewShare(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "<h2>For the meal plan</h2>") || !strings.Contains(body, "&lt;b&gt;onion&lt;/b&gt;") {
+		t.Errorf("unexpected page:\n%s", body)
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "no-store") {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+}
+
+func TestViewShare_UnknownToken(t *testing.T) {
+	h := NewPantryHandler(&stubPantryService{}, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/share/nope", nil)
+	req.SetPathValue("token", "nope")
+	rec := httptest.NewRecorder()
+	h.ViewShare(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestShoppingList_GroupByAisle(t *testing.T) {
+	svc := &stubPantryService{
+		shopping: []pantry.ShoppingListItem{
+			{Name: "eggs", Source: pantry.ShoppingSourceMealPlan, Category: pantry.CategoryDairy},
+			{Name: "bin bags", Source: pantry.ShoppingSourceCustom, Category: pantry.CategoryHousehold},
+			{Name: "leeks", Source: pantry.ShoppingSourceMealPlan, Category: pantry.CategoryProduce},
+		},
+		layout: pantry.StoreLayout{pantry.CategoryHousehold},
+	}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/shopping-list?group=aisle", nil)
+	rec := httptest.NewRecorder()
+	h.ShoppingList(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Items    []pantry.ShoppingListItem `json:"items"`
+		Sections []pantry.ShoppingSection  `json:"sections"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Sections) != 3 || body.Sections[0].Category != pantry.CategoryHousehold || body.Sections[1].Category != pantry.CategoryProduce {
+		t.Fatalf("sections = %+v, want household, produce, dairy", body.Sections)
+	}
+	if len(body.Items) != 3 || body.Items[0].Name != "bin bags" {
+		t.Errorf("items = %+v, want walking order", body.Items)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/shopping-list?group=price", nil)
+	rec = httptest.NewRecorder()
+	h.ShoppingList(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown group: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestSaveStoreLayout(t *testing.T) {
+	for body, want := range map[string]int{
+		`{"categories":["bakery","produce"]}`:  http.StatusOK,
+		`{"categories":["produce","produce"]}`: http.StatusBadRequest,
+		`{"categories":["deli"]}`:              http.StatusBadRequest,
+		`{"categories":`:                       http.StatusBadRequest,
+	} {
+		h := NewPantryHandler(&stubPantryService{}, nil, &noopLogger{})
+		req := httptest.NewRequest(http.MethodPut, "/api/store-layout", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.SaveStoreLayout(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d: %s", body, want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestSetIngredientCategory_Unknown(t *testing.T) {
+	svc := &stubPantryService{}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/ingredients/milk/category", strings.NewReader(`{"category":"deli"}`))
+	req.SetPathValue("ingredient", "milk")
+	rec := httptest.NewRecorder()
+	h.SetIngredientCategory(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
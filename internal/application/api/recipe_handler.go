@@ -3,9 +3,11 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/application/api/middleware"
@@ -34,6 +36,14 @@ func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	// Call service directly with validated data
 	savedRecipe, err := h.recipeService.CreateRecipe(r.Context(), rec)
 	if err != nil {
+		if errors.Is(err, recipe.ErrInvalidSubRecipe) {
+			h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_sub_recipe", err.Error())
+			return
+		}
+		if errors.Is(err, recipe.ErrInvalidLabel) {
+			h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_label", err.Error())
+			return
+		}
 		h.logger.Error().Err(err).Msg("Failed to create recipe")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "creation_failed", "Failed to create recipe")
 		return
@@ -84,11 +94,21 @@ func (h *RecipeHandler) GetRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// ?expand=sub-recipes inlines the recipes behind sub-recipe lines.
+	if r.URL.Query().Get("expand") == "sub-recipes" {
+		if err := h.recipeService.ExpandSubRecipes(r.Context(), recipe); err != nil {
+			h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to expand sub-recipes")
+			h.writeErrorResponse(w, http.StatusInternalServerError, "retrieval_failed", "Failed to retrieve recipe")
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(recipe)
 }
 
-// GET /api/recipes
+// GET /api/recipes - ?equipment=owned leaves out recipes needing equipment
+// the household doesn't own; ?for= names who's eating (see eatersFromQuery).
 func (h *RecipeHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	limitStr := r.URL.Query().Get("limit")
@@ -99,7 +119,7 @@ func (h *RecipeHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
 
 	// Allow-list sort modes; anything else falls back to default (newest first)
 	switch sort {
-	case "name", "time":
+	case "name", "time", "not_cooked_recently":
 		// valid
 	default:
 		sort = ""
@@ -136,7 +156,11 @@ func (h *RecipeHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
 		labels = cleanLabels
 	}
 
-	recipes, total, err := h.recipeService.ListRecipes(r.Context(), limit, offset, search, labels, sort)
+	recipes, total, err := h.recipeService.ListRecipes(r.Context(), limit, offset, search, labels, sort, ownedEquipmentOnly(r), eatersFromQuery(r))
+	if errors.Is(err, recipe.ErrPersonNotFound) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "unknown_person", err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to list recipes")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list recipes")
@@ -195,6 +219,14 @@ func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 			h.writeErrorResponse(w, http.StatusNotFound, "recipe_not_found", "Recipe not found")
 			return
 		}
+		if errors.Is(err, recipe.ErrInvalidSubRecipe) {
+			h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_sub_recipe", err.Error())
+			return
+		}
+		if errors.Is(err, recipe.ErrInvalidLabel) {
+			h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_label", err.Error())
+			return
+		}
 		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to update recipe")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "update_failed", "Failed to update recipe")
 		return
@@ -299,14 +331,33 @@ func (h *RecipeHandler) ListArchivedRecipes(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(response)
 }
 
-// POST /api/recipes/{id}/meal-plan - Add recipe to meal plan
+// POST /api/recipes/{id}/meal-plan - Add recipe to meal plan.
+// An optional body of {"date": "YYYY-MM-DD"} schedules it for that day.
 func (h *RecipeHandler) AddToMealPlan(w http.ResponseWriter, r *http.Request) {
 	recipeID, ok := h.recipeIDFromPath(w, r)
 	if !ok {
 		return
 	}
 
-	err := h.recipeService.AddToMealPlan(r.Context(), recipeID)
+	var body struct {
+		Date string `json:"date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	var plannedFor *time.Time
+	if body.Date != "" {
+		day, err := time.Parse(time.DateOnly, body.Date)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid_date", "date must be formatted YYYY-MM-DD")
+			return
+		}
+		plannedFor = &day
+	}
+
+	err := h.recipeService.AddToMealPlan(r.Context(), recipeID, plannedFor)
 	if err != nil {
 		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to add recipe to meal plan")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "meal_plan_add_failed", "Failed to add recipe to meal plan")
@@ -350,7 +401,8 @@ func (h *RecipeHandler) ListIngredients(w http.ResponseWriter, r *http.Request)
 	})
 }
 
-// GET /api/units - List all units of measure
+// GET /api/units - List the canonical units of measure, with their aliases
+// and dimension
 func (h *RecipeHandler) ListUnits(w http.ResponseWriter, r *http.Request) {
 	units, err := h.recipeService.ListUnits(r.Context())
 	if err != nil {
@@ -365,9 +417,14 @@ func (h *RecipeHandler) ListUnits(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GET /api/recipes/meal-plan - List meal plan recipes
+// GET /api/recipes/meal-plan - List meal plan recipes. ?for= names who's
+// eating; each recipe then lists what any of them can't eat.
 func (h *RecipeHandler) ListMealPlanRecipes(w http.ResponseWriter, r *http.Request) {
-	recipes, err := h.recipeService.ListMealPlanRecipes(r.Context())
+	recipes, err := h.recipeService.ListMealPlanRecipes(r.Context(), eatersFromQuery(r).Names)
+	if errors.Is(err, recipe.ErrPersonNotFound) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "unknown_person", err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to list meal plan recipes")
 		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list meal plan recipes")
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// revisionFromPath reads the {rev} path parameter. Like recipeIDFromPath it
+// writes the error response itself and returns ok=false on failure.
+func (h *RecipeHandler) revisionFromPath(w http.ResponseWriter, r *http.Request) (int, bool) {
+	rev, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil || rev < 1 {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_revision", "Revision must be a positive integer")
+		return 0, false
+	}
+	return rev, true
+}
+
+// writeRevisionError maps the not-found cases shared by the revision
+// endpoints, falling back to a 500 with the given code and message.
+func (h *RecipeHandler) writeRevisionError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, recipe.ErrRecipeNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "recipe_not_found", "Recipe not found")
+	case errors.Is(err, recipe.ErrRevisionNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "revision_not_found", "Revision not found")
+	case errors.Is(err, recipe.ErrInvalidLabel):
+		h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_label", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
+
+// GET /api/recipes/{id}/revisions - Revision history, newest first
+func (h *RecipeHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.recipeService.ListRevisions(r.Context(), recipeID)
+	if err != nil {
+		h.writeRevisionError(w, err, "listing_failed", "Failed to list revisions")
+		return
+	}
+	if revisions == nil {
+		revisions = []recipe.Revision{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+}
+
+// GET /api/recipes/{id}/revisions/{rev} - One revision, including its snapshot
+func (h *RecipeHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	rev, ok := h.revisionFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	revision, err := h.recipeService.GetRevision(r.Context(), recipeID, rev)
+	if err != nil {
+		h.writeRevisionError(w, err, "revision_failed", "Failed to get revision")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revision)
+}
+
+// GET /api/recipes/{id}/revisions/diff?from=N[&to=M] - What changed between
+// two revisions. Without to, compares against the recipe as it is now.
+func (h *RecipeHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || from < 1 {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_revision", "from must be a positive revision number")
+		return
+	}
+	to := 0
+	if v := r.URL.Query().Get("to"); v != "" {
+		to, err = strconv.Atoi(v)
+		if err != nil || to < 1 {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid_revision", "to must be a positive revision number")
+			return
+		}
+	}
+
+	diff, err := h.recipeService.DiffRevisions(r.Context(), recipeID, from, to)
+	if err != nil {
+		h.writeRevisionError(w, err, "diff_failed", "Failed to diff revisions")
+		return
+	}
+
+	var toOut any = to
+	if to == 0 {
+		toOut = "current"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"from": from,
+		"to":   toOut,
+		"diff": diff,
+	})
+}
+
+// POST /api/recipes/{id}/revisions/{rev}/revert - Restore a revision. The
+// revert is itself recorded as a new revision.
+func (h *RecipeHandler) RevertToRevision(w http.ResponseWriter, r *http.Request) {
+	recipeID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	rev, ok := h.revisionFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	reverted, err := h.recipeService.RevertToRevision(r.Context(), recipeID, rev)
+	if err != nil {
+		h.writeRevisionError(w, err, "revert_failed", "Failed to revert recipe")
+		return
+	}
+
+	h.logger.Info().Str("recipe_id", recipeID.String()).Int("revision", rev).Msg("Recipe reverted")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reverted)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+const revisionTestRecipeID = "11111111-1111-1111-1111-111111111111"
+
+func TestListRevisions_Success(t *testing.T) {
+	svc := &stubRecipeService{revisions: []recipe.Revision{
+		{Number: 2, Source: recipe.RevisionSourceChat},
+		{Number: 1, Source: recipe.RevisionSourceREST},
+	}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/revisions", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.ListRevisions(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Revisions []struct {
+			Revision int    `json:"revision"`
+			Source   string `json:"source"`
+		} `json:"revisions"`
+		Total int `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Total != 2 || body.Revisions[0].Revision != 2 || body.Revisions[0].Source != "chat" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+}
+
+func TestGetRevision_NotFound(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/revisions/4", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	req.SetPathValue("rev", "4")
+	rec := httptest.NewRecorder()
+	h.GetRevision(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestDiffRevisions_DefaultsToCurrent(t *testing.T) {
+	svc := &stubRecipeService{diff: recipe.Diff{
+		Fields: []recipe.FieldChange{{Field: "cookTime", From: 30, To: 40}},
+	}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/revisions/diff?from=1", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.DiffRevisions(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		From int    `json:"from"`
+		To   string `json:"to"`
+		Diff struct {
+			Fields []recipe.FieldChange `json:"fields"`
+		} `json:"diff"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.From != 1 || body.To != "current" || len(body.Diff.Fields) != 1 {
+		t.Errorf("unexpected body: %+v", body)
+	}
+}
+
+func TestDiffRevisions_MissingFrom(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recipes/"+revisionTestRecipeID+"/revisions/diff", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.DiffRevisions(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestRevertToRevision_Success(t *testing.T) {
+	svc := &stubRecipeService{revisions: []recipe.Revision{{Number: 1}}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/revisions/1/revert", nil)
+	req.SetPathValue("id", revisionTestRecipeID)
+	req.SetPathValue("rev", "1")
+	rec := httptest.NewRecorder()
+	h.RevertToRevision(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if svc.reverted != 1 {
+		t.Errorf("expected revert to revision 1, got %d", svc.reverted)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/kieranajp/the-bluer-book/internal/application/api/middleware"
 	"github.com/kieranajp/the-bluer-book/internal/application/chat"
 	pantryservice "github.com/kieranajp/the-bluer-book/internal/domain/pantry/service"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/kieranajp/the-bluer-book/internal/domain/recipe/service"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/ai"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
@@ -25,9 +26,23 @@ func NewRouter(recipeService service.RecipeService, pantryService pantryservice.
 	pantryHandler := NewPantryHandler(pantryService, scanner, logger)
 	validationMiddleware := middleware.NewValidationMiddleware(logger)
 
-	mux.HandleFunc("GET /api/units", recipeHandler.ListUnits)
 	mux.HandleFunc("GET /api/ingredients", recipeHandler.ListIngredients)
 
+	// Unit registry admin: recipe lines resolve their units through it, and
+	// unit text that resolves to nothing waits in the review queue.
+	mux.HandleFunc("GET /api/units", recipeHandler.ListUnits)
+	mux.HandleFunc("PUT /api/units/{name}", recipeHandler.SaveUnit)
+	mux.HandleFunc("POST /api/units/merge", recipeHandler.MergeUnits)
+	mux.HandleFunc("GET /api/units/review", recipeHandler.ListUnitReviews)
+	mux.HandleFunc("DELETE /api/units/review/{name}", recipeHandler.DismissUnitReview)
+
+	// Ingredient catalogue admin: tidy near-duplicates by renaming, merging
+	// and aliasing, so saves resolve to one ingredient.
+	mux.HandleFunc("GET /api/ingredients/catalogue", recipeHandler.GetIngredientCatalogue)
+	mux.HandleFunc("PUT /api/ingredients/{name}/aliases", recipeHandler.SetIngredientAliases)
+	mux.HandleFunc("POST /api/ingredients/rename", recipeHandler.RenameIngredient)
+	mux.HandleFunc("POST /api/ingredients/merge", recipeHandler.MergeIngredients)
+
 	mux.HandleFunc("GET /api/recipes", recipeHandler.ListRecipes)
 	mux.HandleFunc("GET /api/labels", recipeHandler.ListLabels)
 	mux.HandleFunc("GET /api/recipes/archived", recipeHandler.ListArchivedRecipes)
@@ -35,21 +50,83 @@ func NewRouter(recipeService service.RecipeService, pantryService pantryservice.
 	mux.HandleFunc("GET /api/recipes/{id}", recipeHandler.GetRecipe)
 	mux.HandleFunc("DELETE /api/recipes/{id}", recipeHandler.DeleteRecipe)
 	mux.HandleFunc("POST /api/recipes/{id}/restore", recipeHandler.RestoreRecipe)
+	mux.HandleFunc("POST /api/recipes/{id}/variants", recipeHandler.ForkRecipe)
+
+	// Revision history
+	mux.HandleFunc("GET /api/recipes/{id}/revisions", recipeHandler.ListRevisions)
+	mux.HandleFunc("GET /api/recipes/{id}/revisions/diff", recipeHandler.DiffRevisions)
+	mux.HandleFunc("GET /api/recipes/{id}/revisions/{rev}", recipeHandler.GetRevision)
+	mux.HandleFunc("POST /api/recipes/{id}/revisions/{rev}/revert", recipeHandler.RevertToRevision)
+
+	// Cook log
+	mux.HandleFunc("GET /api/recipes/{id}/cooks", recipeHandler.ListCooks)
+	mux.HandleFunc("POST /api/recipes/{id}/cooks", recipeHandler.LogCook)
+
+	// Annotations: personal notes, kept apart from the recipe body
+	mux.HandleFunc("POST /api/recipes/{id}/annotations", recipeHandler.AddAnnotation)
+	mux.HandleFunc("PUT /api/recipes/{id}/annotations/{annotation}", recipeHandler.UpdateAnnotation)
+	mux.HandleFunc("DELETE /api/recipes/{id}/annotations/{annotation}", recipeHandler.DeleteAnnotation)
+
+	// Nutrition, estimated from the nutrient table
+	mux.HandleFunc("GET /api/recipes/{id}/nutrition", recipeHandler.GetNutrition)
+
+	// Kitchen equipment the household owns; GET /api/recipes?equipment=owned
+	// filters by it.
+	mux.HandleFunc("GET /api/equipment", recipeHandler.ListEquipment)
+	mux.HandleFunc("PUT /api/equipment/{name}", recipeHandler.AddEquipment)
+	mux.HandleFunc("DELETE /api/equipment/{name}", recipeHandler.RemoveEquipment)
+
+	// The people we cook for; ?for= on recipe and meal-plan listings checks
+	// recipes against their allergens and diets.
+	mux.HandleFunc("GET /api/household", recipeHandler.ListHousehold)
+	mux.HandleFunc("PUT /api/household/{name}", recipeHandler.SavePerson)
+	mux.HandleFunc("DELETE /api/household/{name}", recipeHandler.DeletePerson)
+
+	// Label taxonomy: the label types and names recipes may carry
+	mux.HandleFunc("GET /api/taxonomy", recipeHandler.GetTaxonomy)
+	mux.HandleFunc("PUT /api/taxonomy/{type}", recipeHandler.SaveLabelType)
+	mux.HandleFunc("PUT /api/taxonomy/{type}/{name}", recipeHandler.SaveLabel)
+	mux.HandleFunc("POST /api/labels/rename", recipeHandler.RenameLabel)
+	mux.HandleFunc("POST /api/labels/merge", recipeHandler.MergeLabels)
 
 	// Meal planning routes
 	mux.HandleFunc("POST /api/recipes/{id}/meal-plan", recipeHandler.AddToMealPlan)
 	mux.HandleFunc("DELETE /api/recipes/{id}/meal-plan", recipeHandler.RemoveFromMealPlan)
+	mux.HandleFunc("GET /api/meal-plan.ics", recipeHandler.MealPlanCalendar)
 
 	// Pantry routes
 	mux.HandleFunc("GET /api/pantry", pantryHandler.ListPantry)
 	mux.HandleFunc("PUT /api/pantry/{ingredient}", pantryHandler.AddToPantry)
 	mux.HandleFunc("DELETE /api/pantry/{ingredient}", pantryHandler.RemoveFromPantry)
+	mux.HandleFunc("POST /api/pantry/share", pantryHandler.SharePantry)
+	mux.HandleFunc("PUT /api/pantry/out/{ingredient}", pantryHandler.MarkOut)
+
+	// Staples: assumed in stock unless marked out.
+	mux.HandleFunc("GET /api/pantry/staples", pantryHandler.ListStaples)
+	mux.HandleFunc("PUT /api/pantry/staples/{ingredient}", pantryHandler.AddStaple)
+	mux.HandleFunc("DELETE /api/pantry/staples/{ingredient}", pantryHandler.RemoveStaple)
 
 	// Shopping list: meal-plan shortfall plus free-text custom items.
 	mux.HandleFunc("GET /api/shopping-list", pantryHandler.ShoppingList)
-	mux.HandleFunc("POST /api/shopping-list", pantryHandler.AddCustomShoppingItem)
+	mux.HandleFunc("POST /api/shopping-list", pantryHandler.AddShoppingItem)
 	mux.HandleFunc("POST /api/shopping-list/scan", pantryHandler.ScanShoppingList)
 	mux.HandleFunc("DELETE /api/shopping-list/{name}", pantryHandler.RemoveCustomShoppingItem)
+	mux.HandleFunc("POST /api/shopping-list/share", pantryHandler.ShareShoppingList)
+
+	// Grocery categories and the store layout, for ?group=aisle.
+	mux.HandleFunc("PUT /api/ingredients/{ingredient}/category", pantryHandler.SetIngredientCategory)
+	mux.HandleFunc("PUT /api/shopping-list/{name}/category", pantryHandler.SetShoppingItemCategory)
+	mux.HandleFunc("GET /api/store-layout", pantryHandler.GetStoreLayout)
+	mux.HandleFunc("PUT /api/store-layout", pantryHandler.SaveStoreLayout)
+
+	// Shopping trips: shared ticks, then stock the pantry in one go.
+	mux.HandleFunc("PUT /api/shopping-list/{name}/checked", pantryHandler.CheckShoppingItem)
+	mux.HandleFunc("DELETE /api/shopping-list/{name}/checked", pantryHandler.UncheckShoppingItem)
+	mux.HandleFunc("POST /api/shopping-list/complete", pantryHandler.CompleteShoppingTrip)
+	mux.HandleFunc("GET /api/shopping-list/trips", pantryHandler.ListShoppingTrips)
+
+	// Public, read-only view of a shared list. The token is the credential.
+	mux.HandleFunc("GET /share/{token}", pantryHandler.ViewShare)
 
 	mux.Handle("POST /api/recipes",
 		validationMiddleware.ValidateCreateRecipe(
@@ -76,5 +153,7 @@ func NewRouter(recipeService service.RecipeService, pantryService pantryservice.
 		w.Write([]byte("OK"))
 	})
 
-	return metrics.HTTPMetrics(middleware.AccessLog(logger, mux))
+	return metrics.HTTPMetrics(middleware.AccessLog(logger,
+		middleware.RevisionSource(recipe.RevisionSourceREST, mux),
+	))
 }
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+)
+
+// PUT /api/shopping-list/{name}/checked - Tick a line on the shopping list as
+// it goes in the basket. Ticks are shared, so everyone shopping sees them.
+func (h *PantryHandler) CheckShoppingItem(w http.ResponseWriter, r *http.Request) {
+	h.setShoppingItemChecked(w, r, true)
+}
+
+// DELETE /api/shopping-list/{name}/checked - Untick a line.
+func (h *PantryHandler) UncheckShoppingItem(w http.ResponseWriter, r *http.Request) {
+	h.setShoppingItemChecked(w, r, false)
+}
+
+func (h *PantryHandler) setShoppingItemChecked(w http.ResponseWriter, r *http.Request, checked bool) {
+	name := r.PathValue("name")
+	if name == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "missing_name", "Item name is required")
+		return
+	}
+
+	if err := h.pantryService.SetShoppingItemChecked(r.Context(), name, checked); err != nil {
+		h.writeShoppingTripError(w, err, "check_failed", "Failed to update shopping list item")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("name", name).Bool("checked", checked).Msg("Shopping list item checked")
+}
+
+// POST /api/shopping-list/complete - Finish a trip to the shop: stock the
+// pantry with the checked ingredients, take the checked items added by hand
+// off the list, and record the trip. Returns the trip.
+func (h *PantryHandler) CompleteShoppingTrip(w http.ResponseWriter, r *http.Request) {
+	trip, err := h.pantryService.CompleteShoppingTrip(r.Context())
+	if err != nil {
+		h.writeShoppingTripError(w, err, "complete_failed", "Failed to complete shopping trip")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trip)
+	h.logger.Info().Int("items", len(trip.Items)).Int("stocked", len(trip.Stocked)).Msg("Shopping trip completed")
+}
+
+// GET /api/shopping-list/trips - Completed trips, newest first. ?limit= caps
+// how many (default 20, at most 100).
+func (h *PantryHandler) ListShoppingTrips(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	trips, err := h.pantryService.ShoppingTrips(r.Context(), limit)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list shopping trips")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list shopping trips")
+		return
+	}
+	if trips == nil {
+		trips = []pantry.ShoppingTrip{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"trips": trips,
+		"total": len(trips),
+	})
+}
+
+func (h *PantryHandler) writeShoppingTripError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, pantry.ErrShoppingItemNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "item_not_found", err.Error())
+	case errors.Is(err, pantry.ErrNothingChecked):
+		h.writeErrorResponse(w, http.StatusConflict, "nothing_checked", "Nothing on the shopping list is checked")
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
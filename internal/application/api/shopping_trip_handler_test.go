@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+)
+
+func TestCheckShoppingItem(t *testing.T) {
+	svc := &stubPantryService{}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/shopping-list/onion/checked", nil)
+	req.SetPathValue("name", "onion")
+	rec := httptest.NewRecorder()
+	h.CheckShoppingItem(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if !svc.checked["onion"] {
+		t.Errorf("expected onion checked, got %v", svc.checked)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/shopping-list/onion/checked", nil)
+	req.SetPathValue("name", "onion")
+	rec = httptest.NewRecorder()
+	h.UncheckShoppingItem(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if svc.checked["onion"] {
+		t.Errorf("expected onion unchecked, got %v", svc.checked)
+	}
+}
+
+func TestCheckShoppingItem_NotOnList(t *testing.T) {
+	svc := &stubPantryService{err: pantry.ShoppingItemNotFoundError{Name: "caviar"}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/shopping-list/caviar/checked", nil)
+	req.SetPathValue("name", "caviar")
+	rec := httptest.NewRecorder()
+	h.CheckShoppingItem(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestCompleteShoppingTrip(t *testing.T) {
+	svc := &stubPantryService{shopping: []pantry.ShoppingListItem{
+		{Name: "onion", Source: pantry.ShoppingSourceMealPlan, Checked: true},
+		{Name: "quark", Source: pantry.ShoppingSourceMealPlan},
+		{Name: "bin bags", Source: pantry.ShoppingSourceCustom, Checked: true},
+	}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list/complete", nil)
+	rec := httptest.NewRecorder()
+	h.CompleteShoppingTrip(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var trip pantry.ShoppingTrip
+	if err := json.NewDecoder(rec.Body).Decode(&trip); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(trip.Items) != 2 || len(trip.Stocked) != 1 || trip.Stocked[0] != "onion" {
+		t.Errorf("expected onion stocked and bin bags bought, got %+v", trip)
+	}
+}
+
+func TestCompleteShoppingTrip_NothingChecked(t *testing.T) {
+	svc := &stubPantryService{shopping: []pantry.ShoppingListItem{{Name: "onion", Source: pantry.ShoppingSourceMealPlan}}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shopping-list/complete", nil)
+	rec := httptest.NewRecorder()
+	h.CompleteShoppingTrip(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestShoppingList_RendersChecks(t *testing.T) {
+	svc := &stubPantryService{shopping: []pantry.ShoppingListItem{
+		{Name: "onion", Source: pantry.ShoppingSourceMealPlan, Checked: true},
+		{Name: "quark", Source: pantry.ShoppingSourceMealPlan},
+	}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/shopping-list", nil)
+	req.Header.Set("Accept", "text/markdown")
+	rec := httptest.NewRecorder()
+	h.ShoppingList(rec, req)
+
+	want := "# Shopping list\n\n## For the meal plan\n\n- [x] onion\n- [ ] quark\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
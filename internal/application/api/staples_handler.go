@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+)
+
+// GET /api/pantry/staples - Ingredients assumed always to be in stock, and
+// whether any have run out.
+func (h *PantryHandler) ListStaples(w http.ResponseWriter, r *http.Request) {
+	staples, err := h.pantryService.Staples(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list staples")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list staples")
+		return
+	}
+	if staples == nil {
+		staples = []pantry.Staple{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"staples": staples,
+		"total":   len(staples),
+	})
+}
+
+// PUT /api/pantry/staples/{ingredient} - Make an ingredient a staple, so it
+// counts as in stock and stays off the shopping list.
+func (h *PantryHandler) AddStaple(w http.ResponseWriter, r *http.Request) {
+	h.setStaple(w, r, true)
+}
+
+// DELETE /api/pantry/staples/{ingredient} - Stop treating an ingredient as a
+// staple; it's in stock only when stocked like anything else.
+func (h *PantryHandler) RemoveStaple(w http.ResponseWriter, r *http.Request) {
+	h.setStaple(w, r, false)
+}
+
+func (h *PantryHandler) setStaple(w http.ResponseWriter, r *http.Request, staple bool) {
+	ingredient, ok := h.ingredientFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	name, err := h.pantryService.SetStaple(r.Context(), ingredient, staple)
+	if err != nil {
+		h.writeStapleError(w, err, ingredient)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("ingredient", name).Bool("staple", staple).Msg("Staple updated")
+}
+
+// PUT /api/pantry/out/{ingredient} - "We're out of olive oil." The ingredient
+// leaves the pantry and, if it's a staple, counts as missing until it's
+// stocked again (PUT /api/pantry/{ingredient}). Returns the ingredient and
+// whether it's a staple.
+func (h *PantryHandler) MarkOut(w http.ResponseWriter, r *http.Request) {
+	ingredient, ok := h.ingredientFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	name, staple, err := h.pantryService.MarkOut(r.Context(), ingredient)
+	if err != nil {
+		h.writeStapleError(w, err, ingredient)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"ingredient": name,
+		"staple":     staple,
+	})
+	h.logger.Info().Str("ingredient", name).Bool("staple", staple).Msg("Ingredient marked out")
+}
+
+func (h *PantryHandler) writeStapleError(w http.ResponseWriter, err error, ingredient string) {
+	if errors.Is(err, pantry.ErrIngredientNotFound) {
+		h.writeErrorResponse(w, http.StatusNotFound, "ingredient_not_found", "No such ingredient")
+		return
+	}
+	h.logger.Error().Err(err).Str("ingredient", ingredient).Msg("Failed to update staple")
+	h.writeErrorResponse(w, http.StatusInternalServerError, "staple_failed", "Failed to update staple")
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+)
+
+func TestAddAndRemoveStaple(t *testing.T) {
+	svc := &stubPantryService{}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/pantry/staples/salt", nil)
+	req.SetPathValue("ingredient", "salt")
+	rec := httptest.NewRecorder()
+	h.AddStaple(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if !svc.staples["salt"] {
+		t.Errorf("expected salt to be a staple, got %v", svc.staples)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/pantry/staples/salt", nil)
+	req.SetPathValue("ingredient", "salt")
+	rec = httptest.NewRecorder()
+	h.RemoveStaple(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if svc.staples["salt"] {
+		t.Errorf("expected salt no longer a staple, got %v", svc.staples)
+	}
+}
+
+func TestMarkOut(t *testing.T) {
+	svc := &stubPantryService{staples: map[string]bool{"olive oil": true}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/pantry/out/olive%20oil", nil)
+	req.SetPathValue("ingredient", "olive oil")
+	rec := httptest.NewRecorder()
+	h.MarkOut(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Ingredient string `json:"ingredient"`
+		Staple     bool   `json:"staple"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Ingredient != "olive oil" || !body.Staple {
+		t.Errorf("expected olive oil marked out as a staple, got %+v", body)
+	}
+}
+
+func TestMarkOut_UnknownIngredient(t *testing.T) {
+	svc := &stubPantryService{err: pantry.IngredientNotFoundError{Name: "unobtainium"}}
+	h := NewPantryHandler(svc, nil, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/pantry/out/unobtainium", nil)
+	req.SetPathValue("ingredient", "unobtainium")
+	rec := httptest.NewRecorder()
+	h.MarkOut(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// GET /api/taxonomy - Every label type, in display order, with the labels
+// allowed under it.
+func (h *RecipeHandler) GetTaxonomy(w http.ResponseWriter, r *http.Request) {
+	taxonomy, err := h.recipeService.Taxonomy(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to load taxonomy")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to load taxonomy")
+		return
+	}
+	if taxonomy == nil {
+		taxonomy = recipe.Taxonomy{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"types": taxonomy})
+}
+
+// PUT /api/taxonomy/{type} - Add a label type, or update the one of that
+// name.
+func (h *RecipeHandler) SaveLabelType(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DisplayName string `json:"displayName"`
+		Description string `json:"description"`
+		Multiple    bool   `json:"multiple"`
+		Position    int    `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	lt := recipe.LabelType{
+		Name:        r.PathValue("type"),
+		DisplayName: body.DisplayName,
+		Description: body.Description,
+		Multiple:    body.Multiple,
+		Position:    body.Position,
+	}
+	saved, err := h.recipeService.SaveLabelType(r.Context(), lt)
+	if err != nil {
+		h.writeTaxonomyError(w, err, "label_type_save_failed", "Failed to save label type")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+	h.logger.Info().Str("label_type", saved.Name).Msg("Label type saved")
+}
+
+// PUT /api/taxonomy/{type}/{name} - Add a label, or update the display name,
+// description and aliases of the one of that name.
+func (h *RecipeHandler) SaveLabel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DisplayName string   `json:"displayName"`
+		Description string   `json:"description"`
+		Aliases     []string `json:"aliases"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	d := recipe.LabelDefinition{
+		Type:        r.PathValue("type"),
+		Name:        r.PathValue("name"),
+		DisplayName: body.DisplayName,
+		Description: body.Description,
+		Aliases:     body.Aliases,
+	}
+	saved, err := h.recipeService.SaveLabel(r.Context(), d)
+	if err != nil {
+		h.writeTaxonomyError(w, err, "label_save_failed", "Failed to save label")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+	h.logger.Info().Str("label", saved.Type+":"+saved.Name).Msg("Label saved")
+}
+
+// POST /api/labels/rename - Rename a label, given as {"label": "type:name",
+// "to": "new_name"}. Recipes keep it, and the old name stays as an alias.
+// With "dryRun": true nothing changes; the response lists the recipes that
+// would be affected either way.
+func (h *RecipeHandler) RenameLabel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Label  string `json:"label"`
+		To     string `json:"to"`
+		DryRun bool   `json:"dryRun"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+	label, err := recipe.ParseLabel(body.Label)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_label", err.Error())
+		return
+	}
+
+	change, err := h.recipeService.RenameLabel(r.Context(), label, body.To, body.DryRun)
+	if err != nil {
+		h.writeTaxonomyError(w, err, "label_rename_failed", "Failed to rename label")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+	if !change.DryRun {
+		h.logger.Info().Str("from", body.Label).Str("to", change.Label.Type+":"+change.Label.Name).Int("recipes", len(change.Recipes)).Msg("Label renamed")
+	}
+}
+
+// POST /api/labels/merge - Fold one label into another of the same type,
+// given as {"from": "course:stew", "into": "course:main"}. Recipes carrying
+// from carry into instead, and from's name becomes one of into's aliases.
+// With "dryRun": true nothing changes; the response lists the recipes that
+// would be re-pointed.
+func (h *RecipeHandler) MergeLabels(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		From   string `json:"from"`
+		Into   string `json:"into"`
+		DryRun bool   `json:"dryRun"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+	from, err := recipe.ParseLabel(body.From)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_label", err.Error())
+		return
+	}
+	into, err := recipe.ParseLabel(body.Into)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_label", err.Error())
+		return
+	}
+
+	change, err := h.recipeService.MergeLabels(r.Context(), from, into, body.DryRun)
+	if err != nil {
+		h.writeTaxonomyError(w, err, "label_merge_failed", "Failed to merge labels")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+	if !change.DryRun {
+		h.logger.Info().Str("from", body.From).Str("into", body.Into).Int("recipes", len(change.Recipes)).Msg("Labels merged")
+	}
+}
+
+func (h *RecipeHandler) writeTaxonomyError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, recipe.ErrLabelNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "label_not_found", err.Error())
+	case errors.Is(err, recipe.ErrInvalidLabel):
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_label", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
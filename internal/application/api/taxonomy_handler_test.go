@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+var stubTaxonomy = recipe.Taxonomy{
+	{Name: "course", Labels: []recipe.LabelDefinition{
+		{Type: "course", Name: "main"},
+		{Type: "course", Name: "stew"},
+	}},
+}
+
+func TestSaveLabel_AliasTaken(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{taxonomy: stubTaxonomy}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/taxonomy/course/side", strings.NewReader(`{"aliases":["stew"]}`))
+	req.SetPathValue("type", "course")
+	req.SetPathValue("name", "side")
+	rec := httptest.NewRecorder()
+	h.SaveLabel(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRenameLabel(t *testing.T) {
+	svc := &stubRecipeService{taxonomy: stubTaxonomy}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/labels/rename", strings.NewReader(`{"label":"course:main","to":"main_course"}`))
+	rec := httptest.NewRecorder()
+	h.RenameLabel(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if svc.renamed != [2]string{"course:main", "main_course"} {
+		t.Errorf("unexpected rename %v", svc.renamed)
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	svc := &stubRecipeService{taxonomy: stubTaxonomy}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/labels/merge", strings.NewReader(`{"from":"course:stew","into":"course:main"}`))
+	rec := httptest.NewRecorder()
+	h.MergeLabels(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	want := [2]recipe.Label{{Type: "course", Name: "stew"}, {Type: "course", Name: "main"}}
+	if svc.merged != want {
+		t.Errorf("merged %v, want %v", svc.merged, want)
+	}
+}
+
+func TestMergeLabels_BadLabel(t *testing.T) {
+	for body, want := range map[string]int{
+		`{"from":"stew","into":"course:main"}`:           http.StatusBadRequest,
+		`{"from":"course:stew","into":"course:pudding"}`: http.StatusNotFound,
+	} {
+		h := NewRecipeHandler(&stubRecipeService{taxonomy: stubTaxonomy}, &noopLogger{})
+		req := httptest.NewRequest(http.MethodPost, "/api/labels/merge", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.MergeLabels(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", body, want, rec.Code)
+		}
+	}
+}
+
+func TestMergeLabels_DryRun(t *testing.T) {
+	svc := &stubRecipeService{taxonomy: stubTaxonomy}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/labels/merge", strings.NewReader(`{"from":"course:stew","into":"course:main","dryRun":true}`))
+	rec := httptest.NewRecorder()
+	h.MergeLabels(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !svc.dryRun {
+		t.Error("expected the merge to be a dry run")
+	}
+	if !strings.Contains(rec.Body.String(), `"dryRun":true`) || !strings.Contains(rec.Body.String(), `"recipes":[]`) {
+		t.Errorf("expected a dry-run change listing recipes, got %s", rec.Body.String())
+	}
+}
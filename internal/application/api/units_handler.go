@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// PUT /api/units/{name} - Create or update a canonical unit, given as
+// {"abbreviation": "tbsp", "dimension": "volume", "aliases": ["T", "tbs"]}.
+// Recipe lines queued with unit text it now matches take it.
+func (h *RecipeHandler) SaveUnit(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Abbreviation string   `json:"abbreviation"`
+		Dimension    string   `json:"dimension"`
+		Aliases      []string `json:"aliases"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	saved, err := h.recipeService.SaveUnit(r.Context(), recipe.Unit{
+		Name:         r.PathValue("name"),
+		Abbreviation: body.Abbreviation,
+		Dimension:    recipe.UnitDimension(body.Dimension),
+		Aliases:      body.Aliases,
+	})
+	if err != nil {
+		h.writeUnitError(w, err, "unit_save_failed", "Failed to save unit")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+	h.logger.Info().Str("unit", saved.Name).Strs("aliases", saved.Aliases).Msg("Unit saved")
+}
+
+// POST /api/units/merge - Fold one unit into another of the same dimension,
+// given as {"from": "tbs", "into": "tablespoon"}. Recipe lines move over, and
+// from's names become aliases of into.
+func (h *RecipeHandler) MergeUnits(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		From string `json:"from"`
+		Into string `json:"into"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+
+	merged, err := h.recipeService.MergeUnits(r.Context(), body.From, body.Into)
+	if err != nil {
+		h.writeUnitError(w, err, "unit_merge_failed", "Failed to merge units")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merged)
+	h.logger.Info().Str("from", body.From).Str("into", merged.Name).Msg("Units merged")
+}
+
+// GET /api/units/review - Unit text recipe lines used that resolved to no
+// unit, most recently seen first
+func (h *RecipeHandler) ListUnitReviews(w http.ResponseWriter, r *http.Request) {
+	reviews, err := h.recipeService.UnitReviews(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list unit reviews")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "listing_failed", "Failed to list unit reviews")
+		return
+	}
+	if reviews == nil {
+		reviews = []recipe.UnitReview{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"reviews": reviews})
+}
+
+// DELETE /api/units/review/{name} - Drop unit text from the review queue.
+// Lines using it keep it as written.
+func (h *RecipeHandler) DismissUnitReview(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := h.recipeService.DismissUnitReview(r.Context(), name); err != nil {
+		h.writeUnitError(w, err, "unit_review_failed", "Failed to dismiss unit review")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Info().Str("unit", name).Msg("Unit review dismissed")
+}
+
+func (h *RecipeHandler) writeUnitError(w http.ResponseWriter, err error, code, message string) {
+	switch {
+	case errors.Is(err, recipe.ErrUnitNotFound):
+		h.writeErrorResponse(w, http.StatusNotFound, "unit_not_found", err.Error())
+	case errors.Is(err, recipe.ErrInvalidUnit):
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_unit", err.Error())
+	default:
+		h.logger.Error().Err(err).Msg(message)
+		h.writeErrorResponse(w, http.StatusInternalServerError, code, message)
+	}
+}
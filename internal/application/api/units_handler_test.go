@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+var stubUnits = []recipe.Unit{
+	{Name: "tablespoon", Abbreviation: "tbsp", Dimension: recipe.DimensionVolume, Aliases: []string{"T", "tbsp"}},
+	{Name: "teaspoon", Abbreviation: "tsp", Dimension: recipe.DimensionVolume, Aliases: []string{"t", "tsp"}},
+}
+
+func TestSaveUnit(t *testing.T) {
+	for body, want := range map[string]int{
+		`{"abbreviation":"dsp","dimension":"volume","aliases":["dessert spoons"]}`: http.StatusOK,
+		`{"dimension":"volume","aliases":["T"]}`:                                   http.StatusBadRequest,
+		`{"dimension":"weight"}`:                                                   http.StatusBadRequest,
+		`{"dimension":`:                                                            http.StatusBadRequest,
+	} {
+		h := NewRecipeHandler(&stubRecipeService{units: stubUnits}, &noopLogger{})
+		req := httptest.NewRequest(http.MethodPut, "/api/units/dessertspoon", strings.NewReader(body))
+		req.SetPathValue("name", "dessertspoon")
+		rec := httptest.NewRecorder()
+		h.SaveUnit(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d: %s", body, want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestMergeUnits_NotFound(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{units: stubUnits}, &noopLogger{})
+	req := httptest.NewRequest(http.MethodPost, "/api/units/merge", strings.NewReader(`{"from":"tbs","into":"tablespoon"}`))
+	rec := httptest.NewRecorder()
+	h.MergeUnits(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDismissUnitReview(t *testing.T) {
+	svc := &stubRecipeService{unitReviews: []recipe.UnitReview{{Name: "glug", Lines: 2, Recipes: 1}}}
+	h := NewRecipeHandler(svc, &noopLogger{})
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/units/review/glug", nil)
+		req.SetPathValue("name", "glug")
+		rec := httptest.NewRecorder()
+		h.DismissUnitReview(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("expected %d, got %d: %s", want, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// POST /api/recipes/{id}/variants - Fork a recipe. The body takes the same
+// fields as a recipe; a name is required, anything omitted is copied from the
+// parent.
+func (h *RecipeHandler) ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	parentID, ok := h.recipeIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var changes recipe.Recipe
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid_json", "Invalid JSON in request body")
+		return
+	}
+	changes.Name = strings.TrimSpace(changes.Name)
+	if changes.Name == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "missing_name", "A variant needs its own name, e.g. \"Vegan dhal\"")
+		return
+	}
+
+	variant, err := h.recipeService.ForkRecipe(r.Context(), parentID, changes)
+	if err != nil {
+		if errors.Is(err, recipe.ErrRecipeNotFound) {
+			h.writeErrorResponse(w, http.StatusNotFound, "recipe_not_found", "Recipe not found")
+			return
+		}
+		if errors.Is(err, recipe.ErrInvalidSubRecipe) {
+			h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_sub_recipe", err.Error())
+			return
+		}
+		if errors.Is(err, recipe.ErrInvalidLabel) {
+			h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid_label", err.Error())
+			return
+		}
+		h.logger.Error().Err(err).Str("recipe_id", parentID.String()).Msg("Failed to fork recipe")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "fork_failed", "Failed to fork recipe")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(variant)
+
+	h.logger.Info().Str("recipe_id", variant.UUID.String()).Str("parent_id", parentID.String()).Msg("Recipe forked via API")
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func TestForkRecipe_Success(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	body := `{"name":"Vegan dhal","ingredients":[{"ingredient":{"name":"coconut oil"},"quantity":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/variants", strings.NewReader(body))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.ForkRecipe(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var variant recipe.Recipe
+	if err := json.NewDecoder(rec.Body).Decode(&variant); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if variant.Name != "Vegan dhal" || variant.Parent == nil || variant.Parent.UUID.String() != revisionTestRecipeID {
+		t.Errorf("unexpected variant: %+v", variant)
+	}
+}
+
+func TestForkRecipe_MissingName(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/variants", strings.NewReader(`{"description":"no name"}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.ForkRecipe(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestForkRecipe_ParentNotFound(t *testing.T) {
+	h := NewRecipeHandler(&stubRecipeService{err: recipe.RecipeNotFoundError{}}, &noopLogger{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+revisionTestRecipeID+"/variants", strings.NewReader(`{"name":"Vegan dhal"}`))
+	req.SetPathValue("id", revisionTestRecipeID)
+	rec := httptest.NewRecorder()
+	h.ForkRecipe(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
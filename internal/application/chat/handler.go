@@ -18,6 +18,8 @@ import (
 	"google.golang.org/adk/tool/mcptoolset"
 	"google.golang.org/genai"
 
+	"github.com/kieranajp/the-bluer-book/internal/application/mcp"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/config"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
 )
@@ -59,6 +61,9 @@ func NewHandler(cfg config.Config, log logger.Logger, probe Probe) (*Handler, er
 	mcpURL := fmt.Sprintf("http://localhost%s/mcp", cfg.MCPAddr)
 	transport := &gomcp.StreamableClientTransport{
 		Endpoint: mcpURL,
+		HTTPClient: &http.Client{
+			Transport: clientHeaderTransport{base: http.DefaultTransport},
+		},
 	}
 
 	mcpTools, err := mcptoolset.New(mcptoolset.Config{
@@ -185,6 +190,18 @@ func (h *Handler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	writeSSE(w, flusher, chatEvent{Done: true, SessionID: sessionID})
 }
 
+// clientHeaderTransport marks the agent's MCP requests as coming from chat, so
+// recipe revisions it makes are attributed to it.
+type clientHeaderTransport struct {
+	base http.RoundTripper
+}
+
+func (t clientHeaderTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set(mcp.ChatClientHeader, recipe.RevisionSourceChat)
+	return t.base.RoundTrip(r)
+}
+
 func writeSSE(w http.ResponseWriter, flusher http.Flusher, event chatEvent) {
 	data, err := json.Marshal(event)
 	if err != nil {
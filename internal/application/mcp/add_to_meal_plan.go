@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -30,7 +31,16 @@ func (h *RecipeMCPHandler) AddToMealPlan(ctx context.Context, req mcp.CallToolRe
 		return nil, fmt.Errorf("recipe not found: %s", recipeIDStr)
 	}
 
-	err = h.recipeService.AddToMealPlan(ctx, recipeID)
+	var plannedFor *time.Time
+	if dateStr := req.GetString("date", ""); dateStr != "" {
+		day, err := time.Parse(time.DateOnly, dateStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid date %q: use YYYY-MM-DD", dateStr)), nil
+		}
+		plannedFor = &day
+	}
+
+	err = h.recipeService.AddToMealPlan(ctx, recipeID, plannedFor)
 	if err != nil {
 		h.logger.Error().Err(err).Str("recipe_id", recipeIDStr).Msg("Failed to add recipe to meal plan via MCP")
 		return nil, fmt.Errorf("failed to add recipe to meal plan: %w", err)
@@ -44,6 +54,9 @@ func (h *RecipeMCPHandler) AddToMealPlan(ctx context.Context, req mcp.CallToolRe
 		"recipe_id":   recipeIDStr,
 		"recipe_name": existingRecipe.Name,
 	}
+	if plannedFor != nil {
+		response["planned_for"] = plannedFor.Format(time.DateOnly)
+	}
 
 	responseJSON, _ := json.Marshal(response)
 	return mcp.NewToolResultText(string(responseJSON)), nil
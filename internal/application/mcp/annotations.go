@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (h *RecipeMCPHandler) AnnotateRecipe(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, err := h.recipeService.AddAnnotation(ctx, recipeID, recipe.Annotation{
+		Note: req.GetString("note", ""),
+		Step: int16(req.GetInt("step", 0)),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to annotate recipe via MCP")
+		return recipeErrorResult(err, "annotate recipe")
+	}
+
+	h.logger.Info().Str("recipe_id", recipeID.String()).Msg("Recipe annotated via MCP")
+	responseJSON, _ := json.Marshal(map[string]any{
+		"success":    true,
+		"annotation": annotation,
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) RemoveAnnotation(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+	annotationIDStr := req.GetString("annotation_id", "")
+	annotationID, err := uuid.Parse(annotationIDStr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid annotation ID %q: use the uuid from get_recipe's annotations", annotationIDStr)), nil
+	}
+
+	if err := h.recipeService.DeleteAnnotation(ctx, recipeID, annotationID); err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to remove annotation via MCP")
+		return recipeErrorResult(err, "remove annotation")
+	}
+
+	responseJSON, _ := json.Marshal(map[string]any{
+		"success":       true,
+		"annotation_id": annotationID.String(),
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
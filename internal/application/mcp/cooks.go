@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (h *RecipeMCPHandler) LogCook(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cook := recipe.Cook{
+		Rating:   int16(req.GetInt("rating", 0)),
+		Notes:    req.GetString("notes", ""),
+		Servings: int16(req.GetInt("servings", 0)),
+	}
+	if dateStr := req.GetString("date", ""); dateStr != "" {
+		day, err := time.Parse(time.DateOnly, dateStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid date %q: use YYYY-MM-DD", dateStr)), nil
+		}
+		cook.CookedOn = day
+	}
+
+	logged, err := h.recipeService.LogCook(ctx, recipeID, cook)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to log cook via MCP")
+		return recipeErrorResult(err, "log cook")
+	}
+
+	h.logger.Info().Str("recipe_id", recipeID.String()).Msg("Cook logged via MCP")
+	responseJSON, _ := json.Marshal(map[string]any{
+		"success": true,
+		"cook":    logged,
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) ListCooks(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cooks, err := h.recipeService.ListCooks(ctx, recipeID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to list cooks via MCP")
+		return recipeErrorResult(err, "list cooks")
+	}
+	if cooks == nil {
+		cooks = []recipe.Cook{}
+	}
+
+	responseJSON, _ := json.Marshal(map[string]any{
+		"recipe_id": recipeID.String(),
+		"cooks":     cooks,
+		"total":     len(cooks),
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
@@ -3,8 +3,10 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -53,6 +55,11 @@ func (h *RecipeMCPHandler) CreateRecipe(ctx context.Context, req mcp.CallToolReq
 		return nil, fmt.Errorf("invalid labels: %w", err)
 	}
 
+	equipment, err := equipmentArg(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid equipment: %w", err)
+	}
+
 	// Create recipe domain object
 	rec := recipe.Recipe{
 		Name:        name,
@@ -64,10 +71,14 @@ func (h *RecipeMCPHandler) CreateRecipe(ctx context.Context, req mcp.CallToolReq
 		Steps:       steps,
 		Ingredients: ingredients,
 		Labels:      labels,
+		Equipment:   equipment,
 	}
 
 	// Call service layer directly (same as HTTP handler)
 	savedRecipe, err := h.recipeService.CreateRecipe(ctx, rec)
+	if errors.Is(err, recipe.ErrInvalidSubRecipe) || errors.Is(err, recipe.ErrInvalidLabel) {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to create recipe via MCP")
 		return nil, fmt.Errorf("failed to create recipe: %w", err)
@@ -83,6 +94,10 @@ func (h *RecipeMCPHandler) CreateRecipe(ctx context.Context, req mcp.CallToolReq
 		"message":   fmt.Sprintf("Recipe '%s' created successfully", savedRecipe.Name),
 		"recipe":    savedRecipe,
 	}
+	// Saved regardless, but worth the model fixing or raising with the user.
+	if len(savedRecipe.LabelWarnings) > 0 {
+		response["label_warnings"] = savedRecipe.LabelWarnings
+	}
 
 	responseJSON, _ := json.Marshal(response)
 	return mcp.NewToolResultText(string(responseJSON)), nil
@@ -108,7 +123,7 @@ func (h *RecipeMCPHandler) parseIngredients(data []any) ([]recipe.RecipeIngredie
 		preparation, _ := ingredientMap["preparation"].(string)
 		component, _ := ingredientMap["component"].(string)
 
-		ingredients = append(ingredients, recipe.RecipeIngredient{
+		line := recipe.RecipeIngredient{
 			Ingredient: recipe.Ingredient{
 				Name: name,
 			},
@@ -118,7 +133,20 @@ func (h *RecipeMCPHandler) parseIngredients(data []any) ([]recipe.RecipeIngredie
 			Quantity:    quantity,
 			Preparation: preparation,
 			Component:   component,
-		})
+		}
+
+		if subID, _ := ingredientMap["recipe_id"].(string); subID != "" {
+			id, err := uuid.Parse(subID)
+			if err != nil {
+				return nil, fmt.Errorf("ingredient %d has an invalid recipe_id: %s", i, subID)
+			}
+			if line.Unit.Name, ok = recipe.NormalizeSubRecipeUnit(unit); !ok {
+				return nil, fmt.Errorf("ingredient %d uses a recipe, so its unit must be servings or batches, not %q", i, unit)
+			}
+			line.SubRecipe = &recipe.SubRecipe{UUID: id}
+		}
+
+		ingredients = append(ingredients, line)
 	}
 
 	return ingredients, nil
@@ -128,7 +156,7 @@ func (h *RecipeMCPHandler) parseSteps(data []any) ([]recipe.Step, error) {
 	steps := make([]recipe.Step, 0, len(data))
 
 	for i, item := range data {
-		var description string
+		var description, section string
 
 		// Handle both string and object formats
 		switch v := item.(type) {
@@ -140,6 +168,9 @@ func (h *RecipeMCPHandler) parseSteps(data []any) ([]recipe.Step, error) {
 				return nil, fmt.Errorf("step %d must have a description", i)
 			}
 			description = desc
+			if sec, ok := v["section"].(string); ok {
+				section = sec
+			}
 		default:
 			return nil, fmt.Errorf("step %d must be a string or object with description", i)
 		}
@@ -151,6 +182,7 @@ func (h *RecipeMCPHandler) parseSteps(data []any) ([]recipe.Step, error) {
 		steps = append(steps, recipe.Step{
 			Order:       int16(i + 1),
 			Description: description,
+			Section:     section,
 		})
 	}
 
@@ -177,7 +209,7 @@ func (h *RecipeMCPHandler) parseLabels(data []any) ([]recipe.Label, error) {
 
 		typeVal, ok := labelMap["type"].(string)
 		if !ok || typeVal == "" {
-			return nil, fmt.Errorf("label %d must have a type (course|cuisine|diet|method)", i)
+			return nil, fmt.Errorf("label %d must have a type", i)
 		}
 
 		labels = append(labels, recipe.Label{
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+func (h *RecipeMCPHandler) ListEquipment(ctx context.Context, _ mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	items, err := h.recipeService.ListEquipment(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list equipment via MCP")
+		return nil, fmt.Errorf("failed to list equipment: %w", err)
+	}
+	if items == nil {
+		items = []recipe.EquipmentItem{}
+	}
+
+	responseJSON, err := json.Marshal(map[string]any{
+		"equipment": items,
+		"total":     len(items),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode equipment response: %w", err)
+	}
+	return mcplib.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) AddEquipment(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	name, err := requiredTrimmedString(req, "name")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.recipeService.AddEquipment(ctx, name); err != nil {
+		h.logger.Error().Err(err).Str("equipment", name).Msg("Failed to add equipment via MCP")
+		return nil, fmt.Errorf("failed to add equipment: %w", err)
+	}
+	name = recipe.NormalizeEquipmentName(name)
+	return successResult(fmt.Sprintf("Added '%s' to the kitchen's equipment", name), "name", name)
+}
+
+func (h *RecipeMCPHandler) RemoveEquipment(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	name, err := requiredTrimmedString(req, "name")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.recipeService.RemoveEquipment(ctx, name); err != nil {
+		h.logger.Error().Err(err).Str("equipment", name).Msg("Failed to remove equipment via MCP")
+		return nil, fmt.Errorf("failed to remove equipment: %w", err)
+	}
+	name = recipe.NormalizeEquipmentName(name)
+	return successResult(fmt.Sprintf("Removed '%s' from the kitchen's equipment", name), "name", name)
+}
+
+// equipmentArg reads an optional array of equipment names. It returns nil when
+// the argument is absent, so the service suggests equipment from the steps.
+func equipmentArg(args map[string]any) ([]string, error) {
+	return stringListArg(args, "equipment")
+}
+
+// stringListArg reads an optional array of strings, returning nil when it is
+// absent.
+func stringListArg(args map[string]any, key string) ([]string, error) {
+	data, ok := args[key].([]any)
+	if !ok {
+		return nil, nil
+	}
+	items := make([]string, 0, len(data))
+	for i, item := range data {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s %d must be a string", key, i)
+		}
+		items = append(items, s)
+	}
+	return items, nil
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (h *RecipeMCPHandler) ForkRecipe(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	parentID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.GetString("name", ""))
+	if name == "" {
+		return mcp.NewToolResultError("name is required: give the variant its own name, e.g. 'Vegan dhal'"), nil
+	}
+
+	// Only provided fields override the parent; everything else is copied.
+	changes := recipe.Recipe{
+		Name:        name,
+		Description: req.GetString("description", ""),
+		CookTime:    int32(req.GetFloat("cook_time", 0)),
+		PrepTime:    int32(req.GetFloat("prep_time", 0)),
+		Servings:    int16(req.GetFloat("servings", 0)),
+	}
+
+	args := req.GetArguments()
+	if ingredientsData, ok := args["ingredients"].([]any); ok && len(ingredientsData) > 0 {
+		changes.Ingredients, err = h.parseIngredients(ingredientsData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ingredients: %w", err)
+		}
+	}
+	if stepsData, ok := args["steps"].([]any); ok && len(stepsData) > 0 {
+		changes.Steps, err = h.parseSteps(stepsData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid steps: %w", err)
+		}
+	}
+	if labelsData, ok := args["labels"].([]any); ok {
+		changes.Labels, err = h.parseLabels(labelsData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels: %w", err)
+		}
+	}
+
+	changes.Equipment, err = equipmentArg(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid equipment: %w", err)
+	}
+
+	variant, err := h.recipeService.ForkRecipe(ctx, parentID, changes)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", parentID.String()).Msg("Failed to fork recipe via MCP")
+		return recipeErrorResult(err, "fork recipe")
+	}
+
+	h.logger.Info().Str("recipe_id", variant.UUID.String()).Str("parent_id", parentID.String()).Str("name", variant.Name).Msg("Recipe forked via MCP")
+
+	// The saved variant normally carries its parent; fall back to the ID
+	// rather than trusting it to.
+	parentName := parentID.String()
+	if variant.Parent != nil {
+		parentName = variant.Parent.Name
+	}
+	response := map[string]any{
+		"success":             true,
+		"message":             fmt.Sprintf("Created '%s' as a variant of '%s'", variant.Name, parentName),
+		"recipe_id":           variant.UUID.String(),
+		"parent_id":           parentID.String(),
+		"changes_from_parent": variant.ChangesFromParent,
+	}
+	if len(variant.LabelWarnings) > 0 {
+		response["label_warnings"] = variant.LabelWarnings
+	}
+	responseJSON, _ := json.Marshal(response)
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
@@ -33,6 +33,12 @@ func (h *RecipeMCPHandler) GetRecipe(ctx context.Context, req mcp.CallToolReques
 		return nil, fmt.Errorf("recipe not found: %s", recipeIDStr)
 	}
 
+	if req.GetBool("expand_sub_recipes", false) {
+		if err := h.recipeService.ExpandSubRecipes(ctx, recipe); err != nil {
+			return nil, fmt.Errorf("failed to expand sub-recipes: %w", err)
+		}
+	}
+
 	// Format response based on requested section
 	var response any
 	switch section {
@@ -43,11 +49,15 @@ func (h *RecipeMCPHandler) GetRecipe(ctx context.Context, req mcp.CallToolReques
 			"ingredients": recipe.Ingredients,
 		}
 	case "steps":
-		response = map[string]any{
+		steps := map[string]any{
 			"recipe_id": recipe.UUID.String(),
 			"name":      recipe.Name,
 			"steps":     recipe.Steps,
 		}
+		if len(recipe.Annotations) > 0 {
+			steps["annotations"] = recipe.Annotations
+		}
+		response = steps
 	case "summary":
 		response = map[string]any{
 			"recipe_id":   recipe.UUID.String(),
@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"strings"
+
 	pantryservice "github.com/kieranajp/the-bluer-book/internal/domain/pantry/service"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	recipeservice "github.com/kieranajp/the-bluer-book/internal/domain/recipe/service"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -22,6 +25,22 @@ func NewRecipeMCPHandler(recipeService recipeservice.RecipeService, pantryServic
 	}
 }
 
+// stepItemSchema is a step in the create, update and fork tools: either the
+// instruction on its own or an object that also names the step's section.
+var stepItemSchema = map[string]any{
+	"anyOf": []any{
+		map[string]any{"type": "string", "description": "Step instruction"},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"description": map[string]any{"type": "string", "description": "Step instruction"},
+				"section":     map[string]any{"type": "string", "description": "Heading for the phase this step belongs to, e.g. \"Make the dough\". Consecutive steps with the same section are grouped"},
+			},
+			"required": []string{"description"},
+		},
+	},
+}
+
 func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 	// Register create_recipe tool
 	s.AddTool(
@@ -29,7 +48,7 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 			mcp.WithDescription("Create a new recipe with ingredients and steps"),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Recipe name")),
 			mcp.WithString("description", mcp.Description("Recipe description")),
-			mcp.WithArray("ingredients", mcp.Required(), mcp.Description("Array of ingredient objects with name, quantity, unit"),
+			mcp.WithArray("ingredients", mcp.Required(), mcp.Description("Array of ingredient objects with name, quantity, unit, in recipe order. An ingredient used in two places (butter for the pastry and for the filling) gets a line for each, told apart by component"),
 				mcp.Items(map[string]any{
 					"type": "object",
 					"properties": map[string]any{
@@ -38,26 +57,23 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 						"unit":        map[string]any{"type": "string", "description": "Unit of measurement"},
 						"preparation": map[string]any{"type": "string", "description": "Preparation notes"},
 						"component":   map[string]any{"type": "string", "description": "Component this ingredient belongs to, e.g. 'sauce', 'batter', 'filling'"},
+						"recipe_id":   map[string]any{"type": "string", "description": "UUID of another recipe this line uses, e.g. a dough or sauce kept as its own recipe. The unit must then be 'servings' or 'batches'"},
 					},
 					"required": []string{"name"},
 				}),
 			),
-			mcp.WithArray("steps", mcp.Required(), mcp.Description("Array of step instructions in order"),
-				mcp.WithStringItems(mcp.Description("Step instruction")),
+			mcp.WithArray("steps", mcp.Required(), mcp.Description("Array of step instructions in order. A step can be an object with `section` and `description` to group a long method into phases"),
+				mcp.Items(stepItemSchema),
 			),
 			mcp.WithNumber("cook_time", mcp.Description("Cooking time in minutes")),
 			mcp.WithNumber("prep_time", mcp.Description("Prep time in minutes")),
 			mcp.WithNumber("servings", mcp.Description("Number of servings")),
 			mcp.WithString("url", mcp.Description("Source URL for the recipe")),
+			mcp.WithArray("equipment", mcp.Description("Equipment the recipe needs beyond the everyday, lowercase snake_case (e.g. 'wok', 'stand_mixer', 'pasta_machine'). Omit to have it suggested from the steps"),
+				mcp.WithStringItems(),
+			),
 			mcp.WithArray("labels", mcp.Description("Array of typed labels. Each label is an object with `type` and `name`."),
-				mcp.Items(map[string]any{
-					"type": "object",
-					"properties": map[string]any{
-						"type": map[string]any{"type": "string", "enum": []string{"course", "cuisine", "diet", "method"}, "description": "Label type"},
-						"name": map[string]any{"type": "string", "description": "Canonical label name, lowercase snake_case (e.g. 'main', 'indian', 'gluten_free', 'slow_cooked')"},
-					},
-					"required": []string{"type", "name"},
-				}),
+				mcp.Items(labelItemSchema),
 			),
 		),
 		h.CreateRecipe,
@@ -70,6 +86,10 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 			mcp.WithString("query", mcp.Required(), mcp.Description("Search term")),
 			mcp.WithNumber("limit", mcp.DefaultNumber(5), mcp.Max(20), mcp.Description("Maximum number of results")),
 			mcp.WithString("format", mcp.DefaultString("summary"), mcp.Description("Response format: summary or full")),
+			mcp.WithString("sort", mcp.Enum("newest", "name", "time", "not_cooked_recently"), mcp.Description("Result order. not_cooked_recently puts recipes never cooked first, then those cooked longest ago")),
+			mcp.WithBoolean("owned_equipment_only", mcp.Description("Leave out recipes needing equipment the kitchen doesn't have (see list_equipment). Set this when suggesting what to cook")),
+			mcp.WithArray("eaters", mcp.WithStringItems(), mcp.Description("Who's eating: household member names (see list_household), or \"everyone\" for the whole household. Recipes any of them can't eat, from their allergens and diets, are left out")),
+			mcp.WithBoolean("flag_conflicts", mcp.Description("With eaters, keep recipes someone can't eat and list the offending ingredients under conflicts instead of leaving them out")),
 		),
 		h.SearchRecipes,
 	)
@@ -77,9 +97,10 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 	// Register get_recipe tool
 	s.AddTool(
 		mcp.NewTool("get_recipe",
-			mcp.WithDescription("Get a specific recipe by ID"),
+			mcp.WithDescription("Get a specific recipe by ID. Each step carries structured timers (seconds, with maxSeconds for a range, and whether the step is active or hands-off) and temperatures, for running real timers while cooking, and the ingredient lines (with quantities) that go in at that step. Steps in a long method may carry a section heading; consecutive steps with the same section form one phase. Personal notes on the recipe or a step (by step number) are under annotations."),
 			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
-			mcp.WithString("section", mcp.DefaultString("full"), mcp.Description("Section to return: full, ingredients, steps, summary")),
+			mcp.WithString("section", mcp.DefaultString("full"), mcp.Description("Section to return: full, ingredients, steps (with each step's ingredients), summary")),
+			mcp.WithBoolean("expand_sub_recipes", mcp.Description("Include the full, scaled recipe behind any ingredient line that uses another recipe")),
 		),
 		h.GetRecipe,
 	)
@@ -95,7 +116,7 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 			mcp.WithNumber("cook_time", mcp.Description("Updated cook time in minutes")),
 			mcp.WithNumber("servings", mcp.Description("Updated number of servings")),
 			mcp.WithString("url", mcp.Description("Updated source URL for the recipe")),
-			mcp.WithArray("ingredients", mcp.Description("Updated list of ingredient objects with name, quantity, unit, preparation"),
+			mcp.WithArray("ingredients", mcp.Description("Updated list of ingredient objects with name, quantity, unit, preparation, in recipe order. Replaces every line, so include repeated ingredients once per line"),
 				mcp.Items(map[string]any{
 					"type": "object",
 					"properties": map[string]any{
@@ -104,25 +125,85 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 						"unit":        map[string]any{"type": "string", "description": "Unit of measurement"},
 						"preparation": map[string]any{"type": "string", "description": "Preparation notes"},
 						"component":   map[string]any{"type": "string", "description": "Component this ingredient belongs to, e.g. 'sauce', 'batter', 'filling'"},
+						"recipe_id":   map[string]any{"type": "string", "description": "UUID of another recipe this line uses, e.g. a dough or sauce kept as its own recipe. The unit must then be 'servings' or 'batches'"},
 					},
 					"required": []string{"name"},
 				}),
 			),
-			mcp.WithArray("steps", mcp.Description("Updated cooking steps in order"),
-				mcp.WithStringItems(mcp.Description("Step instruction")),
+			mcp.WithArray("steps", mcp.Description("Updated cooking steps in order, as strings or objects with `section` and `description`"),
+				mcp.Items(stepItemSchema),
+			),
+			mcp.WithArray("equipment", mcp.Description("Replaces the recipe's equipment, lowercase snake_case. If omitted while steps are replaced, it is suggested from the new steps"),
+				mcp.WithStringItems(),
 			),
 			mcp.WithArray("labels", mcp.Description("Replaces the recipe's labels. Each label is an object with `type` and `name`."),
+				mcp.Items(labelItemSchema),
+			),
+		),
+		h.UpdateRecipe,
+	)
+
+	s.AddTool(
+		mcp.NewTool("fork_recipe",
+			mcp.WithDescription("Create a variant of an existing recipe (e.g. 'vegan version', 'Gran's original') linked to the original. Use this instead of update_recipe when the user wants to keep the original, and instead of create_recipe so the link is kept. Only provided fields change; everything else is copied from the original."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe to fork")),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name of the variant, e.g. 'Vegan dhal'")),
+			mcp.WithString("description", mcp.Description("Variant description")),
+			mcp.WithNumber("prep_time", mcp.Description("Prep time in minutes")),
+			mcp.WithNumber("cook_time", mcp.Description("Cook time in minutes")),
+			mcp.WithNumber("servings", mcp.Description("Number of servings")),
+			mcp.WithArray("ingredients", mcp.Description("Full ingredient list for the variant, replacing the original's. Omit to keep them"),
 				mcp.Items(map[string]any{
 					"type": "object",
 					"properties": map[string]any{
-						"type": map[string]any{"type": "string", "enum": []string{"course", "cuisine", "diet", "method"}, "description": "Label type"},
-						"name": map[string]any{"type": "string", "description": "Canonical label name, lowercase snake_case"},
+						"name":        map[string]any{"type": "string", "description": "Ingredient name"},
+						"quantity":    map[string]any{"type": "number", "description": "Amount"},
+						"unit":        map[string]any{"type": "string", "description": "Unit of measurement"},
+						"preparation": map[string]any{"type": "string", "description": "Preparation notes"},
+						"component":   map[string]any{"type": "string", "description": "Component this ingredient belongs to, e.g. 'sauce', 'batter', 'filling'"},
+						"recipe_id":   map[string]any{"type": "string", "description": "UUID of another recipe this line uses, e.g. a dough or sauce kept as its own recipe. The unit must then be 'servings' or 'batches'"},
 					},
-					"required": []string{"type", "name"},
+					"required": []string{"name"},
 				}),
 			),
+			mcp.WithArray("steps", mcp.Description("Full method for the variant, replacing the original's, as strings or objects with `section` and `description`. Omit to keep it"),
+				mcp.Items(stepItemSchema),
+			),
+			mcp.WithArray("equipment", mcp.Description("Replaces the copied equipment, lowercase snake_case. If omitted while steps are replaced, it is suggested from the new steps"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithArray("labels", mcp.Description("Replaces the copied labels. Each label is an object with `type` and `name`."),
+				mcp.Items(labelItemSchema),
+			),
 		),
-		h.UpdateRecipe,
+		h.ForkRecipe,
+	)
+
+	s.AddTool(
+		mcp.NewTool("list_labels",
+			mcp.WithDescription("List the label types and the labels allowed under each, with display names, descriptions, aliases and how many recipes carry each. Labels on create_recipe, update_recipe and fork_recipe must come from here; an alias is accepted and saved as its label."),
+		),
+		h.ListLabels,
+	)
+
+	s.AddTool(
+		mcp.NewTool("rename_label",
+			mcp.WithDescription("Rename a label. Recipes carrying it keep it under the new name, and the old name stays as an alias. Run with dry_run first to see the recipes affected, and confirm with the user before renaming."),
+			mcp.WithString("label", mcp.Required(), mcp.Description("The label to rename, as type:name, e.g. 'course:stew'")),
+			mcp.WithString("to", mcp.Required(), mcp.Description("New name, lowercase snake_case. Must not already be a label or alias of the type; merge instead")),
+			mcp.WithBoolean("dry_run", mcp.Description("List the recipes that would be affected without renaming anything")),
+		),
+		h.RenameLabel,
+	)
+
+	s.AddTool(
+		mcp.NewTool("merge_labels",
+			mcp.WithDescription("Fold one label into another of the same type: every recipe carrying from carries into instead, from is deleted, and its name becomes an alias of into. Run with dry_run first to see the recipes affected, and confirm with the user before merging."),
+			mcp.WithString("from", mcp.Required(), mcp.Description("The label to fold away, as type:name, e.g. 'course:stew'")),
+			mcp.WithString("into", mcp.Required(), mcp.Description("The label to keep, as type:name, e.g. 'course:main'")),
+			mcp.WithBoolean("dry_run", mcp.Description("List the recipes that would be re-pointed without merging anything")),
+		),
+		h.MergeLabels,
 	)
 
 	// Register archive_recipe tool
@@ -134,11 +215,78 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 		h.ArchiveRecipe,
 	)
 
+	s.AddTool(
+		mcp.NewTool("list_recipe_revisions",
+			mcp.WithDescription("List a recipe's revision history, newest first. Every create and update is a revision, recording who made it (rest, mcp, chat)."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+		),
+		h.ListRecipeRevisions,
+	)
+
+	s.AddTool(
+		mcp.NewTool("diff_recipe_revisions",
+			mcp.WithDescription("Show what changed between two revisions of a recipe: fields, ingredients, steps and labels added, removed or changed."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+			mcp.WithNumber("from", mcp.Required(), mcp.Description("Older revision number")),
+			mcp.WithNumber("to", mcp.Description("Newer revision number. Omit to compare against the recipe as it is now")),
+		),
+		h.DiffRecipeRevisions,
+	)
+
+	s.AddTool(
+		mcp.NewTool("revert_recipe",
+			mcp.WithDescription("Restore a recipe to an earlier revision, e.g. to undo a mistaken update. The revert is itself a new revision, so it can be undone too."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+			mcp.WithNumber("revision", mcp.Required(), mcp.Description("Revision number to restore")),
+		),
+		h.RevertRecipe,
+	)
+
+	s.AddTool(
+		mcp.NewTool("log_cook",
+			mcp.WithDescription("Record that a recipe was cooked, e.g. \"we made this tonight, loved it\". Use the notes for anything to remember next time (\"needed more salt\")."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe that was cooked")),
+			mcp.WithString("date", mcp.Description("Day it was cooked, as YYYY-MM-DD. Defaults to today")),
+			mcp.WithNumber("rating", mcp.Min(1), mcp.Max(5), mcp.Description("How it went, 1 (never again) to 5 (loved it)")),
+			mcp.WithString("notes", mcp.Description("Free-text notes on how it went or what to change")),
+			mcp.WithNumber("servings", mcp.Description("Servings made")),
+		),
+		h.LogCook,
+	)
+
+	s.AddTool(
+		mcp.NewTool("list_cooks",
+			mcp.WithDescription("List the times a recipe has been cooked, most recent first, with ratings and notes. Check this before suggesting tweaks to a recipe."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+		),
+		h.ListCooks,
+	)
+
+	s.AddTool(
+		mcp.NewTool("annotate_recipe",
+			mcp.WithDescription("Add a personal note to a recipe, or to one of its steps (\"use the big pan\", \"halve the chilli for the kids\"). Notes are kept apart from the recipe and shown with it in get_recipe; use this rather than update_recipe for anything that isn't a change to the recipe itself."),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+			mcp.WithString("note", mcp.Required(), mcp.Description("The note")),
+			mcp.WithNumber("step", mcp.Description("Step number (its order, from 1) the note is about. Omit for a note on the whole recipe")),
+		),
+		h.AnnotateRecipe,
+	)
+
+	s.AddTool(
+		mcp.NewTool("remove_annotation",
+			mcp.WithDescription("Remove a personal note from a recipe"),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+			mcp.WithString("annotation_id", mcp.Required(), mcp.Description("UUID of the note, from get_recipe's annotations")),
+		),
+		h.RemoveAnnotation,
+	)
+
 	// Register add_to_meal_plan tool
 	s.AddTool(
 		mcp.NewTool("add_to_meal_plan",
 			mcp.WithDescription("Add a recipe to the meal plan"),
 			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe to add to the meal plan")),
+			mcp.WithString("date", mcp.Description("Optional day to cook it on, as YYYY-MM-DD. Re-adding with a date reschedules it")),
 		),
 		h.AddToMealPlan,
 	)
@@ -156,21 +304,47 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 	s.AddTool(
 		mcp.NewTool("list_meal_plan",
 			mcp.WithDescription("List all recipes currently in the meal plan"),
+			mcp.WithArray("eaters", mcp.WithStringItems(), mcp.Description("Who's eating: household member names, or \"everyone\". Each recipe then lists, under conflicts, the ingredients any of them can't eat")),
 		),
 		h.ListMealPlan,
 	)
 
+	s.AddTool(
+		mcp.NewTool("list_household",
+			mcp.WithDescription("List the people we cook for, with their allergens and the diets they keep to. Pass their names as eaters to search_recipes and list_meal_plan"),
+		),
+		h.ListHousehold,
+	)
+
+	s.AddTool(
+		mcp.NewTool("save_person",
+			mcp.WithDescription("Add a household member, or replace one of the same name, with what they can't eat"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Their name (case-insensitive)")),
+			mcp.WithArray("allergens", mcp.WithStringItems(), mcp.Description("What they can't eat at all, from: "+strings.Join(recipe.DietFlagNames(), ", ")+". A coeliac avoids gluten")),
+			mcp.WithArray("diets", mcp.WithStringItems(), mcp.Description("Diets they keep to, from: "+strings.Join(recipe.DietNames(), ", "))),
+		),
+		h.SavePerson,
+	)
+
+	s.AddTool(
+		mcp.NewTool("remove_person",
+			mcp.WithDescription("Remove a household member"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Their name (case-insensitive)")),
+		),
+		h.RemovePerson,
+	)
+
 	s.AddTool(
 		mcp.NewTool("list_pantry",
-			mcp.WithDescription("List all ingredients currently in the pantry"),
+			mcp.WithDescription("List all ingredients currently in the pantry, including staples (staple: true) that haven't run out"),
 		),
 		h.ListPantry,
 	)
 
 	s.AddTool(
 		mcp.NewTool("add_to_pantry",
-			mcp.WithDescription("Mark an ingredient as currently available in the pantry. Only works for ingredients used by a recipe in the book — for anything else use add_to_shopping_list. Fails if the name matches no ingredient."),
-			mcp.WithString("ingredient", mcp.Required(), mcp.Description("Ingredient name, as spelled in a recipe's ingredient list (case-insensitive)")),
+			mcp.WithDescription("Mark an ingredient as currently available in the pantry. The name is matched to the book's ingredients allowing for case, plurals and aliases; a name no recipe uses yet (\"tinned tomatoes\") is added as a new pantry-only ingredient, which recipes using that name later share."),
+			mcp.WithString("ingredient", mcp.Required(), mcp.Description("Ingredient name, ideally as spelled in a recipe's ingredient list (case-insensitive)")),
 		),
 		h.AddToPantry,
 	)
@@ -183,6 +357,54 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 		h.RemoveFromPantry,
 	)
 
+	s.AddTool(
+		mcp.NewTool("set_pantry_staple",
+			mcp.WithDescription("Make an ingredient a staple (salt, olive oil, ...): assumed always in the pantry and kept off the shopping list unless marked out. Set staple to false to stop treating it as one. Fails if the name matches no ingredient in the book."),
+			mcp.WithString("ingredient", mcp.Required(), mcp.Description("Ingredient name, as spelled in a recipe's ingredient list (case-insensitive)")),
+			mcp.WithBoolean("staple", mcp.Description("Whether it's a staple (default true)")),
+		),
+		h.SetPantryStaple,
+	)
+
+	s.AddTool(
+		mcp.NewTool("mark_out_of_stock",
+			mcp.WithDescription("Say we've run out of an ingredient, e.g. \"we're out of olive oil\". It leaves the pantry; a staple also counts as missing, so a planned recipe puts it on the shopping list, until add_to_pantry stocks it again. Fails if the name matches no ingredient in the book."),
+			mcp.WithString("ingredient", mcp.Required(), mcp.Description("Ingredient name, as spelled in a recipe's ingredient list (case-insensitive)")),
+		),
+		h.MarkOutOfStock,
+	)
+
+	s.AddTool(
+		mcp.NewTool("get_nutrition",
+			mcp.WithDescription("Estimate a recipe's nutrition (calories, protein, fat, carbs, fibre, salt) in total and per serving. coverage is the percentage of ingredient lines that could be counted; unmatched lists the rest and why. Treat low coverage as a rough guide only. lowCalorie is given when the estimate is reliable enough to label by"),
+			mcp.WithString("recipe_id", mcp.Required(), mcp.Description("UUID of the recipe")),
+		),
+		h.GetNutrition,
+	)
+
+	s.AddTool(
+		mcp.NewTool("list_equipment",
+			mcp.WithDescription("List the kitchen equipment the household owns (wok, stand_mixer, ...). Recipes list the equipment they need; search_recipes can leave out those needing anything not owned"),
+		),
+		h.ListEquipment,
+	)
+
+	s.AddTool(
+		mcp.NewTool("add_equipment",
+			mcp.WithDescription("Record that the kitchen has a piece of equipment"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Equipment name, e.g. 'slow cooker' (stored as slow_cooker)")),
+		),
+		h.AddEquipment,
+	)
+
+	s.AddTool(
+		mcp.NewTool("remove_equipment",
+			mcp.WithDescription("Record that the kitchen no longer has a piece of equipment"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Equipment name, e.g. 'pasta machine'")),
+		),
+		h.RemoveEquipment,
+	)
+
 	s.AddTool(
 		mcp.NewTool("list_shopping_list",
 			mcp.WithDescription("List missing meal-plan ingredients and custom shopping-list items"),
@@ -192,15 +414,16 @@ func (h *RecipeMCPHandler) RegisterTools(s *server.MCPServer) {
 
 	s.AddTool(
 		mcp.NewTool("add_to_shopping_list",
-			mcp.WithDescription("Add a custom free-text item to the shopping list"),
+			mcp.WithDescription("Add an item to the shopping list. The name is matched against the book's ingredients, allowing for case, plurals and aliases; a name only close to some (a typo, shared words) is never resolved automatically, even when one ingredient is a letter off, since \"beer\" isn't beef: it is not added and the candidates are returned to choose from."),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Shopping-list item name")),
+			mcp.WithBoolean("custom", mcp.Description("Add the name as written, without matching it to an ingredient")),
 		),
 		h.AddToShoppingList,
 	)
 
 	s.AddTool(
 		mcp.NewTool("remove_from_shopping_list",
-			mcp.WithDescription("Remove a custom free-text item from the shopping list"),
+			mcp.WithDescription("Remove an item added to the shopping list by hand"),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Shopping-list item name")),
 		),
 		h.RemoveFromShoppingList,
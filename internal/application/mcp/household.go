@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+func (h *RecipeMCPHandler) ListHousehold(ctx context.Context, _ mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	people, err := h.recipeService.ListPeople(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list household via MCP")
+		return nil, fmt.Errorf("failed to list household: %w", err)
+	}
+	if people == nil {
+		people = []recipe.Person{}
+	}
+
+	responseJSON, err := json.Marshal(map[string]any{
+		"people": people,
+		"total":  len(people),
+		"diets":  recipe.DietNames(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode household response: %w", err)
+	}
+	return mcplib.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) SavePerson(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	name, err := requiredTrimmedString(req, "name")
+	if err != nil {
+		return nil, err
+	}
+	args := req.GetArguments()
+	p := recipe.Person{Name: name}
+	allergens, err := stringListArg(args, "allergens")
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range allergens {
+		p.Allergens = append(p.Allergens, recipe.DietFlag(a))
+	}
+	if p.Diets, err = stringListArg(args, "diets"); err != nil {
+		return nil, err
+	}
+
+	saved, err := h.recipeService.SavePerson(ctx, p)
+	if err != nil {
+		h.logger.Error().Err(err).Str("person", name).Msg("Failed to save person via MCP")
+		return recipeErrorResult(err, "save person")
+	}
+
+	responseJSON, err := json.Marshal(map[string]any{
+		"success": true,
+		"message": fmt.Sprintf("Saved %s", saved.Name),
+		"person":  saved,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode person response: %w", err)
+	}
+	return mcplib.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) RemovePerson(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	name, err := requiredTrimmedString(req, "name")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.recipeService.DeletePerson(ctx, name); err != nil {
+		h.logger.Error().Err(err).Str("person", name).Msg("Failed to remove person via MCP")
+		return recipeErrorResult(err, "remove person")
+	}
+	return successResult(fmt.Sprintf("Removed %s from the household", name), "name", name)
+}
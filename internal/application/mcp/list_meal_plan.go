@@ -3,13 +3,23 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func (h *RecipeMCPHandler) ListMealPlan(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	recipes, err := h.recipeService.ListMealPlanRecipes(ctx)
+	eaters, err := stringListArg(req.GetArguments(), "eaters")
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, err := h.recipeService.ListMealPlanRecipes(ctx, eaters)
+	if errors.Is(err, recipe.ErrPersonNotFound) {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to list meal plan recipes via MCP")
 		return nil, fmt.Errorf("failed to list meal plan: %w", err)
@@ -25,6 +35,9 @@ func (h *RecipeMCPHandler) ListMealPlan(ctx context.Context, req mcp.CallToolReq
 			"prep_time":   r.PrepTime,
 			"servings":    r.Servings,
 		}
+		if len(r.Conflicts) > 0 {
+			summaries[i]["conflicts"] = r.Conflicts
+		}
 	}
 
 	response := map[string]any{
@@ -0,0 +1,27 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (h *RecipeMCPHandler) GetNutrition(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+
+	est, err := h.recipeService.Nutrition(ctx, recipeID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to estimate nutrition via MCP")
+		return recipeErrorResult(err, "estimate nutrition")
+	}
+
+	responseJSON, _ := json.Marshal(map[string]any{
+		"recipe_id": recipeID.String(),
+		"nutrition": est,
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
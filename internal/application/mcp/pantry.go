@@ -36,15 +36,15 @@ func (h *RecipeMCPHandler) AddToPantry(ctx context.Context, req mcplib.CallToolR
 	if err != nil {
 		return nil, err
 	}
-	if err := h.pantryService.AddToPantry(ctx, ingredient); err != nil {
-		if errors.Is(err, pantry.ErrIngredientNotFound) {
-			h.logger.Warn().Str("ingredient", ingredient).Msg("Unknown ingredient for pantry add via MCP")
-			return unknownIngredientResult(ingredient), nil
-		}
+	name, created, err := h.pantryService.AddToPantry(ctx, ingredient)
+	if err != nil {
 		h.logger.Error().Err(err).Str("ingredient", ingredient).Msg("Failed to add ingredient to pantry via MCP")
 		return nil, fmt.Errorf("failed to add ingredient to pantry: %w", err)
 	}
-	return successResult(fmt.Sprintf("Added '%s' to the pantry", ingredient), "ingredient", ingredient)
+	if created {
+		return successResult(fmt.Sprintf("Added '%s' to the pantry as a new ingredient; no recipe uses it yet", name), "ingredient", name)
+	}
+	return successResult(fmt.Sprintf("Added '%s' to the pantry", name), "ingredient", name)
 }
 
 func (h *RecipeMCPHandler) RemoveFromPantry(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
@@ -63,6 +63,47 @@ func (h *RecipeMCPHandler) RemoveFromPantry(ctx context.Context, req mcplib.Call
 	return successResult(fmt.Sprintf("Removed '%s' from the pantry", ingredient), "ingredient", ingredient)
 }
 
+func (h *RecipeMCPHandler) SetPantryStaple(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	ingredient, err := requiredTrimmedString(req, "ingredient")
+	if err != nil {
+		return nil, err
+	}
+	staple := req.GetBool("staple", true)
+	name, err := h.pantryService.SetStaple(ctx, ingredient, staple)
+	if err != nil {
+		if errors.Is(err, pantry.ErrIngredientNotFound) {
+			h.logger.Warn().Str("ingredient", ingredient).Msg("Unknown ingredient for staple via MCP")
+			return unknownIngredientResult(ingredient), nil
+		}
+		h.logger.Error().Err(err).Str("ingredient", ingredient).Msg("Failed to set staple via MCP")
+		return nil, fmt.Errorf("failed to set staple: %w", err)
+	}
+	if !staple {
+		return successResult(fmt.Sprintf("'%s' is no longer a staple", name), "ingredient", name)
+	}
+	return successResult(fmt.Sprintf("'%s' is now a staple: always in stock unless marked out", name), "ingredient", name)
+}
+
+func (h *RecipeMCPHandler) MarkOutOfStock(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	ingredient, err := requiredTrimmedString(req, "ingredient")
+	if err != nil {
+		return nil, err
+	}
+	name, staple, err := h.pantryService.MarkOut(ctx, ingredient)
+	if err != nil {
+		if errors.Is(err, pantry.ErrIngredientNotFound) {
+			h.logger.Warn().Str("ingredient", ingredient).Msg("Unknown ingredient for mark out via MCP")
+			return unknownIngredientResult(ingredient), nil
+		}
+		h.logger.Error().Err(err).Str("ingredient", ingredient).Msg("Failed to mark ingredient out via MCP")
+		return nil, fmt.Errorf("failed to mark ingredient out: %w", err)
+	}
+	if staple {
+		return successResult(fmt.Sprintf("Marked staple '%s' as out until it's added to the pantry again", name), "ingredient", name)
+	}
+	return successResult(fmt.Sprintf("Removed '%s' from the pantry", name), "ingredient", name)
+}
+
 func (h *RecipeMCPHandler) ListShoppingList(ctx context.Context, _ mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
 	items, err := h.pantryService.ShoppingList(ctx)
 	if err != nil {
@@ -88,10 +129,25 @@ func (h *RecipeMCPHandler) AddToShoppingList(ctx context.Context, req mcplib.Cal
 	if err != nil {
 		return nil, err
 	}
-	if err := h.pantryService.AddCustomShoppingItem(ctx, name); err != nil {
-		h.logger.Error().Err(err).Str("name", name).Msg("Failed to add custom shopping-list item via MCP")
+	if req.GetBool("custom", false) {
+		if err := h.pantryService.AddCustomShoppingItem(ctx, name); err != nil {
+			h.logger.Error().Err(err).Str("name", name).Msg("Failed to add custom shopping-list item via MCP")
+			return nil, fmt.Errorf("failed to add shopping-list item: %w", err)
+		}
+		return successResult(fmt.Sprintf("Added '%s' to the shopping list", name), "name", name)
+	}
+
+	additions, err := h.pantryService.AddShoppingItems(ctx, []string{name})
+	if err != nil {
+		h.logger.Error().Err(err).Str("name", name).Msg("Failed to add shopping-list item via MCP")
 		return nil, fmt.Errorf("failed to add shopping-list item: %w", err)
 	}
+	if len(additions.NeedsReview) > 0 {
+		return needsReviewResult(additions.NeedsReview[0]), nil
+	}
+	if len(additions.Added) > 0 {
+		name = additions.Added[0].Name
+	}
 	return successResult(fmt.Sprintf("Added '%s' to the shopping list", name), "name", name)
 }
 
@@ -109,15 +165,24 @@ func (h *RecipeMCPHandler) RemoveFromShoppingList(ctx context.Context, req mcpli
 
 // unknownIngredientResult tells the caller the pantry didn't change and why.
 // It's a tool error rather than a transport error so the model reads the text
-// and can correct itself — the usual fix is a name lifted from a recipe, or
-// add_to_shopping_list for something that isn't a recipe ingredient at all.
+// and can correct itself — the usual fix is the name as list_pantry or a
+// recipe spells it.
 func unknownIngredientResult(ingredient string) *mcplib.CallToolResult {
 	return mcplib.NewToolResultError(fmt.Sprintf(
-		"No ingredient called '%s' exists, so the pantry was not changed. The pantry only holds ingredients that recipes in the book already use — check the spelling against a recipe's ingredient list, or use add_to_shopping_list if this isn't a recipe ingredient.",
+		"No ingredient called '%s' exists, so the pantry was not changed. Check the spelling against list_pantry or a recipe's ingredient list.",
 		ingredient,
 	))
 }
 
+// needsReviewResult tells the caller a name could mean several ingredients,
+// so nothing was added, and how to settle it.
+func needsReviewResult(review pantry.ShoppingReview) *mcplib.CallToolResult {
+	return mcplib.NewToolResultError(fmt.Sprintf(
+		"'%s' could mean any of: %s. Nothing was added — call add_to_shopping_list again with one of those names, or with custom set to true to add '%s' as written.",
+		review.Name, strings.Join(review.Candidates, ", "), review.Name,
+	))
+}
+
 func requiredTrimmedString(req mcplib.CallToolRequest, key string) (string, error) {
 	value := strings.TrimSpace(req.GetString(key, ""))
 	if value == "" {
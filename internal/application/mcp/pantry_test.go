@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
 	mcplib "github.com/mark3labs/mcp-go/mcp"
@@ -19,12 +21,17 @@ type stubPantryService struct {
 	removedPantry   string
 	addedShopping   string
 	removedShopping string
+	reviews         map[string][]string
+	newIngredient   bool
+	staple          string
+	stapled         bool
+	out             string
 	err             error
 }
 
-func (s *stubPantryService) AddToPantry(_ context.Context, ingredient string) error {
+func (s *stubPantryService) AddToPantry(_ context.Context, ingredient string) (string, bool, error) {
 	s.addedPantry = ingredient
-	return s.err
+	return ingredient, s.newIngredient, s.err
 }
 
 func (s *stubPantryService) RemoveFromPantry(_ context.Context, ingredient string) error {
@@ -36,6 +43,20 @@ func (s *stubPantryService) ListPantry(context.Context) ([]pantry.PantryItem, er
 	return s.pantryItems, s.err
 }
 
+func (s *stubPantryService) Staples(context.Context) ([]pantry.Staple, error) {
+	return nil, s.err
+}
+
+func (s *stubPantryService) SetStaple(_ context.Context, ingredient string, staple bool) (string, error) {
+	s.staple, s.stapled = ingredient, staple
+	return ingredient, s.err
+}
+
+func (s *stubPantryService) MarkOut(_ context.Context, ingredient string) (string, bool, error) {
+	s.out = ingredient
+	return ingredient, true, s.err
+}
+
 func (s *stubPantryService) ShoppingList(context.Context) ([]pantry.ShoppingListItem, error) {
 	return s.shoppingList, s.err
 }
@@ -45,11 +66,69 @@ func (s *stubPantryService) AddCustomShoppingItem(_ context.Context, name string
 	return s.err
 }
 
+func (s *stubPantryService) AddShoppingItems(_ context.Context, names []string) (pantry.ShoppingAdditions, error) {
+	result := pantry.ShoppingAdditions{}
+	for _, name := range names {
+		if candidates := s.reviews[name]; len(candidates) > 0 {
+			result.NeedsReview = append(result.NeedsReview, pantry.ShoppingReview{Name: name, Candidates: candidates})
+			continue
+		}
+		s.addedShopping = name
+		result.Added = append(result.Added, pantry.ShoppingListItem{Name: name, Source: pantry.ShoppingSourceCustom})
+	}
+	return result, s.err
+}
+
+func (s *stubPantryService) AddIngredientShoppingItem(_ context.Context, ingredient string) (string, error) {
+	s.addedShopping = ingredient
+	return ingredient, s.err
+}
+
 func (s *stubPantryService) RemoveCustomShoppingItem(_ context.Context, name string) error {
 	s.removedShopping = name
 	return s.err
 }
 
+func (s *stubPantryService) SetShoppingItemChecked(context.Context, string, bool) error {
+	return s.err
+}
+
+func (s *stubPantryService) CompleteShoppingTrip(context.Context) (pantry.ShoppingTrip, error) {
+	return pantry.ShoppingTrip{}, s.err
+}
+
+func (s *stubPantryService) ShoppingTrips(context.Context, int) ([]pantry.ShoppingTrip, error) {
+	return nil, s.err
+}
+
+func (s *stubPantryService) ShoppingAisles(context.Context) ([]pantry.ShoppingSection, error) {
+	return pantry.GroupByAisle(s.shoppingList, nil), s.err
+}
+
+func (s *stubPantryService) SetIngredientCategory(context.Context, string, string) error {
+	return s.err
+}
+
+func (s *stubPantryService) SetShoppingItemCategory(context.Context, string, string) error {
+	return s.err
+}
+
+func (s *stubPantryService) StoreLayout(context.Context) ([]string, error) {
+	return pantry.StoreLayout(nil).Order(), s.err
+}
+
+func (s *stubPantryService) SaveStoreLayout(_ context.Context, categories []string) ([]string, error) {
+	return categories, s.err
+}
+
+func (s *stubPantryService) ShareList(_ context.Context, list string, _ time.Duration) (pantry.ListShare, error) {
+	return pantry.ListShare{Token: "tok", List: list}, s.err
+}
+
+func (s *stubPantryService) GetShare(_ context.Context, token string) (pantry.ListShare, error) {
+	return pantry.ListShare{}, pantry.ErrShareNotFound
+}
+
 type noopLogger struct{}
 
 var testLogger = zerolog.Nop()
@@ -136,6 +215,30 @@ func TestPantryAndShoppingListMutations(t *testing.T) {
 			got:  func(s *stubPantryService) string { return s.removedPantry },
 			want: "salt",
 		},
+		{
+			name: "set staple",
+			call: func(h *RecipeMCPHandler) (*mcplib.CallToolResult, error) {
+				return h.SetPantryStaple(context.Background(), toolRequest(map[string]any{"ingredient": " salt "}))
+			},
+			got:  func(s *stubPantryService) string { return fmt.Sprintf("%s %t", s.staple, s.stapled) },
+			want: "salt true",
+		},
+		{
+			name: "unset staple",
+			call: func(h *RecipeMCPHandler) (*mcplib.CallToolResult, error) {
+				return h.SetPantryStaple(context.Background(), toolRequest(map[string]any{"ingredient": "salt", "staple": false}))
+			},
+			got:  func(s *stubPantryService) string { return fmt.Sprintf("%s %t", s.staple, s.stapled) },
+			want: "salt false",
+		},
+		{
+			name: "mark out",
+			call: func(h *RecipeMCPHandler) (*mcplib.CallToolResult, error) {
+				return h.MarkOutOfStock(context.Background(), toolRequest(map[string]any{"ingredient": "olive oil"}))
+			},
+			got:  func(s *stubPantryService) string { return s.out },
+			want: "olive oil",
+		},
 		{
 			name: "add shopping item",
 			call: func(h *RecipeMCPHandler) (*mcplib.CallToolResult, error) {
@@ -185,19 +288,13 @@ func TestPantryToolsValidateAndWrapErrors(t *testing.T) {
 	}
 }
 
-// The original bug: an ingredient name matching nothing was reported back as a
-// successful add, so the pantry looked like it had silently dropped the item.
+// An ingredient name matching nothing must not read as a successful change,
+// or the pantry looks like it silently ignored the request.
 func TestPantryMutationsReportUnknownIngredient(t *testing.T) {
 	tests := []struct {
 		name string
 		call func(*RecipeMCPHandler) (*mcplib.CallToolResult, error)
 	}{
-		{
-			name: "add pantry",
-			call: func(h *RecipeMCPHandler) (*mcplib.CallToolResult, error) {
-				return h.AddToPantry(context.Background(), toolRequest(map[string]any{"ingredient": "unobtainium"}))
-			},
-		},
 		{
 			name: "remove pantry",
 			call: func(h *RecipeMCPHandler) (*mcplib.CallToolResult, error) {
@@ -227,6 +324,21 @@ func TestPantryMutationsReportUnknownIngredient(t *testing.T) {
 	}
 }
 
+// The pantry takes names no recipe uses, saying so rather than refusing them.
+func TestAddToPantryNewIngredient(t *testing.T) {
+	svc := &stubPantryService{newIngredient: true}
+	handler := NewRecipeMCPHandler(nil, svc, noopLogger{})
+
+	result, err := handler.AddToPantry(context.Background(), toolRequest(map[string]any{"ingredient": "tinned tomatoes"}))
+	if err != nil {
+		t.Fatalf("AddToPantry() error = %v", err)
+	}
+	body := resultJSON(t, result)
+	if body["ingredient"] != "tinned tomatoes" || !strings.Contains(body["message"].(string), "new ingredient") {
+		t.Errorf("result = %v, want tinned tomatoes added as a new ingredient", body)
+	}
+}
+
 // A genuine fault still surfaces as a transport error, not as a polite "no such
 // ingredient" the model would take at face value.
 func TestPantryMutationsWrapServiceFaults(t *testing.T) {
@@ -139,3 +139,26 @@ func TestParseIngredients_MissingName(t *testing.T) {
 		t.Fatal("expected error for missing name, got nil")
 	}
 }
+
+func TestParseSteps_WithSections(t *testing.T) {
+	h := newTestHandler()
+
+	steps, err := h.parseSteps([]any{
+		map[string]any{"section": "Make the dough", "description": "Mix and knead."},
+		map[string]any{"section": "Next day: shape and bake", "description": "Shape the loaf."},
+		"Cool before slicing.",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+	if steps[0].Section != "Make the dough" || steps[1].Section != "Next day: shape and bake" {
+		t.Errorf("unexpected sections: %q, %q", steps[0].Section, steps[1].Section)
+	}
+	if steps[2].Section != "" || steps[2].Order != 3 {
+		t.Errorf("expected a plain string step without a section, got %+v", steps[2])
+	}
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// recipeIDArg parses the required recipe_id argument.
+func recipeIDArg(req mcp.CallToolRequest) (uuid.UUID, error) {
+	recipeIDStr := req.GetString("recipe_id", "")
+	if recipeIDStr == "" {
+		return uuid.Nil, fmt.Errorf("recipe_id is required")
+	}
+	recipeID, err := uuid.Parse(recipeIDStr)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid recipe ID format: %s", recipeIDStr)
+	}
+	return recipeID, nil
+}
+
+// recipeErrorResult turns the caller's mistakes (unknown recipe, revision or
+// annotation, a bad sub-recipe, an out-of-range rating, an empty note) into
+// tool errors the model can act on; anything else is a real failure.
+func recipeErrorResult(err error, action string) (*mcp.CallToolResult, error) {
+	if errors.Is(err, recipe.ErrRecipeNotFound) || errors.Is(err, recipe.ErrRevisionNotFound) ||
+		errors.Is(err, recipe.ErrInvalidSubRecipe) || errors.Is(err, recipe.ErrInvalidCook) ||
+		errors.Is(err, recipe.ErrAnnotationNotFound) || errors.Is(err, recipe.ErrInvalidAnnotation) ||
+		errors.Is(err, recipe.ErrPersonNotFound) || errors.Is(err, recipe.ErrInvalidPerson) ||
+		errors.Is(err, recipe.ErrLabelNotFound) || errors.Is(err, recipe.ErrInvalidLabel) {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return nil, fmt.Errorf("failed to %s: %w", action, err)
+}
+
+func (h *RecipeMCPHandler) ListRecipeRevisions(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := h.recipeService.ListRevisions(ctx, recipeID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to list revisions via MCP")
+		return recipeErrorResult(err, "list revisions")
+	}
+	if revisions == nil {
+		revisions = []recipe.Revision{}
+	}
+
+	responseJSON, _ := json.Marshal(map[string]any{
+		"recipe_id": recipeID.String(),
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) DiffRecipeRevisions(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+	from := req.GetInt("from", 0)
+	if from < 1 {
+		return mcp.NewToolResultError("from must be a revision number (see list_recipe_revisions)"), nil
+	}
+	to := req.GetInt("to", 0)
+
+	diff, err := h.recipeService.DiffRevisions(ctx, recipeID, from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Msg("Failed to diff revisions via MCP")
+		return recipeErrorResult(err, "diff revisions")
+	}
+
+	var toOut any = to
+	if to == 0 {
+		toOut = "current"
+	}
+	responseJSON, _ := json.Marshal(map[string]any{
+		"recipe_id": recipeID.String(),
+		"from":      from,
+		"to":        toOut,
+		"unchanged": diff.IsEmpty(),
+		"diff":      diff,
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) RevertRecipe(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	recipeID, err := recipeIDArg(req)
+	if err != nil {
+		return nil, err
+	}
+	rev := req.GetInt("revision", 0)
+	if rev < 1 {
+		return mcp.NewToolResultError("revision must be a revision number (see list_recipe_revisions)"), nil
+	}
+
+	reverted, err := h.recipeService.RevertToRevision(ctx, recipeID, rev)
+	if err != nil {
+		h.logger.Error().Err(err).Str("recipe_id", recipeID.String()).Int("revision", rev).Msg("Failed to revert recipe via MCP")
+		return recipeErrorResult(err, "revert recipe")
+	}
+
+	h.logger.Info().Str("recipe_id", recipeID.String()).Int("revision", rev).Msg("Recipe reverted via MCP")
+
+	responseJSON, _ := json.Marshal(map[string]any{
+		"success":     true,
+		"message":     fmt.Sprintf("Reverted '%s' to revision %d", reverted.Name, rev),
+		"recipe_id":   recipeID.String(),
+		"recipe_name": reverted.Name,
+	})
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// ChatClientHeader is set by the in-process chat agent on its MCP requests so
+// that revisions it makes are attributed to chat rather than to an external
+// MCP client.
+const ChatClientHeader = "X-Bluer-Book-Client"
+
+// RevisionSourceContext tags each MCP request's context with the revision
+// source for any recipe changes it makes. Pass to
+// server.WithHTTPContextFunc.
+func RevisionSourceContext(ctx context.Context, r *http.Request) context.Context {
+	if r.Header.Get(ChatClientHeader) == recipe.RevisionSourceChat {
+		return recipe.WithRevisionSource(ctx, recipe.RevisionSourceChat)
+	}
+	return recipe.WithRevisionSource(ctx, recipe.RevisionSourceMCP)
+}
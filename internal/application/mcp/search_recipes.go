@@ -3,8 +3,10 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -12,9 +14,22 @@ func (h *RecipeMCPHandler) SearchRecipes(ctx context.Context, req mcp.CallToolRe
 	query := req.GetString("query", "")
 	limit := req.GetInt("limit", 5)
 	format := req.GetString("format", "summary")
+	sort := req.GetString("sort", "")
+	ownedEquipmentOnly := req.GetBool("owned_equipment_only", false)
+	if sort == "newest" {
+		sort = ""
+	}
+	names, err := stringListArg(req.GetArguments(), "eaters")
+	if err != nil {
+		return nil, err
+	}
+	eaters := recipe.Eaters{Names: names, Exclude: !req.GetBool("flag_conflicts", false)}
 
 	// Call service layer directly
-	recipes, total, err := h.recipeService.ListRecipes(ctx, limit, 0, query, nil, "")
+	recipes, total, err := h.recipeService.ListRecipes(ctx, limit, 0, query, nil, sort, ownedEquipmentOnly, eaters)
+	if errors.Is(err, recipe.ErrPersonNotFound) {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to search recipes via MCP")
 		return nil, fmt.Errorf("search failed: %w", err)
@@ -33,6 +48,15 @@ func (h *RecipeMCPHandler) SearchRecipes(ctx context.Context, req mcp.CallToolRe
 				"prep_time":   recipe.PrepTime,
 				"servings":    recipe.Servings,
 			}
+			if recipe.CookStats != nil {
+				summaries[i]["cook_stats"] = recipe.CookStats
+			}
+			if len(recipe.Equipment) > 0 {
+				summaries[i]["equipment"] = recipe.Equipment
+			}
+			if len(recipe.Conflicts) > 0 {
+				summaries[i]["conflicts"] = recipe.Conflicts
+			}
 		}
 		response = map[string]any{
 			"recipes": summaries,
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+)
+
+// labelItemSchema is a label in the create, update and fork tools. Its type
+// and name are left open rather than enumerated: the taxonomy changes while
+// the server runs, and saves check labels against it as it stands.
+var labelItemSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"type": map[string]any{"type": "string", "description": "Label type. Call list_labels for the types"},
+		"name": map[string]any{"type": "string", "description": "Canonical label name, lowercase snake_case. Call list_labels for the names allowed under each type"},
+	},
+	"required": []string{"type", "name"},
+}
+
+func (h *RecipeMCPHandler) ListLabels(ctx context.Context, _ mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	taxonomy, err := h.recipeService.Taxonomy(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to load taxonomy via MCP")
+		return nil, fmt.Errorf("failed to load taxonomy: %w", err)
+	}
+	if taxonomy == nil {
+		taxonomy = recipe.Taxonomy{}
+	}
+
+	responseJSON, err := json.Marshal(map[string]any{"types": taxonomy})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode taxonomy response: %w", err)
+	}
+	return mcplib.NewToolResultText(string(responseJSON)), nil
+}
+
+func (h *RecipeMCPHandler) RenameLabel(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	raw, err := requiredTrimmedString(req, "label")
+	if err != nil {
+		return nil, err
+	}
+	to, err := requiredTrimmedString(req, "to")
+	if err != nil {
+		return nil, err
+	}
+	label, err := recipe.ParseLabel(raw)
+	if err != nil {
+		return mcplib.NewToolResultError(err.Error()), nil
+	}
+
+	change, err := h.recipeService.RenameLabel(ctx, label, to, req.GetBool("dry_run", false))
+	if err != nil {
+		h.logger.Error().Err(err).Str("label", raw).Msg("Failed to rename label via MCP")
+		return recipeErrorResult(err, "rename label")
+	}
+	return labelChangeResult(change, fmt.Sprintf("Renamed %s:%s to %s:%s", change.From.Type, change.From.Name, change.Label.Type, change.Label.Name))
+}
+
+func (h *RecipeMCPHandler) MergeLabels(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	rawFrom, err := requiredTrimmedString(req, "from")
+	if err != nil {
+		return nil, err
+	}
+	rawInto, err := requiredTrimmedString(req, "into")
+	if err != nil {
+		return nil, err
+	}
+	from, err := recipe.ParseLabel(rawFrom)
+	if err != nil {
+		return mcplib.NewToolResultError(err.Error()), nil
+	}
+	into, err := recipe.ParseLabel(rawInto)
+	if err != nil {
+		return mcplib.NewToolResultError(err.Error()), nil
+	}
+
+	change, err := h.recipeService.MergeLabels(ctx, from, into, req.GetBool("dry_run", false))
+	if err != nil {
+		h.logger.Error().Err(err).Str("from", rawFrom).Str("into", rawInto).Msg("Failed to merge labels via MCP")
+		return recipeErrorResult(err, "merge labels")
+	}
+	return labelChangeResult(change, fmt.Sprintf("Merged %s:%s into %s:%s", change.From.Type, change.From.Name, change.Label.Type, change.Label.Name))
+}
+
+// labelChangeResult reports a rename or merge, saying so when it was only a
+// dry run.
+func labelChangeResult(change *recipe.LabelChange, message string) (*mcplib.CallToolResult, error) {
+	if change.DryRun {
+		message = fmt.Sprintf("Dry run: nothing changed. %s would affect %d recipe(s)", message, len(change.Recipes))
+	} else {
+		message = fmt.Sprintf("%s, affecting %d recipe(s)", message, len(change.Recipes))
+	}
+	responseJSON, err := json.Marshal(map[string]any{
+		"success": true,
+		"message": message,
+		"change":  change,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode label change response: %w", err)
+	}
+	return mcplib.NewToolResultText(string(responseJSON)), nil
+}
@@ -3,9 +3,11 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -68,6 +70,13 @@ func (h *RecipeMCPHandler) UpdateRecipe(ctx context.Context, req mcp.CallToolReq
 			return nil, fmt.Errorf("invalid ingredients: %w", err)
 		}
 		updatedRecipe.Ingredients = ingredients
+		// Existing steps link to the old lines; have them inferred again.
+		steps := make([]recipe.Step, len(updatedRecipe.Steps))
+		for i, step := range updatedRecipe.Steps {
+			step.Ingredients = nil
+			steps[i] = step
+		}
+		updatedRecipe.Steps = steps
 	}
 
 	// Parse and update steps if provided
@@ -77,6 +86,17 @@ func (h *RecipeMCPHandler) UpdateRecipe(ctx context.Context, req mcp.CallToolReq
 			return nil, fmt.Errorf("invalid steps: %w", err)
 		}
 		updatedRecipe.Steps = steps
+		// New steps may need different kit; suggest it again unless given.
+		// The service keeps the stored list when equipment is left out.
+		updatedRecipe.Equipment = recipe.SuggestEquipment(steps)
+	}
+
+	if args["equipment"] != nil {
+		equipment, err := equipmentArg(args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid equipment: %w", err)
+		}
+		updatedRecipe.Equipment = equipment
 	}
 
 	// Parse and update labels if provided
@@ -90,6 +110,9 @@ func (h *RecipeMCPHandler) UpdateRecipe(ctx context.Context, req mcp.CallToolReq
 
 	// Call service layer to update the recipe
 	savedRecipe, err := h.recipeService.UpdateRecipe(ctx, recipeID, updatedRecipe)
+	if errors.Is(err, recipe.ErrInvalidSubRecipe) || errors.Is(err, recipe.ErrInvalidLabel) {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Str("recipe_id", recipeIDStr).Msg("Failed to update recipe via MCP")
 		return nil, fmt.Errorf("failed to update recipe: %w", err)
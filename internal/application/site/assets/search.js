@@ -0,0 +1,49 @@
+// Client-side search over the pre-built index. Matches every word of the
+// query against a recipe's name, description, labels and ingredients.
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var all = document.getElementById("all");
+  if (!input || !results) return;
+
+  var index = null;
+  fetch("search-index.json")
+    .then(function (r) { return r.json(); })
+    .then(function (data) {
+      index = data.map(function (e) {
+        e.haystack = [e.name, e.description || ""].concat(e.labels, e.ingredients).join(" ").toLowerCase();
+        return e;
+      });
+      if (input.value) search();
+    })
+    .catch(function () { input.placeholder = "Search needs the site served over HTTP"; });
+
+  function search() {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    if (!index || words.length === 0) {
+      results.hidden = true;
+      all.hidden = false;
+      return;
+    }
+    index.filter(function (e) {
+      return words.every(function (w) { return e.haystack.indexOf(w) !== -1; });
+    }).forEach(function (e) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = e.path;
+      a.textContent = e.name;
+      li.appendChild(a);
+      results.appendChild(li);
+    });
+    if (!results.firstChild) {
+      var none = document.createElement("li");
+      none.textContent = "No recipes match.";
+      results.appendChild(none);
+    }
+    results.hidden = false;
+    all.hidden = true;
+  }
+
+  input.addEventListener("input", search);
+})();
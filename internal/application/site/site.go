@@ -0,0 +1,459 @@
+// Package site renders the recipe book as a static, read-only HTML site: one
+// page per recipe, label and ingredient indexes, and a pre-built JSON search
+// index. Every link is relative, so the output works from any plain file
+// server (or straight off disk) with no API behind it.
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed assets/*
+var assetFS embed.FS
+
+// labelTypeOrder is the order taxonomy types appear in the navigation. Types
+// not listed here follow alphabetically.
+var labelTypeOrder = []string{"course", "cuisine", "diet", "method"}
+
+// Builder writes the site for a set of recipes into OutDir.
+type Builder struct {
+	OutDir string
+	Title  string
+	// Now stamps the "generated at" footer; zero means time.Now.
+	Now time.Time
+}
+
+// Build renders recipes (which should already exclude archived ones) into
+// b.OutDir, creating it if needed. Existing files with the same names are
+// overwritten; anything else in the directory is left alone.
+func (b Builder) Build(recipes []*recipe.Recipe) error {
+	if b.Now.IsZero() {
+		b.Now = time.Now()
+	}
+	if b.Title == "" {
+		b.Title = "The Bluer Book"
+	}
+
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return fmt.Errorf("parse templates: %w", err)
+	}
+
+	sorted := make([]*recipe.Recipe, len(recipes))
+	copy(sorted, recipes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+
+	pages := make([]recipePage, len(sorted))
+	for i, rec := range sorted {
+		pages[i] = recipePage{Recipe: rec, Path: "recipes/" + recipeSlug(rec) + ".html"}
+	}
+
+	labelTypes := buildLabelIndex(pages)
+	ingredients := buildIngredientIndex(pages)
+
+	for _, dir := range []string{"", "recipes", "labels", "assets"} {
+		if err := os.MkdirAll(filepath.Join(b.OutDir, dir), 0o755); err != nil {
+			return err
+		}
+	}
+
+	base := pageBase{SiteTitle: b.Title, Generated: b.Now, LabelTypes: labelTypes}
+
+	root := base
+	root.Root = ""
+	root.Title = b.Title
+	if err := b.render(tmpl, "index.html", "index.html", struct {
+		pageBase
+		Recipes []recipePage
+	}{root, pages}); err != nil {
+		return err
+	}
+
+	root.Title = "Ingredients"
+	if err := b.render(tmpl, "ingredients.html", "ingredients.html", struct {
+		pageBase
+		Ingredients []ingredientEntry
+	}{root, ingredients}); err != nil {
+		return err
+	}
+
+	nested := base
+	nested.Root = "../"
+	for _, lt := range labelTypes {
+		nested.Title = lt.Display
+		if err := b.render(tmpl, "labels.html", "labels/"+lt.Type+".html", struct {
+			pageBase
+			LabelType labelTypeEntry
+		}{nested, lt}); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range pages {
+		nested.Title = p.Recipe.Name
+		jsonLD, err := recipeJSONLD(p.Recipe)
+		if err != nil {
+			return err
+		}
+		if err := b.render(tmpl, "recipe.html", p.Path, struct {
+			pageBase
+			Page   recipePage
+			JSONLD template.JS
+		}{nested, p, jsonLD}); err != nil {
+			return err
+		}
+	}
+
+	if err := b.writeSearchIndex(pages); err != nil {
+		return err
+	}
+	return b.copyAssets()
+}
+
+func (b Builder) render(tmpl *template.Template, name, path string, data any) error {
+	f, err := os.Create(filepath.Join(b.OutDir, path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := tmpl.ExecuteTemplate(f, name, data); err != nil {
+		return fmt.Errorf("render %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// searchEntry is one record in search-index.json. The search script matches
+// the query against name, description, labels and ingredients client-side.
+type searchEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Path        string   `json:"path"`
+	Photo       string   `json:"photo,omitempty"`
+	TotalTime   int32    `json:"totalTime,omitempty"`
+	Labels      []string `json:"labels"`
+	Ingredients []string `json:"ingredients"`
+}
+
+func (b Builder) writeSearchIndex(pages []recipePage) error {
+	entries := make([]searchEntry, len(pages))
+	for i, p := range pages {
+		e := searchEntry{
+			Name:        p.Recipe.Name,
+			Description: p.Recipe.Description,
+			Path:        p.Path,
+			TotalTime:   p.Recipe.PrepTime + p.Recipe.CookTime,
+			Labels:      []string{},
+			Ingredients: []string{},
+		}
+		if p.Recipe.MainPhoto != nil {
+			e.Photo = p.Recipe.MainPhoto.URL
+		}
+		for _, l := range p.Recipe.Labels {
+			e.Labels = append(e.Labels, displayName(l.Name))
+		}
+		for _, ri := range p.Recipe.Ingredients {
+			e.Ingredients = append(e.Ingredients, ri.Ingredient.Name)
+		}
+		entries[i] = e
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.OutDir, "search-index.json"), data, 0o644)
+}
+
+func (b Builder) copyAssets() error {
+	return fs.WalkDir(assetFS, "assets", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := assetFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(b.OutDir, path), data, 0o644)
+	})
+}
+
+type pageBase struct {
+	SiteTitle  string
+	Title      string
+	Root       string // relative path back to the site root: "" or "../"
+	Generated  time.Time
+	LabelTypes []labelTypeEntry
+}
+
+type recipePage struct {
+	Recipe *recipe.Recipe
+	Path   string // relative to the site root
+}
+
+type labelTypeEntry struct {
+	Type    string
+	Display string
+	Labels  []labelEntry
+}
+
+type labelEntry struct {
+	Name    string
+	Display string
+	Recipes []recipePage
+}
+
+type ingredientEntry struct {
+	Name    string
+	Anchor  string
+	Recipes []recipePage
+}
+
+// buildLabelIndex groups recipes under each label, grouped by label type.
+func buildLabelIndex(pages []recipePage) []labelTypeEntry {
+	byType := map[string]map[string][]recipePage{}
+	for _, p := range pages {
+		for _, l := range p.Recipe.Labels {
+			if byType[l.Type] == nil {
+				byType[l.Type] = map[string][]recipePage{}
+			}
+			byType[l.Type][l.Name] = append(byType[l.Type][l.Name], p)
+		}
+	}
+
+	rank := func(t string) int {
+		for i, known := range labelTypeOrder {
+			if known == t {
+				return i
+			}
+		}
+		return len(labelTypeOrder)
+	}
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if rank(types[i]) != rank(types[j]) {
+			return rank(types[i]) < rank(types[j])
+		}
+		return types[i] < types[j]
+	})
+
+	out := make([]labelTypeEntry, len(types))
+	for i, t := range types {
+		entry := labelTypeEntry{Type: t, Display: titleCase(t)}
+		for name, recipes := range byType[t] {
+			entry.Labels = append(entry.Labels, labelEntry{Name: name, Display: displayName(name), Recipes: recipes})
+		}
+		sort.Slice(entry.Labels, func(a, b int) bool { return entry.Labels[a].Name < entry.Labels[b].Name })
+		out[i] = entry
+	}
+	return out
+}
+
+// buildIngredientIndex lists every ingredient with the recipes that use it.
+// Names are grouped case-insensitively, shown in the first casing seen.
+func buildIngredientIndex(pages []recipePage) []ingredientEntry {
+	index := map[string]*ingredientEntry{}
+	for _, p := range pages {
+		seen := map[string]bool{}
+		for _, ri := range p.Recipe.Ingredients {
+			key := strings.ToLower(strings.TrimSpace(ri.Ingredient.Name))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if index[key] == nil {
+				index[key] = &ingredientEntry{Name: ri.Ingredient.Name, Anchor: slugify(key)}
+			}
+			index[key].Recipes = append(index[key].Recipes, p)
+		}
+	}
+	out := make([]ingredientEntry, 0, len(index))
+	for _, e := range index {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	return out
+}
+
+// recipeJSONLD builds the schema.org Recipe markup for a page.
+func recipeJSONLD(rec *recipe.Recipe) (template.JS, error) {
+	ld := map[string]any{
+		"@context": "https://schema.org",
+		"@type":    "Recipe",
+		"name":     rec.Name,
+	}
+	if rec.Description != "" {
+		ld["description"] = rec.Description
+	}
+	if rec.MainPhoto != nil && rec.MainPhoto.URL != "" {
+		ld["image"] = []string{rec.MainPhoto.URL}
+	}
+	if rec.PrepTime > 0 {
+		ld["prepTime"] = isoMinutes(rec.PrepTime)
+	}
+	if rec.CookTime > 0 {
+		ld["cookTime"] = isoMinutes(rec.CookTime)
+	}
+	if total := rec.PrepTime + rec.CookTime; total > 0 {
+		ld["totalTime"] = isoMinutes(total)
+	}
+	if rec.Servings > 0 {
+		ld["recipeYield"] = strconv.Itoa(int(rec.Servings))
+	}
+	if rec.Url != "" {
+		ld["isBasedOn"] = rec.Url
+	}
+	if !rec.CreatedAt.IsZero() {
+		ld["datePublished"] = rec.CreatedAt.Format(time.DateOnly)
+	}
+
+	var categories, cuisines, keywords []string
+	for _, l := range rec.Labels {
+		switch l.Type {
+		case "course":
+			categories = append(categories, displayName(l.Name))
+		case "cuisine":
+			cuisines = append(cuisines, displayName(l.Name))
+		default:
+			keywords = append(keywords, displayName(l.Name))
+		}
+	}
+	if len(categories) > 0 {
+		ld["recipeCategory"] = categories
+	}
+	if len(cuisines) > 0 {
+		ld["recipeCuisine"] = cuisines
+	}
+	if len(keywords) > 0 {
+		ld["keywords"] = strings.Join(keywords, ", ")
+	}
+
+	ingredients := make([]string, len(rec.Ingredients))
+	for i, ri := range rec.Ingredients {
+		ingredients[i] = ri.Text()
+	}
+	ld["recipeIngredient"] = ingredients
+
+	// Sectioned methods become HowToSections, with any steps outside a
+	// section left in between as plain HowToSteps.
+	var instructions []map[string]any
+	for _, section := range recipe.GroupSteps(rec.Steps) {
+		steps := make([]map[string]any, len(section.Steps))
+		for i, s := range section.Steps {
+			step := map[string]any{"@type": "HowToStep", "text": s.Description}
+			if len(s.Photos) > 0 {
+				step["image"] = s.Photos[0].URL
+			}
+			steps[i] = step
+		}
+		if section.Title == "" {
+			instructions = append(instructions, steps...)
+			continue
+		}
+		instructions = append(instructions, map[string]any{
+			"@type":           "HowToSection",
+			"name":            section.Title,
+			"itemListElement": steps,
+		})
+	}
+	if instructions == nil {
+		instructions = []map[string]any{}
+	}
+	ld["recipeInstructions"] = instructions
+
+	// json.Marshal escapes <, > and &, so the result is safe inside <script>.
+	data, err := json.Marshal(ld)
+	if err != nil {
+		return "", err
+	}
+	return template.JS(data), nil
+}
+
+func isoMinutes(m int32) string {
+	return fmt.Sprintf("PT%dM", m)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// recipeSlug is a readable file name that stays unique even when two recipes
+// share a name, and stable across rebuilds so bookmarks keep working.
+func recipeSlug(rec *recipe.Recipe) string {
+	slug := slugify(rec.Name)
+	if slug == "" {
+		slug = "recipe"
+	}
+	return slug + "-" + rec.UUID.String()[:8]
+}
+
+// displayName turns a canonical label name ("gluten_free") into "gluten free".
+func displayName(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+type ingredientGroup struct {
+	Component   string
+	Ingredients []recipe.RecipeIngredient
+}
+
+// groupIngredients splits a recipe's ingredients into runs by component
+// ("sauce", "filling", …), keeping recipe order.
+func groupIngredients(ris []recipe.RecipeIngredient) []ingredientGroup {
+	var groups []ingredientGroup
+	for _, ri := range ris {
+		if len(groups) == 0 || groups[len(groups)-1].Component != ri.Component {
+			groups = append(groups, ingredientGroup{Component: ri.Component})
+		}
+		groups[len(groups)-1].Ingredients = append(groups[len(groups)-1].Ingredients, ri)
+	}
+	return groups
+}
+
+var templateFuncs = template.FuncMap{
+	"groupIngredients": groupIngredients,
+	"groupSteps":       recipe.GroupSteps,
+	"displayName":      displayName,
+	"totalTime": func(rec *recipe.Recipe) int32 {
+		return rec.PrepTime + rec.CookTime
+	},
+	"ingredientAnchor": func(name string) string {
+		return slugify(strings.ToLower(strings.TrimSpace(name)))
+	},
+	// listData bundles a recipe list with the page's root prefix for the
+	// "recipe-list" partial.
+	"listData": func(root string, recipes []recipePage) any {
+		return struct {
+			Root    string
+			Recipes []recipePage
+		}{root, recipes}
+	},
+}
@@ -0,0 +1,175 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+func testRecipes() []*recipe.Recipe {
+	return []*recipe.Recipe{
+		{
+			UUID:        uuid.MustParse("aaaaaaaa-0000-0000-0000-000000000001"),
+			Name:        "Tarka Dhal",
+			Description: "Lentils & spice </script>",
+			PrepTime:    10,
+			CookTime:    35,
+			Servings:    4,
+			MainPhoto:   &recipe.Photo{URL: "https://images.example/dhal.jpg"},
+			Ingredients: []recipe.RecipeIngredient{
+				{Ingredient: recipe.Ingredient{Name: "red lentils"}, Unit: recipe.Unit{Name: "grams", Abbreviation: "g"}, Quantity: 250},
+				{Ingredient: recipe.Ingredient{Name: "Onion"}, Quantity: 1, Preparation: "diced"},
+			},
+			Steps: []recipe.Step{
+				{Order: 1, Description: "Rinse the lentils."},
+				{Order: 2, Description: "Simmer for 30 minutes."},
+			},
+			Labels: []recipe.Label{
+				{Type: "cuisine", Name: "indian"},
+				{Type: "diet", Name: "gluten_free"},
+				{Type: "course", Name: "main"},
+			},
+		},
+		{
+			UUID:        uuid.MustParse("bbbbbbbb-0000-0000-0000-000000000002"),
+			Name:        "Onion Soup",
+			Ingredients: []recipe.RecipeIngredient{{Ingredient: recipe.Ingredient{Name: "onion"}, Quantity: 6}},
+			Labels:      []recipe.Label{{Type: "course", Name: "soup"}},
+		},
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestBuild_WritesSite(t *testing.T) {
+	out := t.TempDir()
+	b := Builder{OutDir: out, Now: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)}
+	if err := b.Build(testRecipes()); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for _, f := range []string{
+		"index.html",
+		"ingredients.html",
+		"search-index.json",
+		"assets/style.css",
+		"assets/search.js",
+		"labels/course.html",
+		"labels/cuisine.html",
+		"labels/diet.html",
+		"recipes/tarka-dhal-aaaaaaaa.html",
+		"recipes/onion-soup-bbbbbbbb.html",
+	} {
+		if _, err := os.Stat(filepath.Join(out, f)); err != nil {
+			t.Errorf("expected %s: %v", f, err)
+		}
+	}
+
+	page := readFile(t, filepath.Join(out, "recipes/tarka-dhal-aaaaaaaa.html"))
+	for _, want := range []string{
+		`<script type="application/ld+json">`,
+		`"@type":"Recipe"`,
+		`"totalTime":"PT45M"`,
+		`"recipeIngredient":["250 g red lentils","1 Onion, diced"]`,
+		`"recipeCuisine":["indian"]`,
+		`<img class="hero" src="https://images.example/dhal.jpg"`,
+		`href="../ingredients.html#onion"`,
+		`href="../labels/diet.html#gluten_free"`,
+		`href="../assets/style.css"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("recipe page missing %q", want)
+		}
+	}
+	if strings.Contains(page, "spice </script>") {
+		t.Error("description broke out of the JSON-LD script block")
+	}
+
+	// Ingredient names are grouped case-insensitively across recipes.
+	ingredients := readFile(t, filepath.Join(out, "ingredients.html"))
+	if !strings.Contains(ingredients, `<section id="onion">`) || !strings.Contains(ingredients, "(2)") {
+		t.Errorf("expected onion listed against both recipes:\n%s", ingredients)
+	}
+
+	course := readFile(t, filepath.Join(out, "labels/course.html"))
+	if !strings.Contains(course, `href="../recipes/onion-soup-bbbbbbbb.html"`) {
+		t.Errorf("course index missing soup link:\n%s", course)
+	}
+}
+
+func TestBuild_SearchIndex(t *testing.T) {
+	out := t.TempDir()
+	if err := (Builder{OutDir: out}).Build(testRecipes()); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var entries []searchEntry
+	if err := json.Unmarshal([]byte(readFile(t, filepath.Join(out, "search-index.json"))), &entries); err != nil {
+		t.Fatalf("decode search index: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	// Sorted by name.
+	if entries[0].Name != "Onion Soup" || entries[1].Name != "Tarka Dhal" {
+		t.Errorf("unexpected order: %q, %q", entries[0].Name, entries[1].Name)
+	}
+	dhal := entries[1]
+	if dhal.Path != "recipes/tarka-dhal-aaaaaaaa.html" || dhal.TotalTime != 45 {
+		t.Errorf("unexpected entry: %+v", dhal)
+	}
+	if strings.Join(dhal.Labels, ",") != "indian,gluten free,main" {
+		t.Errorf("labels = %v", dhal.Labels)
+	}
+}
+
+func TestGroupIngredients(t *testing.T) {
+	groups := groupIngredients([]recipe.RecipeIngredient{
+		{Ingredient: recipe.Ingredient{Name: "flour"}, Component: "dough"},
+		{Ingredient: recipe.Ingredient{Name: "yeast"}, Component: "dough"},
+		{Ingredient: recipe.Ingredient{Name: "tomato"}, Component: "sauce"},
+	})
+	if len(groups) != 2 || groups[0].Component != "dough" || len(groups[0].Ingredients) != 2 {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestBuild_StepSections(t *testing.T) {
+	recipes := testRecipes()
+	recipes[0].Steps = []recipe.Step{
+		{Order: 1, Description: "Rinse the lentils.", Section: "Dhal"},
+		{Order: 2, Description: "Simmer for 30 minutes.", Section: "Dhal"},
+		{Order: 3, Description: "Fry the spices.", Section: "Tarka"},
+		{Order: 4, Description: "Serve."},
+	}
+
+	out := t.TempDir()
+	if err := (Builder{OutDir: out}).Build(recipes); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	page := readFile(t, filepath.Join(out, "recipes/tarka-dhal-aaaaaaaa.html"))
+	for _, want := range []string{
+		`<h3>Tarka</h3>`,
+		`<ol start="3">`,
+		`{"@type":"HowToSection","itemListElement":[{"@type":"HowToStep","text":"Rinse the lentils."},{"@type":"HowToStep","text":"Simmer for 30 minutes."}],"name":"Dhal"}`,
+		`{"@type":"HowToStep","text":"Serve."}]`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("recipe page missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package pantry
+
+import "strings"
+
+// Grocery categories: the sections of a supermarket an item is found in.
+// Every ingredient and custom shopping item can carry one; those that don't
+// are guessed from their name, and anything the guess misses is "other".
+const (
+	CategoryProduce   = "produce"
+	CategoryBakery    = "bakery"
+	CategoryMeat      = "meat"
+	CategoryDairy     = "dairy"
+	CategoryFrozen    = "frozen"
+	CategoryTins      = "tins"
+	CategorySpices    = "spices"
+	CategoryHousehold = "household"
+	CategoryOther     = "other"
+)
+
+// Categories is every category in the default walking order: fresh food at
+// the door, chilled and frozen at the back, then the aisles. Other is always
+// last, whatever the store layout says.
+var Categories = []string{
+	CategoryProduce,
+	CategoryBakery,
+	CategoryMeat,
+	CategoryDairy,
+	CategoryFrozen,
+	CategoryTins,
+	CategorySpices,
+	CategoryHousehold,
+	CategoryOther,
+}
+
+// categoryHeadings label the sections of an aisle-ordered shopping list.
+var categoryHeadings = map[string]string{
+	CategoryProduce:   "Fruit & veg",
+	CategoryBakery:    "Bakery",
+	CategoryMeat:      "Meat & fish",
+	CategoryDairy:     "Dairy & eggs",
+	CategoryFrozen:    "Frozen",
+	CategoryTins:      "Tins & dry goods",
+	CategorySpices:    "Herbs & spices",
+	CategoryHousehold: "Household",
+	CategoryOther:     "Everything else",
+}
+
+// CategoryHeading is how category is shown as a section heading.
+func CategoryHeading(category string) string {
+	if h, ok := categoryHeadings[category]; ok {
+		return h
+	}
+	return category
+}
+
+// NormalizeCategory lowercases and trims category, checking it is one of
+// Categories. An empty category is valid: it clears the stored one.
+func NormalizeCategory(category string) (string, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return "", nil
+	}
+	for _, c := range Categories {
+		if c == category {
+			return category, nil
+		}
+	}
+	return "", InvalidCategoryError{Category: category}
+}
+
+// IngredientCategory is an ingredient's stored category, empty when it has
+// none.
+type IngredientCategory struct {
+	Ingredient string `json:"ingredient"`
+	Category   string `json:"category"`
+}
+
+// StoreLayout is the order the shop's sections are walked in. It may name
+// only some categories; the rest follow in their default order.
+type StoreLayout []string
+
+// NormalizeStoreLayout checks every entry of layout is a category and none
+// repeats. Other can't be placed: it always comes last.
+func NormalizeStoreLayout(layout []string) (StoreLayout, error) {
+	out := StoreLayout{}
+	for _, c := range layout {
+		c, err := NormalizeCategory(c)
+		if err != nil {
+			return nil, err
+		}
+		if c == "" || c == CategoryOther {
+			return nil, InvalidCategoryError{Category: c, Reason: "can't be placed in the store layout"}
+		}
+		for _, seen := range out {
+			if seen == c {
+				return nil, InvalidCategoryError{Category: c, Reason: "appears twice in the store layout"}
+			}
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// Order lists every category in walking order: the layout's, then any it
+// leaves out in their default order, then other.
+func (l StoreLayout) Order() []string {
+	order := make([]string, 0, len(Categories))
+	placed := map[string]bool{CategoryOther: true}
+	for _, c := range l {
+		if !placed[c] {
+			order = append(order, c)
+			placed[c] = true
+		}
+	}
+	for _, c := range Categories {
+		if !placed[c] {
+			order = append(order, c)
+			placed[c] = true
+		}
+	}
+	return append(order, CategoryOther)
+}
+
+// ShoppingSection is one stop on an aisle-ordered shopping list.
+type ShoppingSection struct {
+	Category string             `json:"category"`
+	Heading  string             `json:"heading"`
+	Items    []ShoppingListItem `json:"items"`
+}
+
+// GroupByAisle sorts items into sections in the layout's walking order,
+// skipping empty sections. Items keep their order within a section, and one
+// without a category goes under other.
+func GroupByAisle(items []ShoppingListItem, layout StoreLayout) []ShoppingSection {
+	byCategory := map[string][]ShoppingListItem{}
+	for _, item := range items {
+		c := item.Category
+		if c == "" {
+			c = CategoryOther
+		}
+		byCategory[c] = append(byCategory[c], item)
+	}
+	sections := []ShoppingSection{}
+	for _, c := range layout.Order() {
+		if len(byCategory[c]) > 0 {
+			sections = append(sections, ShoppingSection{Category: c, Heading: CategoryHeading(c), Items: byCategory[c]})
+		}
+	}
+	return sections
+}
+
+// categoryModifiers are words that decide the category wherever they appear
+// in a name: "frozen peas" are frozen, "tinned tomatoes" are tins.
+var categoryModifiers = map[string]string{
+	"frozen": CategoryFrozen,
+	"tinned": CategoryTins,
+	"canned": CategoryTins,
+}
+
+// categoryKeywords map a name's head noun, usually its last word ("chicken
+// stock" is stock), to a category. Phrases are matched before single words.
+var categoryKeywords = map[string]string{
+	// Produce
+	"apple": CategoryProduce, "aubergine": CategoryProduce, "avocado": CategoryProduce,
+	"banana": CategoryProduce, "basil": CategoryProduce, "bean sprout": CategoryProduce,
+	"beetroot": CategoryProduce, "berry": CategoryProduce, "broccoli": CategoryProduce,
+	"cabbage": CategoryProduce, "carrot": CategoryProduce, "cauliflower": CategoryProduce,
+	"celery": CategoryProduce, "chilli": CategoryProduce, "coriander": CategoryProduce,
+	"courgette": CategoryProduce, "cucumber": CategoryProduce, "garlic": CategoryProduce,
+	"ginger": CategoryProduce, "herb": CategoryProduce, "kale": CategoryProduce,
+	"leek": CategoryProduce, "lemon": CategoryProduce, "lettuce": CategoryProduce,
+	"lime": CategoryProduce, "mint": CategoryProduce, "mushroom": CategoryProduce,
+	"onion": CategoryProduce, "orange": CategoryProduce, "parsley": CategoryProduce,
+	"parsnip": CategoryProduce, "pea": CategoryProduce, "pepper": CategoryProduce,
+	"potato": CategoryProduce, "rocket": CategoryProduce, "rosemary": CategoryProduce,
+	"salad": CategoryProduce, "shallot": CategoryProduce, "spinach": CategoryProduce,
+	"spring onion": CategoryProduce, "squash": CategoryProduce, "sweet potato": CategoryProduce,
+	"thyme": CategoryProduce, "tomato": CategoryProduce,
+	// Bakery
+	"bagel": CategoryBakery, "baguette": CategoryBakery, "bread": CategoryBakery,
+	"brioche": CategoryBakery, "bun": CategoryBakery, "ciabatta": CategoryBakery,
+	"croissant": CategoryBakery, "flatbread": CategoryBakery, "naan": CategoryBakery,
+	"pitta": CategoryBakery, "roll": CategoryBakery, "sourdough": CategoryBakery,
+	"tortilla": CategoryBakery, "wrap": CategoryBakery,
+	// Meat & fish
+	"bacon": CategoryMeat, "beef": CategoryMeat, "chicken": CategoryMeat,
+	"chorizo": CategoryMeat, "cod": CategoryMeat, "fish": CategoryMeat,
+	"ham": CategoryMeat, "lamb": CategoryMeat, "mince": CategoryMeat,
+	"pancetta": CategoryMeat, "pork": CategoryMeat, "prawn": CategoryMeat,
+	"salmon": CategoryMeat, "sausage": CategoryMeat, "steak": CategoryMeat,
+	"thigh": CategoryMeat, "breast": CategoryMeat, "turkey": CategoryMeat,
+	// Dairy & eggs
+	"butter": CategoryDairy, "cheddar": CategoryDairy, "cheese": CategoryDairy,
+	"cream": CategoryDairy, "creme fraiche": CategoryDairy, "egg": CategoryDairy,
+	"feta": CategoryDairy, "halloumi": CategoryDairy, "milk": CategoryDairy,
+	"mozzarella": CategoryDairy, "parmesan": CategoryDairy, "yoghurt": CategoryDairy,
+	"yogurt": CategoryDairy,
+	// Frozen
+	"ice cream": CategoryFrozen, "ice": CategoryFrozen,
+	// Tins & dry goods
+	"bean": CategoryTins, "chickpea": CategoryTins, "coconut milk": CategoryTins,
+	"couscous": CategoryTins, "flour": CategoryTins, "honey": CategoryTins,
+	"lentil": CategoryTins, "noodle": CategoryTins, "oat": CategoryTins,
+	"oil": CategoryTins, "pasta": CategoryTins, "rice": CategoryTins,
+	"sauce": CategoryTins, "spaghetti": CategoryTins, "stock": CategoryTins,
+	"sugar": CategoryTins, "tomato puree": CategoryTins, "vinegar": CategoryTins,
+	"passata": CategoryTins, "penne": CategoryTins, "mustard": CategoryTins,
+	"tuna": CategoryTins, "baking powder": CategoryTins, "yeast": CategoryTins,
+	// Herbs & spices
+	"bay leaf": CategorySpices, "black pepper": CategorySpices, "cardamom": CategorySpices,
+	"cayenne": CategorySpices, "cinnamon": CategorySpices,
+	"cumin": CategorySpices, "curry powder": CategorySpices, "garam masala": CategorySpices,
+	"nutmeg": CategorySpices, "oregano": CategorySpices, "paprika": CategorySpices,
+	"peppercorn": CategorySpices, "salt": CategorySpices, "spice": CategorySpices,
+	"turmeric": CategorySpices, "chilli flake": CategorySpices,
+	// Household
+	"bin bag": CategoryHousehold, "bleach": CategoryHousehold, "cling film": CategoryHousehold,
+	"foil": CategoryHousehold, "kitchen roll": CategoryHousehold, "soap": CategoryHousehold,
+	"sponge": CategoryHousehold, "tissue": CategoryHousehold, "toilet roll": CategoryHousehold,
+	"washing-up liquid": CategoryHousehold, "detergent": CategoryHousehold,
+	"toothpaste": CategoryHousehold, "shampoo": CategoryHousehold,
+}
+
+// GuessCategory picks a category for name from the keyword tables, or ""
+// when none matches. Stored categories, from an edit or the
+// categorise-ingredients command, always win over a guess.
+func GuessCategory(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for _, w := range words {
+		if c, ok := categoryModifiers[w]; ok {
+			return c
+		}
+	}
+	// Head noun first: walk back from the end, trying the two-word phrase
+	// ending at each word before the word itself.
+	for i := len(words) - 1; i >= 0; i-- {
+		if i > 0 {
+			if c, ok := lookupKeyword(words[i-1] + " " + words[i]); ok {
+				return c
+			}
+		}
+		if c, ok := lookupKeyword(words[i]); ok {
+			return c
+		}
+	}
+	return ""
+}
+
+// lookupKeyword tries key and its singular forms against categoryKeywords.
+func lookupKeyword(key string) (string, bool) {
+	for _, k := range []string{key, strings.TrimSuffix(key, "s"), strings.TrimSuffix(key, "es"), strings.TrimSuffix(key, "ies") + "y"} {
+		if c, ok := categoryKeywords[k]; ok {
+			return c, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package pantry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGuessCategory(t *testing.T) {
+	for name, want := range map[string]string{
+		"red onions":        CategoryProduce,
+		"black pepper":      CategorySpices,
+		"red pepper":        CategoryProduce,
+		"frozen peas":       CategoryFrozen,
+		"tinned tomatoes":   CategoryTins,
+		"chicken stock":     CategoryTins,
+		"chicken thighs":    CategoryMeat,
+		"coconut milk":      CategoryTins,
+		"Greek Yoghurt":     CategoryDairy,
+		"washing-up liquid": CategoryHousehold,
+		"sourdough loaf":    CategoryBakery,
+		"unobtainium":       "",
+	} {
+		if got := GuessCategory(name); got != want {
+			t.Errorf("GuessCategory(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestStoreLayoutOrder(t *testing.T) {
+	layout, err := NormalizeStoreLayout([]string{" Bakery", "dairy"})
+	if err != nil {
+		t.Fatalf("NormalizeStoreLayout() error = %v", err)
+	}
+	got := layout.Order()
+	want := []string{CategoryBakery, CategoryDairy, CategoryProduce, CategoryMeat, CategoryFrozen, CategoryTins, CategorySpices, CategoryHousehold, CategoryOther}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Order() = %v, want %v", got, want)
+	}
+
+	for _, bad := range [][]string{{"produce", "produce"}, {"other"}, {"deli"}} {
+		if _, err := NormalizeStoreLayout(bad); err == nil {
+			t.Errorf("NormalizeStoreLayout(%v) error = nil, want invalid category", bad)
+		}
+	}
+}
+
+func TestGroupByAisle(t *testing.T) {
+	items := []ShoppingListItem{
+		{Name: "milk", Category: CategoryDairy},
+		{Name: "mystery", Category: ""},
+		{Name: "bread", Category: CategoryBakery},
+		{Name: "cheese", Category: CategoryDairy},
+	}
+	sections := GroupByAisle(items, StoreLayout{CategoryDairy})
+
+	var got []string
+	for _, s := range sections {
+		got = append(got, s.Category)
+	}
+	want := []string{CategoryDairy, CategoryBakery, CategoryOther}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sections = %v, want %v", got, want)
+	}
+	if len(sections[0].Items) != 2 || sections[0].Items[1].Name != "cheese" {
+		t.Errorf("dairy = %+v, want milk then cheese", sections[0].Items)
+	}
+}
@@ -3,6 +3,7 @@ package pantry
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Domain-specific errors
@@ -12,6 +13,22 @@ var (
 	// so there is nothing for such an entry to point at — the operation is a
 	// reportable failure, not a no-op.
 	ErrIngredientNotFound = errors.New("ingredient not found")
+
+	// ErrShareNotFound indicates a share token that doesn't exist or has
+	// expired. The two are deliberately indistinguishable to the caller.
+	ErrShareNotFound = errors.New("share not found")
+
+	// ErrInvalidCategory indicates a grocery category that isn't one of
+	// Categories, or a store layout that misuses one.
+	ErrInvalidCategory = errors.New("invalid category")
+
+	// ErrShoppingItemNotFound indicates no custom shopping list item has the
+	// given name.
+	ErrShoppingItemNotFound = errors.New("shopping list item not found")
+
+	// ErrNothingChecked indicates a shopping trip was completed with nothing
+	// on the list checked off, so there is nothing to stock or record.
+	ErrNothingChecked = errors.New("nothing on the shopping list is checked")
 )
 
 // IngredientNotFoundError provides context about which ingredient name could
@@ -27,3 +44,35 @@ func (e IngredientNotFoundError) Error() string {
 func (e IngredientNotFoundError) Is(target error) bool {
 	return target == ErrIngredientNotFound
 }
+
+// InvalidCategoryError reports a category that isn't one of Categories, or
+// a store layout that misuses one.
+type InvalidCategoryError struct {
+	Category string
+	Reason   string
+}
+
+func (e InvalidCategoryError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("category %q %s", e.Category, e.Reason)
+	}
+	return fmt.Sprintf("unknown category %q; expected one of %s", e.Category, strings.Join(Categories, ", "))
+}
+
+func (e InvalidCategoryError) Is(target error) bool {
+	return target == ErrInvalidCategory
+}
+
+// ShoppingItemNotFoundError provides context about which custom shopping list
+// item was not found.
+type ShoppingItemNotFoundError struct {
+	Name string
+}
+
+func (e ShoppingItemNotFoundError) Error() string {
+	return fmt.Sprintf("no shopping list item named %q", e.Name)
+}
+
+func (e ShoppingItemNotFoundError) Is(target error) bool {
+	return target == ErrShoppingItemNotFound
+}
@@ -1,27 +1,94 @@
 package pantry
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // PantryItem records that the user currently has a given ingredient at home.
 // Presence-only (v1): the ingredient is identified by its name, which is
-// unique in the ingredients table.
+// unique in the ingredients table. Staple marks one that's there because it's
+// a staple, whether or not anyone stocked it. PantryOnly marks an ingredient
+// the pantry added that no recipe has used yet.
 type PantryItem struct {
 	Ingredient string    `json:"ingredient"`
 	AddedAt    time.Time `json:"addedAt,omitempty"`
+	Staple     bool      `json:"staple"`
+	PantryOnly bool      `json:"pantryOnly"`
+}
+
+// Staple is an ingredient assumed always to be in the pantry — salt, olive
+// oil — so it never lands on the shopping list, unless someone says it's run
+// out. Out holds until it's stocked again.
+type Staple struct {
+	Ingredient string    `json:"ingredient"`
+	Out        bool      `json:"out"`
+	OutSince   time.Time `json:"outSince,omitempty"`
 }
 
 // Shopping list item sources. A meal-plan item is an ingredient a planned
-// recipe needs but the pantry lacks; checking one off stocks the pantry. A
-// custom item is free text the user added (or scanned from a photo) that isn't
-// a recipe ingredient at all; checking one off simply deletes it.
+// recipe needs but the pantry lacks; checking one off stocks the pantry. An
+// ingredient item is a known ingredient the user added (or scanned from a
+// photo) themselves; checking one off stocks the pantry too, which takes it
+// off the list. A custom item is free text that isn't a recipe ingredient at
+// all; checking one off simply deletes it.
 const (
-	ShoppingSourceMealPlan = "meal_plan"
-	ShoppingSourceCustom   = "custom"
+	ShoppingSourceMealPlan   = "meal_plan"
+	ShoppingSourceIngredient = "ingredient"
+	ShoppingSourceCustom     = "custom"
 )
 
 // ShoppingListItem is one line on the shopping list. Source tells the client
 // (and the check-off behaviour) which kind it is — see the constants above.
+// Category is the grocery category it's shopped from: the one stored for it,
+// else a guess from its name, else other. Checked is whether it's in the
+// basket on the current trip; it's shared, so everyone shopping sees it.
 type ShoppingListItem struct {
-	Name   string `json:"name"`
-	Source string `json:"source"`
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	Category string `json:"category"`
+	Checked  bool   `json:"checked"`
+}
+
+// ShoppingTrip is a completed shop, kept for history: the checked lines it
+// took off the list (Items) and, of those, the ingredients it stocked the
+// pantry with (Stocked).
+type ShoppingTrip struct {
+	UUID        uuid.UUID `json:"uuid"`
+	Items       []string  `json:"items"`
+	Stocked     []string  `json:"stocked"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// ShoppingAdditions is what adding typed or scanned names to the shopping
+// list did. Added holds the items now on it: known ingredients where a name
+// resolved to one, free text where it resolved to nothing. NeedsReview holds
+// the names that might be one of several ingredients; they aren't added until
+// someone picks.
+type ShoppingAdditions struct {
+	Added       []ShoppingListItem `json:"added"`
+	NeedsReview []ShoppingReview   `json:"needs_review"`
+}
+
+// ShoppingReview is a name that needs a person to say which ingredient, if
+// any, it means.
+type ShoppingReview struct {
+	Name       string   `json:"name"`
+	Candidates []string `json:"candidates"`
+}
+
+// Lists that can be shared via a public link.
+const (
+	ListShoppingList = "shopping_list"
+	ListPantry       = "pantry"
+)
+
+// ListShare is a short-lived public link to a read-only rendering of one of
+// the lists above. Token is the link's only credential. The shared view is
+// live: it shows the list as it is when opened, not as it was when shared.
+type ListShare struct {
+	Token     string    `json:"token"`
+	List      string    `json:"list"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/repository"
@@ -13,24 +16,103 @@ import (
 // PantryService is the single door into the pantry domain. REST handlers and
 // MCP tools call this rather than the repository directly.
 type PantryService interface {
-	// AddToPantry marks a known ingredient as in stock. The name is matched
-	// case-insensitively; one that matches no ingredient returns
-	// pantry.ErrIngredientNotFound rather than silently doing nothing.
-	AddToPantry(ctx context.Context, ingredient string) error
-	// RemoveFromPantry is the inverse, and reports the same not-found error.
-	// Removing an ingredient that isn't in the pantry is a no-op, not an error.
+	// AddToPantry marks an ingredient as in stock. The name is resolved as
+	// recipe saves resolve it (case, plurals, aliases); one that matches no
+	// ingredient creates a pantry-only one, which a recipe using the name
+	// later links to. It returns the ingredient's name and whether it was
+	// created.
+	AddToPantry(ctx context.Context, ingredient string) (string, bool, error)
+	// RemoveFromPantry is the inverse. A name that matches no ingredient
+	// returns pantry.ErrIngredientNotFound rather than silently doing nothing;
+	// removing an ingredient that isn't in the pantry is a no-op, not an error.
 	RemoveFromPantry(ctx context.Context, ingredient string) error
+	// ListPantry lists what's in stock, staples that haven't run out included.
 	ListPantry(ctx context.Context) ([]pantry.PantryItem, error)
+	// Staples are ingredients assumed always to be in stock: they're listed in
+	// the pantry and kept off the shopping list. Removing one from the pantry
+	// doesn't change that; MarkOut does, until the ingredient is stocked
+	// again. SetStaple flags or unflags an ingredient, resolving the name as
+	// AddToPantry does and returning the ingredient's name.
+	Staples(ctx context.Context) ([]pantry.Staple, error)
+	SetStaple(ctx context.Context, ingredient string, staple bool) (string, error)
+	// MarkOut says we've run out of an ingredient: it leaves the pantry and,
+	// if it's a staple, counts as missing until stocked again, so a planned
+	// recipe puts it on the shopping list. It returns the ingredient's name and
+	// whether it's a staple.
+	MarkOut(ctx context.Context, ingredient string) (string, bool, error)
 	// ShoppingList returns everything to buy: the ingredients a planned recipe
-	// needs but the pantry lacks, plus any free-text custom items.
+	// needs but the pantry lacks, plus any free-text custom items. Every item
+	// has a category: its stored one, else a guess from its name, else other.
 	ShoppingList(ctx context.Context) ([]pantry.ShoppingListItem, error)
+	// ShoppingAisles is the shopping list in sections, in the store layout's
+	// walking order.
+	ShoppingAisles(ctx context.Context) ([]pantry.ShoppingSection, error)
+	// AddShoppingItems puts typed or scanned names on the shopping list,
+	// resolving each against the ingredients first (recipe.IngredientCatalogue
+	// Match). A name that means an ingredient — exactly, by case, plural or
+	// alias — is added as it, so stocking the pantry takes it off the list;
+	// one that's only close to some (a typo, shared words) is returned for
+	// review and not added, however few the candidates; the rest are added
+	// as free text.
+	AddShoppingItems(ctx context.Context, names []string) (pantry.ShoppingAdditions, error)
+	// AddIngredientShoppingItem adds a known ingredient, say one picked from
+	// a review, returning its name, or pantry.ErrIngredientNotFound.
+	AddIngredientShoppingItem(ctx context.Context, ingredient string) (string, error)
 	// AddCustomShoppingItem adds a free-text item (not a recipe ingredient) to
-	// the shopping list, e.g. "washing-up liquid".
+	// the shopping list as is, e.g. "washing-up liquid", or a name review
+	// found to be none of its candidates.
 	AddCustomShoppingItem(ctx context.Context, name string) error
-	// RemoveCustomShoppingItem removes a previously added custom item.
+	// RemoveCustomShoppingItem removes a previously added item, free text or
+	// ingredient.
 	RemoveCustomShoppingItem(ctx context.Context, name string) error
+	// SetShoppingItemChecked ticks a line on the shopping list, by name, or
+	// unticks it. Only a line that's on the list can be ticked:
+	// pantry.ErrShoppingItemNotFound otherwise.
+	SetShoppingItemChecked(ctx context.Context, name string, checked bool) error
+	// CompleteShoppingTrip ends a trip to the shop in one go: the checked
+	// ingredients are stocked in the pantry, the checked items added by hand
+	// come off the list, ticks on lines no longer listed are cleared, and the
+	// trip is recorded. pantry.ErrNothingChecked when nothing is checked,
+	// including when a concurrent completion already took the ticks.
+	CompleteShoppingTrip(ctx context.Context) (pantry.ShoppingTrip, error)
+	// ShoppingTrips lists completed trips, newest first, at most limit.
+	ShoppingTrips(ctx context.Context, limit int) ([]pantry.ShoppingTrip, error)
+	// SetIngredientCategory files an ingredient under a grocery category,
+	// resolving the name as AddToPantry does. SetShoppingItemCategory does the
+	// same for a custom item, returning pantry.ErrShoppingItemNotFound for
+	// one that isn't on the list. An empty category clears the stored one, so
+	// the item's category is guessed again; an unknown one is
+	// pantry.ErrInvalidCategory.
+	SetIngredientCategory(ctx context.Context, ingredient, category string) error
+	SetShoppingItemCategory(ctx context.Context, name, category string) error
+	// StoreLayout is the walking order through the shop, every category in
+	// it. SaveStoreLayout sets it from the categories given in order; any
+	// left out follow in their default order.
+	StoreLayout(ctx context.Context) ([]string, error)
+	SaveStoreLayout(ctx context.Context, categories []string) ([]string, error)
+	// ShareList mints a public, read-only link to the shopping list or the
+	// pantry (pantry.ListShoppingList / pantry.ListPantry) that stops working
+	// after ttl. A zero ttl means DefaultShareTTL; anything over MaxShareTTL is
+	// clamped to it.
+	ShareList(ctx context.Context, list string, ttl time.Duration) (pantry.ListShare, error)
+	// GetShare resolves a share token, returning pantry.ErrShareNotFound for
+	// one that's unknown or expired.
+	GetShare(ctx context.Context, token string) (pantry.ListShare, error)
 }
 
+// Share link lifetimes. Links are meant for a single trip to the shop, so the
+// default is a day and even the maximum is short.
+const (
+	DefaultShareTTL = 24 * time.Hour
+	MaxShareTTL     = 7 * 24 * time.Hour
+)
+
+// How many past shopping trips ShoppingTrips returns by default, and at most.
+const (
+	DefaultTripHistory = 20
+	MaxTripHistory     = 100
+)
+
 type pantryService struct {
 	repo  repository.PantryRepository
 	probe pantry.Probe
@@ -43,17 +125,22 @@ func NewPantryService(repo repository.PantryRepository, probe pantry.Probe) Pant
 	}
 }
 
-func (s *pantryService) AddToPantry(ctx context.Context, ingredient string) error {
+func (s *pantryService) AddToPantry(ctx context.Context, ingredient string) (string, bool, error) {
 	ingredient = strings.TrimSpace(ingredient)
 	if ingredient == "" {
-		return fmt.Errorf("ingredient name is required")
+		return "", false, fmt.Errorf("ingredient name is required")
 	}
-	if err := s.repo.AddToPantry(ctx, ingredient); err != nil {
-		s.observeFailure("add", ingredient, err)
-		return err
+	name, created, err := s.repo.AddToPantry(ctx, ingredient)
+	if err != nil {
+		s.probe.PantryError("add", err)
+		return "", false, err
 	}
-	s.probe.PantryChanged("add", ingredient)
-	return nil
+	if created {
+		s.probe.PantryChanged("add_new", name)
+	} else {
+		s.probe.PantryChanged("add", name)
+	}
+	return name, created, nil
 }
 
 func (s *pantryService) RemoveFromPantry(ctx context.Context, ingredient string) error {
@@ -84,6 +171,47 @@ func (s *pantryService) ListPantry(ctx context.Context) ([]pantry.PantryItem, er
 	return s.repo.ListPantry(ctx)
 }
 
+func (s *pantryService) Staples(ctx context.Context) ([]pantry.Staple, error) {
+	staples, err := s.repo.ListStaples(ctx)
+	if err != nil {
+		s.probe.PantryError("staples", err)
+		return nil, err
+	}
+	return staples, nil
+}
+
+func (s *pantryService) SetStaple(ctx context.Context, ingredient string, staple bool) (string, error) {
+	ingredient = strings.TrimSpace(ingredient)
+	if ingredient == "" {
+		return "", fmt.Errorf("ingredient name is required")
+	}
+	action := "staple"
+	if !staple {
+		action = "unstaple"
+	}
+	name, err := s.repo.SetStaple(ctx, ingredient, staple)
+	if err != nil {
+		s.observeFailure(action, ingredient, err)
+		return "", err
+	}
+	s.probe.PantryChanged(action, name)
+	return name, nil
+}
+
+func (s *pantryService) MarkOut(ctx context.Context, ingredient string) (string, bool, error) {
+	ingredient = strings.TrimSpace(ingredient)
+	if ingredient == "" {
+		return "", false, fmt.Errorf("ingredient name is required")
+	}
+	name, staple, err := s.repo.MarkOut(ctx, ingredient)
+	if err != nil {
+		s.observeFailure("out", ingredient, err)
+		return "", false, err
+	}
+	s.probe.PantryChanged("out", name)
+	return name, staple, nil
+}
+
 func (s *pantryService) ShoppingList(ctx context.Context) ([]pantry.ShoppingListItem, error) {
 	mealPlan, err := s.repo.ShoppingList(ctx)
 	if err != nil {
@@ -96,18 +224,246 @@ func (s *pantryService) ShoppingList(ctx context.Context) ([]pantry.ShoppingList
 		return nil, err
 	}
 
-	// Meal-plan ingredients first, then custom extras — both already sorted by
-	// name by the queries.
+	// Meal-plan ingredients first, then what was added by hand — both already
+	// sorted by name by the queries. An ingredient added by hand that the
+	// meal plan needs anyway is listed once, for the meal plan.
 	items := make([]pantry.ShoppingListItem, 0, len(mealPlan)+len(custom))
-	for _, name := range mealPlan {
-		items = append(items, pantry.ShoppingListItem{Name: name, Source: pantry.ShoppingSourceMealPlan})
+	planned := map[string]bool{}
+	for _, item := range mealPlan {
+		item.Source = pantry.ShoppingSourceMealPlan
+		planned[strings.ToLower(item.Name)] = true
+		items = append(items, categorised(item))
 	}
-	for _, name := range custom {
-		items = append(items, pantry.ShoppingListItem{Name: name, Source: pantry.ShoppingSourceCustom})
+	for _, item := range custom {
+		if item.Source == pantry.ShoppingSourceIngredient && planned[strings.ToLower(item.Name)] {
+			continue
+		}
+		items = append(items, categorised(item))
 	}
 	return items, nil
 }
 
+// categorised fills in the category of an item that has none stored.
+func categorised(item pantry.ShoppingListItem) pantry.ShoppingListItem {
+	if item.Category == "" {
+		item.Category = pantry.GuessCategory(item.Name)
+	}
+	if item.Category == "" {
+		item.Category = pantry.CategoryOther
+	}
+	return item
+}
+
+func (s *pantryService) ShoppingAisles(ctx context.Context) ([]pantry.ShoppingSection, error) {
+	items, err := s.ShoppingList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := s.repo.GetStoreLayout(ctx)
+	if err != nil {
+		s.probe.PantryError("store_layout", err)
+		return nil, err
+	}
+	return pantry.GroupByAisle(items, layout), nil
+}
+
+func (s *pantryService) SetIngredientCategory(ctx context.Context, ingredient, category string) error {
+	ingredient = strings.TrimSpace(ingredient)
+	if ingredient == "" {
+		return fmt.Errorf("ingredient name is required")
+	}
+	category, err := pantry.NormalizeCategory(category)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetIngredientCategory(ctx, ingredient, category); err != nil {
+		s.observeFailure("categorise", ingredient, err)
+		return err
+	}
+	s.probe.PantryChanged("categorise", ingredient)
+	return nil
+}
+
+func (s *pantryService) SetShoppingItemChecked(ctx context.Context, name string, checked bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("shopping list item name is required")
+	}
+	if !checked {
+		// Unticking doesn't look for the line: a tick left behind by a line
+		// that has since gone should still be clearable.
+		if err := s.repo.UncheckShoppingItem(ctx, name); err != nil {
+			s.probe.PantryError("shopping_uncheck", err)
+			return err
+		}
+		s.probe.PantryChanged("shopping_uncheck", name)
+		return nil
+	}
+
+	items, err := s.ShoppingList(ctx)
+	if err != nil {
+		return err
+	}
+	onList := false
+	for _, item := range items {
+		if strings.EqualFold(item.Name, name) {
+			onList = true
+			break
+		}
+	}
+	if !onList {
+		return pantry.ShoppingItemNotFoundError{Name: name}
+	}
+	if err := s.repo.CheckShoppingItem(ctx, name); err != nil {
+		s.probe.PantryError("shopping_check", err)
+		return err
+	}
+	s.probe.PantryChanged("shopping_check", name)
+	return nil
+}
+
+func (s *pantryService) CompleteShoppingTrip(ctx context.Context) (pantry.ShoppingTrip, error) {
+	list, err := s.ShoppingList(ctx)
+	if err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+
+	// Only lines still on the list count, so a tick left on something since
+	// dropped from the meal plan doesn't stock the pantry with it; the repo
+	// clears such ticks.
+	var items, stock, listed []string
+	for _, item := range list {
+		listed = append(listed, item.Name)
+		if !item.Checked {
+			continue
+		}
+		items = append(items, item.Name)
+		if item.Source != pantry.ShoppingSourceCustom {
+			stock = append(stock, item.Name)
+		}
+	}
+	if len(items) == 0 {
+		return pantry.ShoppingTrip{}, pantry.ErrNothingChecked
+	}
+
+	trip, err := s.repo.CompleteShoppingTrip(ctx, items, stock, listed)
+	if errors.Is(err, pantry.ErrNothingChecked) {
+		return pantry.ShoppingTrip{}, err
+	}
+	if err != nil {
+		s.probe.PantryError("shopping_complete", err)
+		return pantry.ShoppingTrip{}, err
+	}
+	for _, ingredient := range trip.Stocked {
+		s.probe.PantryChanged("add", ingredient)
+	}
+	s.probe.PantryChanged("shopping_complete", trip.UUID.String())
+	return trip, nil
+}
+
+func (s *pantryService) ShoppingTrips(ctx context.Context, limit int) ([]pantry.ShoppingTrip, error) {
+	if limit <= 0 {
+		limit = DefaultTripHistory
+	}
+	trips, err := s.repo.ListShoppingTrips(ctx, min(limit, MaxTripHistory))
+	if err != nil {
+		s.probe.PantryError("shopping_trips", err)
+		return nil, err
+	}
+	return trips, nil
+}
+
+func (s *pantryService) SetShoppingItemCategory(ctx context.Context, name, category string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("shopping list item name is required")
+	}
+	category, err := pantry.NormalizeCategory(category)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetCustomShoppingItemCategory(ctx, name, category); err != nil {
+		if !errors.Is(err, pantry.ErrShoppingItemNotFound) {
+			s.probe.PantryError("shopping_categorise", err)
+		}
+		return err
+	}
+	s.probe.PantryChanged("shopping_categorise", name)
+	return nil
+}
+
+func (s *pantryService) StoreLayout(ctx context.Context) ([]string, error) {
+	layout, err := s.repo.GetStoreLayout(ctx)
+	if err != nil {
+		s.probe.PantryError("store_layout", err)
+		return nil, err
+	}
+	return layout.Order(), nil
+}
+
+func (s *pantryService) SaveStoreLayout(ctx context.Context, categories []string) ([]string, error) {
+	layout, err := pantry.NormalizeStoreLayout(categories)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveStoreLayout(ctx, layout); err != nil {
+		s.probe.PantryError("store_layout", err)
+		return nil, err
+	}
+	s.probe.PantryChanged("store_layout", strings.Join(layout, ","))
+	return layout.Order(), nil
+}
+
+func (s *pantryService) AddShoppingItems(ctx context.Context, names []string) (pantry.ShoppingAdditions, error) {
+	result := pantry.ShoppingAdditions{Added: []pantry.ShoppingListItem{}, NeedsReview: []pantry.ShoppingReview{}}
+	catalogue, err := s.repo.ListIngredientCatalogue(ctx)
+	if err != nil {
+		s.probe.PantryError("shopping_add", err)
+		return result, err
+	}
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		match := catalogue.Match(name)
+		switch {
+		case match.Ingredient != "":
+			added, err := s.AddIngredientShoppingItem(ctx, match.Ingredient)
+			if err != nil {
+				return result, err
+			}
+			result.Added = append(result.Added, categorised(pantry.ShoppingListItem{Name: added, Source: pantry.ShoppingSourceIngredient}))
+		case len(match.Candidates) > 0:
+			result.NeedsReview = append(result.NeedsReview, pantry.ShoppingReview{Name: name, Candidates: match.Candidates})
+		default:
+			if err := s.AddCustomShoppingItem(ctx, name); err != nil {
+				return result, err
+			}
+			result.Added = append(result.Added, categorised(pantry.ShoppingListItem{Name: name, Source: pantry.ShoppingSourceCustom}))
+		}
+	}
+	return result, nil
+}
+
+func (s *pantryService) AddIngredientShoppingItem(ctx context.Context, ingredient string) (string, error) {
+	ingredient = strings.TrimSpace(ingredient)
+	if ingredient == "" {
+		return "", fmt.Errorf("ingredient name is required")
+	}
+	name, err := s.repo.AddIngredientShoppingItem(ctx, ingredient)
+	if err != nil {
+		s.observeFailure("shopping_add_ingredient", ingredient, err)
+		return "", err
+	}
+	s.probe.PantryChanged("shopping_add_ingredient", name)
+	return name, nil
+}
+
 func (s *pantryService) AddCustomShoppingItem(ctx context.Context, name string) error {
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -133,3 +489,40 @@ func (s *pantryService) RemoveCustomShoppingItem(ctx context.Context, name strin
 	s.probe.PantryChanged("shopping_remove_custom", name)
 	return nil
 }
+
+func (s *pantryService) ShareList(ctx context.Context, list string, ttl time.Duration) (pantry.ListShare, error) {
+	if list != pantry.ListShoppingList && list != pantry.ListPantry {
+		return pantry.ListShare{}, fmt.Errorf("unknown list %q", list)
+	}
+	if ttl <= 0 {
+		ttl = DefaultShareTTL
+	}
+	ttl = min(ttl, MaxShareTTL)
+
+	// 128 bits of randomness: the token is the link's only protection.
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return pantry.ListShare{}, fmt.Errorf("generating share token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	share, err := s.repo.CreateListShare(ctx, token, list, ttl)
+	if err != nil {
+		s.probe.PantryError("share", err)
+		return pantry.ListShare{}, err
+	}
+	s.probe.PantryChanged("share", list)
+	return share, nil
+}
+
+func (s *pantryService) GetShare(ctx context.Context, token string) (pantry.ListShare, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return pantry.ListShare{}, pantry.ErrShareNotFound
+	}
+	share, err := s.repo.GetListShare(ctx, token)
+	if err != nil && !errors.Is(err, pantry.ErrShareNotFound) {
+		s.probe.PantryError("get_share", err)
+	}
+	return share, err
+}
@@ -3,20 +3,35 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 )
 
 type stubPantryRepo struct {
-	added   []string
-	removed []string
-	err     error
+	added         []string
+	removed       []string
+	shares        []pantry.ListShare
+	shortfall     []pantry.ShoppingListItem
+	custom        []pantry.ShoppingListItem
+	layout        pantry.StoreLayout
+	catalogue     recipe.IngredientCatalogue
+	customAdd     []string
+	listed        []string
+	checks        []string
+	staples       map[string]bool
+	newIngredient bool
+	trip          struct{ items, stock, listed []string }
+	tripErr       error
+	err           error
 }
 
-func (s *stubPantryRepo) AddToPantry(_ context.Context, ingredient string) error {
+func (s *stubPantryRepo) AddToPantry(_ context.Context, ingredient string) (string, bool, error) {
 	s.added = append(s.added, ingredient)
-	return s.err
+	return ingredient, s.newIngredient, s.err
 }
 
 func (s *stubPantryRepo) RemoveFromPantry(_ context.Context, ingredient string) error {
@@ -28,14 +43,84 @@ func (s *stubPantryRepo) ListPantry(context.Context) ([]pantry.PantryItem, error
 	return nil, s.err
 }
 
-func (s *stubPantryRepo) ShoppingList(context.Context) ([]string, error) { return nil, s.err }
+func (s *stubPantryRepo) SetStaple(_ context.Context, ingredient string, staple bool) (string, error) {
+	if s.staples == nil {
+		s.staples = map[string]bool{}
+	}
+	s.staples[ingredient] = staple
+	return ingredient, s.err
+}
+func (s *stubPantryRepo) MarkOut(_ context.Context, ingredient string) (string, bool, error) {
+	return ingredient, s.staples[ingredient], s.err
+}
+func (s *stubPantryRepo) ListStaples(context.Context) ([]pantry.Staple, error) { return nil, s.err }
 
-func (s *stubPantryRepo) AddCustomShoppingItem(context.Context, string) error    { return s.err }
+func (s *stubPantryRepo) ShoppingList(context.Context) ([]pantry.ShoppingListItem, error) {
+	return s.shortfall, s.err
+}
+
+func (s *stubPantryRepo) AddCustomShoppingItem(_ context.Context, name string) error {
+	s.customAdd = append(s.customAdd, name)
+	return s.err
+}
+func (s *stubPantryRepo) AddIngredientShoppingItem(_ context.Context, ingredient string) (string, error) {
+	s.listed = append(s.listed, ingredient)
+	return ingredient, s.err
+}
+func (s *stubPantryRepo) ListIngredientCatalogue(context.Context) (recipe.IngredientCatalogue, error) {
+	return s.catalogue, s.err
+}
 func (s *stubPantryRepo) RemoveCustomShoppingItem(context.Context, string) error { return s.err }
-func (s *stubPantryRepo) ListCustomShoppingItems(context.Context) ([]string, error) {
+func (s *stubPantryRepo) ListCustomShoppingItems(context.Context) ([]pantry.ShoppingListItem, error) {
+	return s.custom, s.err
+}
+
+func (s *stubPantryRepo) CheckShoppingItem(_ context.Context, name string) error {
+	s.checks = append(s.checks, name)
+	return s.err
+}
+func (s *stubPantryRepo) UncheckShoppingItem(context.Context, string) error { return s.err }
+func (s *stubPantryRepo) CompleteShoppingTrip(_ context.Context, items, stock, listed []string) (pantry.ShoppingTrip, error) {
+	s.trip.items, s.trip.stock, s.trip.listed = items, stock, listed
+	if s.tripErr != nil {
+		return pantry.ShoppingTrip{}, s.tripErr
+	}
+	return pantry.ShoppingTrip{Items: items, Stocked: stock}, s.err
+}
+func (s *stubPantryRepo) ListShoppingTrips(context.Context, int) ([]pantry.ShoppingTrip, error) {
 	return nil, s.err
 }
 
+func (s *stubPantryRepo) SetIngredientCategory(context.Context, string, string) error { return s.err }
+func (s *stubPantryRepo) SetCustomShoppingItemCategory(context.Context, string, string) error {
+	return s.err
+}
+func (s *stubPantryRepo) ListIngredientCategories(context.Context) ([]pantry.IngredientCategory, error) {
+	return nil, s.err
+}
+func (s *stubPantryRepo) GetStoreLayout(context.Context) (pantry.StoreLayout, error) {
+	return s.layout, s.err
+}
+func (s *stubPantryRepo) SaveStoreLayout(_ context.Context, layout pantry.StoreLayout) error {
+	s.layout = layout
+	return s.err
+}
+
+func (s *stubPantryRepo) CreateListShare(_ context.Context, token, list string, ttl time.Duration) (pantry.ListShare, error) {
+	share := pantry.ListShare{Token: token, List: list, ExpiresAt: time.Now().Add(ttl)}
+	s.shares = append(s.shares, share)
+	return share, s.err
+}
+
+func (s *stubPantryRepo) GetListShare(_ context.Context, token string) (pantry.ListShare, error) {
+	for _, share := range s.shares {
+		if share.Token == token {
+			return share, s.err
+		}
+	}
+	return pantry.ListShare{}, pantry.ErrShareNotFound
+}
+
 type recordingProbe struct {
 	changed  []string
 	failed   []string
@@ -59,7 +144,7 @@ func TestPantryMutationsTrimNames(t *testing.T) {
 	probe := &recordingProbe{}
 	svc := NewPantryService(repo, probe)
 
-	if err := svc.AddToPantry(context.Background(), "  plain flour \n"); err != nil {
+	if _, _, err := svc.AddToPantry(context.Background(), "  plain flour \n"); err != nil {
 		t.Fatalf("AddToPantry() error = %v", err)
 	}
 	if err := svc.RemoveFromPantry(context.Background(), " salt "); err != nil {
@@ -81,7 +166,7 @@ func TestPantryMutationsRejectBlankNames(t *testing.T) {
 	repo := &stubPantryRepo{}
 	svc := NewPantryService(repo, &recordingProbe{})
 
-	if err := svc.AddToPantry(context.Background(), "   "); err == nil {
+	if _, _, err := svc.AddToPantry(context.Background(), "   "); err == nil {
 		t.Error("AddToPantry() error = nil, want required-field error")
 	}
 	if err := svc.RemoveFromPantry(context.Background(), ""); err == nil {
@@ -99,15 +184,15 @@ func TestUnknownIngredientIsNotCountedAsAnError(t *testing.T) {
 	probe := &recordingProbe{}
 	svc := NewPantryService(repo, probe)
 
-	err := svc.AddToPantry(context.Background(), "unobtainium")
+	err := svc.RemoveFromPantry(context.Background(), "unobtainium")
 	if !errors.Is(err, pantry.ErrIngredientNotFound) {
-		t.Fatalf("AddToPantry() error = %v, want ErrIngredientNotFound", err)
+		t.Fatalf("RemoveFromPantry() error = %v, want ErrIngredientNotFound", err)
 	}
 	if len(probe.failed) != 0 {
 		t.Errorf("probe errors = %v, want none", probe.failed)
 	}
-	if len(probe.unknowns) != 1 || probe.unknowns[0] != "add:unobtainium" {
-		t.Errorf("probe unknowns = %v, want [add:unobtainium]", probe.unknowns)
+	if len(probe.unknowns) != 1 || probe.unknowns[0] != "remove:unobtainium" {
+		t.Errorf("probe unknowns = %v, want [remove:unobtainium]", probe.unknowns)
 	}
 	if len(probe.changed) != 0 {
 		t.Errorf("probe changes = %v, want none — nothing changed", probe.changed)
@@ -129,3 +214,245 @@ func TestRepositoryFaultIsCountedAsAnError(t *testing.T) {
 		t.Errorf("probe unknowns = %v, want none", probe.unknowns)
 	}
 }
+
+func TestShareListDefaultsAndClampsTTL(t *testing.T) {
+	repo := &stubPantryRepo{}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	share, err := svc.ShareList(context.Background(), pantry.ListShoppingList, 0)
+	if err != nil {
+		t.Fatalf("ShareList() error = %v", err)
+	}
+	if share.Token == "" {
+		t.Fatal("expected a token")
+	}
+	if d := time.Until(share.ExpiresAt); d < DefaultShareTTL-time.Minute || d > DefaultShareTTL {
+		t.Errorf("default expiry in %v, want ~%v", d, DefaultShareTTL)
+	}
+
+	long, err := svc.ShareList(context.Background(), pantry.ListPantry, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ShareList() error = %v", err)
+	}
+	if d := time.Until(long.ExpiresAt); d > MaxShareTTL {
+		t.Errorf("expiry in %v, want at most %v", d, MaxShareTTL)
+	}
+	if long.Token == share.Token {
+		t.Error("expected distinct tokens")
+	}
+
+	got, err := svc.GetShare(context.Background(), share.Token)
+	if err != nil || got.List != pantry.ListShoppingList {
+		t.Errorf("GetShare() = %+v, %v", got, err)
+	}
+}
+
+func TestShareListRejectsUnknownList(t *testing.T) {
+	svc := NewPantryService(&stubPantryRepo{}, &recordingProbe{})
+
+	if _, err := svc.ShareList(context.Background(), "fridge", 0); err == nil {
+		t.Fatal("expected an error for an unknown list")
+	}
+}
+
+func TestGetShareUnknownTokenIsNotAnError(t *testing.T) {
+	probe := &recordingProbe{}
+	svc := NewPantryService(&stubPantryRepo{}, probe)
+
+	_, err := svc.GetShare(context.Background(), "nope")
+	if !errors.Is(err, pantry.ErrShareNotFound) {
+		t.Fatalf("GetShare() error = %v, want ErrShareNotFound", err)
+	}
+	if len(probe.failed) != 0 {
+		t.Errorf("failed = %v, want none", probe.failed)
+	}
+}
+
+// Items with no stored category are guessed, and the guess misses land in
+// other, so every item is walked to.
+func TestShoppingAislesCategorisesEveryItem(t *testing.T) {
+	repo := &stubPantryRepo{
+		shortfall: []pantry.ShoppingListItem{{Name: "onion"}, {Name: "quark", Category: pantry.CategoryDairy}},
+		custom:    []pantry.ShoppingListItem{{Name: "bin bags", Source: pantry.ShoppingSourceCustom}, {Name: "birthday card", Source: pantry.ShoppingSourceCustom}},
+		layout:    pantry.StoreLayout{pantry.CategoryHousehold},
+	}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	sections, err := svc.ShoppingAisles(context.Background())
+	if err != nil {
+		t.Fatalf("ShoppingAisles() error = %v", err)
+	}
+	var got []string
+	for _, s := range sections {
+		for _, item := range s.Items {
+			got = append(got, s.Category+":"+item.Name+":"+item.Source)
+		}
+	}
+	want := []string{
+		"household:bin bags:custom",
+		"produce:onion:meal_plan",
+		"dairy:quark:meal_plan",
+		"other:birthday card:custom",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("items = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestAddShoppingItemsResolvesAgainstIngredients(t *testing.T) {
+	repo := &stubPantryRepo{catalogue: recipe.IngredientCatalogue{
+		{Name: "beef stock", Aliases: []string{}},
+		{Name: "chicken stock", Aliases: []string{}},
+		{Name: "tomato", Aliases: []string{}},
+	}}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	got, err := svc.AddShoppingItems(context.Background(), []string{"Tomatoes", " stock ", "bin bags", "tomatoes", ""})
+	if err != nil {
+		t.Fatalf("AddShoppingItems() error = %v", err)
+	}
+
+	if len(repo.listed) != 1 || repo.listed[0] != "tomato" {
+		t.Errorf("ingredients listed = %v, want [tomato]", repo.listed)
+	}
+	if len(repo.customAdd) != 1 || repo.customAdd[0] != "bin bags" {
+		t.Errorf("custom items = %v, want [bin bags]", repo.customAdd)
+	}
+	if len(got.Added) != 2 || got.Added[0].Source != pantry.ShoppingSourceIngredient || got.Added[1].Source != pantry.ShoppingSourceCustom {
+		t.Errorf("added = %+v, want tomato as an ingredient then bin bags", got.Added)
+	}
+	if len(got.NeedsReview) != 1 || got.NeedsReview[0].Name != "stock" || len(got.NeedsReview[0].Candidates) != 2 {
+		t.Errorf("needs review = %+v, want stock with two candidates", got.NeedsReview)
+	}
+}
+
+func TestShoppingListListsPlannedIngredientsOnce(t *testing.T) {
+	repo := &stubPantryRepo{
+		shortfall: []pantry.ShoppingListItem{{Name: "Tomato"}},
+		custom: []pantry.ShoppingListItem{
+			{Name: "tomato", Source: pantry.ShoppingSourceIngredient},
+			{Name: "bin bags", Source: pantry.ShoppingSourceCustom},
+		},
+	}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	items, err := svc.ShoppingList(context.Background())
+	if err != nil {
+		t.Fatalf("ShoppingList() error = %v", err)
+	}
+	if len(items) != 2 || items[0].Source != pantry.ShoppingSourceMealPlan || items[1].Name != "bin bags" {
+		t.Errorf("items = %+v, want the planned tomato and bin bags", items)
+	}
+}
+
+func TestCompleteShoppingTripStocksCheckedIngredients(t *testing.T) {
+	repo := &stubPantryRepo{
+		shortfall: []pantry.ShoppingListItem{{Name: "onion", Checked: true}, {Name: "quark"}},
+		custom: []pantry.ShoppingListItem{
+			{Name: "eggs", Source: pantry.ShoppingSourceIngredient, Checked: true},
+			{Name: "bin bags", Source: pantry.ShoppingSourceCustom, Checked: true},
+		},
+	}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	trip, err := svc.CompleteShoppingTrip(context.Background())
+	if err != nil {
+		t.Fatalf("CompleteShoppingTrip() error = %v", err)
+	}
+	if got := strings.Join(repo.trip.items, ","); got != "onion,eggs,bin bags" {
+		t.Errorf("items = %s, want onion,eggs,bin bags", got)
+	}
+	if got := strings.Join(trip.Stocked, ","); got != "onion,eggs" {
+		t.Errorf("stocked = %s, want onion,eggs", got)
+	} // Every line on the list, so the repo can clear ticks on the rest.
+	if got := strings.Join(repo.trip.listed, ","); got != "onion,quark,eggs,bin bags" {
+		t.Errorf("listed = %s, want onion,quark,eggs,bin bags", got)
+	}
+}
+
+func TestCompleteShoppingTripTakenByAnotherCompletion(t *testing.T) {
+	repo := &stubPantryRepo{
+		shortfall: []pantry.ShoppingListItem{{Name: "onion", Checked: true}},
+		tripErr:   pantry.ErrNothingChecked,
+	}
+	probe := &recordingProbe{}
+	svc := NewPantryService(repo, probe)
+
+	if _, err := svc.CompleteShoppingTrip(context.Background()); !errors.Is(err, pantry.ErrNothingChecked) {
+		t.Fatalf("CompleteShoppingTrip() error = %v, want ErrNothingChecked", err)
+	}
+	if len(probe.failed) != 0 {
+		t.Errorf("probe failed = %v, want none for a lost race", probe.failed)
+	}
+}
+
+func TestCompleteShoppingTripNeedsSomethingChecked(t *testing.T) {
+	repo := &stubPantryRepo{shortfall: []pantry.ShoppingListItem{{Name: "onion"}}}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	if _, err := svc.CompleteShoppingTrip(context.Background()); !errors.Is(err, pantry.ErrNothingChecked) {
+		t.Fatalf("CompleteShoppingTrip() error = %v, want ErrNothingChecked", err)
+	}
+	if repo.trip.items != nil {
+		t.Errorf("expected no trip recorded, got %v", repo.trip.items)
+	}
+}
+
+func TestCheckShoppingItemMustBeOnTheList(t *testing.T) {
+	repo := &stubPantryRepo{shortfall: []pantry.ShoppingListItem{{Name: "Onion"}}}
+	svc := NewPantryService(repo, &recordingProbe{})
+
+	if err := svc.SetShoppingItemChecked(context.Background(), "onion", true); err != nil {
+		t.Fatalf("SetShoppingItemChecked(onion) error = %v", err)
+	}
+	if err := svc.SetShoppingItemChecked(context.Background(), "caviar", true); !errors.Is(err, pantry.ErrShoppingItemNotFound) {
+		t.Fatalf("SetShoppingItemChecked(caviar) error = %v, want ErrShoppingItemNotFound", err)
+	}
+	if len(repo.checks) != 1 || repo.checks[0] != "onion" {
+		t.Errorf("checks = %v, want [onion]", repo.checks)
+	}
+}
+
+func TestSetStapleTrimsAndReportsUnknownIngredients(t *testing.T) {
+	repo := &stubPantryRepo{}
+	probe := &recordingProbe{}
+	svc := NewPantryService(repo, probe)
+
+	if _, err := svc.SetStaple(context.Background(), " salt ", true); err != nil {
+		t.Fatalf("SetStaple() error = %v", err)
+	}
+	if !repo.staples["salt"] {
+		t.Errorf("staples = %v, want salt", repo.staples)
+	}
+
+	repo.err = pantry.IngredientNotFoundError{Name: "unobtainium"}
+	if _, _, err := svc.MarkOut(context.Background(), "unobtainium"); !errors.Is(err, pantry.ErrIngredientNotFound) {
+		t.Fatalf("MarkOut() error = %v, want ErrIngredientNotFound", err)
+	}
+	if len(probe.unknowns) != 1 || probe.unknowns[0] != "out:unobtainium" || len(probe.failed) != 0 {
+		t.Errorf("unknowns = %v, failed = %v", probe.unknowns, probe.failed)
+	}
+}
+
+func TestAddToPantryReportsNewIngredients(t *testing.T) {
+	repo := &stubPantryRepo{newIngredient: true}
+	probe := &recordingProbe{}
+	svc := NewPantryService(repo, probe)
+
+	name, created, err := svc.AddToPantry(context.Background(), " tinned tomatoes ")
+	if err != nil {
+		t.Fatalf("AddToPantry() error = %v", err)
+	}
+	if name != "tinned tomatoes" || !created {
+		t.Errorf("AddToPantry() = %q, %v, want tinned tomatoes, created", name, created)
+	}
+	if len(probe.changed) != 1 || probe.changed[0] != "add_new:tinned tomatoes" {
+		t.Errorf("probe changes = %v, want [add_new:tinned tomatoes]", probe.changed)
+	}
+}
@@ -0,0 +1,85 @@
+package recipe
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Annotation is a personal note on a recipe ("halve the chilli for the kids")
+// or on one of its steps ("use the big pan"). Step is the step's position
+// (Step.Order), or zero for a note on the whole recipe. Annotations are kept
+// apart from the recipe body; editing the recipe only moves step notes to
+// follow their steps (see ReanchorAnnotations).
+type Annotation struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Step      int16     `json:"step,omitempty"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ValidateAnnotation checks a note about to be added to r.
+func ValidateAnnotation(r Recipe, a Annotation) error {
+	if strings.TrimSpace(a.Note) == "" {
+		return InvalidAnnotationError{Reason: "note must not be empty"}
+	}
+	if a.Step < 0 || int(a.Step) > len(r.Steps) {
+		return InvalidAnnotationError{Reason: "step must be one of the recipe's steps"}
+	}
+	return nil
+}
+
+// ReanchorAnnotations follows step notes through an edit of the steps from
+// before to after. A note stays put while its step's text is unchanged at the
+// same position, follows the step by its text when it moved, and goes on the
+// recipe as a whole when its step was reworded or removed, rather than onto
+// whichever step took its place. It returns the notes whose Step changed.
+func ReanchorAnnotations(before, after []Step, annotations []Annotation) []Annotation {
+	text := func(steps []Step, order int16) (string, bool) {
+		for _, s := range steps {
+			if s.Order == order {
+				return strings.TrimSpace(s.Description), true
+			}
+		}
+		return "", false
+	}
+
+	var moved []Annotation
+	for _, a := range annotations {
+		if a.Step == 0 {
+			continue
+		}
+		was, ok := text(before, a.Step)
+		if now, _ := text(after, a.Step); ok && now == was {
+			continue
+		}
+		to := int16(0)
+		if ok {
+			for _, s := range after {
+				if strings.TrimSpace(s.Description) == was {
+					to = s.Order
+					break
+				}
+			}
+		}
+		if to != a.Step {
+			a.Step = to
+			moved = append(moved, a)
+		}
+	}
+	return moved
+}
+
+// AttachAnnotations sets r's annotations. A step note whose step has since
+// been removed (the method got shorter) is shown against the recipe instead,
+// rather than hidden or pinned to a step that doesn't exist.
+func AttachAnnotations(r *Recipe, annotations []Annotation) {
+	for i := range annotations {
+		if int(annotations[i].Step) > len(r.Steps) {
+			annotations[i].Step = 0
+		}
+	}
+	r.Annotations = annotations
+}
@@ -0,0 +1,88 @@
+package recipe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAnnotation(t *testing.T) {
+	r := Recipe{Steps: []Step{{Order: 1}, {Order: 2}}}
+
+	tests := []struct {
+		name    string
+		a       Annotation
+		wantErr bool
+	}{
+		{"recipe note", Annotation{Note: "halve the chilli"}, false},
+		{"step note", Annotation{Note: "use the big pan", Step: 2}, false},
+		{"empty note", Annotation{Note: "   "}, true},
+		{"step past the end", Annotation{Note: "x", Step: 3}, true},
+		{"negative step", Annotation{Note: "x", Step: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAnnotation(r, tt.a)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ValidateAnnotation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAnnotation) {
+				t.Errorf("error %v is not ErrInvalidAnnotation", err)
+			}
+		})
+	}
+}
+
+func TestAttachAnnotationsRehomesRemovedSteps(t *testing.T) {
+	r := Recipe{Steps: []Step{{Order: 1}}}
+	AttachAnnotations(&r, []Annotation{{Note: "a", Step: 1}, {Note: "b", Step: 3}})
+
+	if got := r.Annotations[0].Step; got != 1 {
+		t.Errorf("in-range note moved to step %d", got)
+	}
+	if got := r.Annotations[1].Step; got != 0 {
+		t.Errorf("note on removed step kept step %d, want 0", got)
+	}
+}
+
+func TestReanchorAnnotations(t *testing.T) {
+	before := []Step{
+		{Order: 1, Description: "Fry the onions."},
+		{Order: 2, Description: "Add the spices."},
+		{Order: 3, Description: "Simmer."},
+		{Order: 4, Description: "Serve."},
+	}
+	// A step inserted at the top, "Simmer." reworded, "Serve." gone.
+	after := []Step{
+		{Order: 1, Description: "Rinse the lentils."},
+		{Order: 2, Description: "Fry the onions."},
+		{Order: 3, Description: "Add the spices."},
+		{Order: 4, Description: "Simmer for 20 minutes."},
+	}
+	notes := []Annotation{
+		{Note: "whole recipe"},
+		{Note: "use the big pan", Step: 1},
+		{Note: "less cumin", Step: 2},
+		{Note: "lid on", Step: 3},
+		{Note: "with rice", Step: 4},
+	}
+
+	moved := ReanchorAnnotations(before, after, notes)
+
+	got := map[string]int16{}
+	for _, a := range moved {
+		got[a.Note] = a.Step
+	}
+	want := map[string]int16{"use the big pan": 2, "less cumin": 3, "lid on": 0, "with rice": 0}
+	if len(got) != len(want) {
+		t.Fatalf("moved = %+v, want %v", moved, want)
+	}
+	for note, step := range want {
+		if s, ok := got[note]; !ok || s != step {
+			t.Errorf("%q moved to step %d (moved: %t), want %d", note, s, ok, step)
+		}
+	}
+
+	if moved := ReanchorAnnotations(before, before, notes); len(moved) != 0 {
+		t.Errorf("an unchanged method moved notes: %+v", moved)
+	}
+}
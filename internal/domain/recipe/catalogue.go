@@ -0,0 +1,251 @@
+package recipe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IngredientSummary is an ingredient in the catalogue, with the other names
+// it goes by and how much it is used: by how many recipes, and whether it is
+// in the pantry. PantryOnly marks one the pantry added that no recipe has
+// used yet; once one has, it stays clear.
+type IngredientSummary struct {
+	Name       string    `json:"name"`
+	Aliases    []string  `json:"aliases"`
+	Uses       int       `json:"uses"`
+	InPantry   bool      `json:"inPantry"`
+	PantryOnly bool      `json:"pantryOnly"`
+	CreatedAt  time.Time `json:"createdAt,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt,omitempty"`
+}
+
+// IngredientCatalogue is every ingredient, ordered by name ignoring case,
+// oldest first among names that differ only in case.
+type IngredientCatalogue []IngredientSummary
+
+// NormalizeIngredientName trims name and collapses the whitespace inside it.
+// Case is kept: ingredients are stored as written.
+func NormalizeIngredientName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// IngredientNameForms lists the lowercase spellings that name matches: itself
+// and its simple singular and plural forms, so "Spring Onions" finds "spring
+// onion". Saves and pantry lookups resolve a name to an ingredient whose name
+// or alias is one of these.
+func IngredientNameForms(name string) []string {
+	key := strings.ToLower(NormalizeIngredientName(name))
+	if key == "" {
+		return nil
+	}
+	var forms []string
+	for _, f := range pluralVariants(key) {
+		if !containsString(forms, f) {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
+
+// NormalizeIngredientAliases lowercases and tidies aliases, dropping blanks,
+// repeats and any that are just a form of name. It never returns nil.
+func NormalizeIngredientAliases(name string, aliases []string) []string {
+	own := IngredientNameForms(name)
+	out := []string{}
+	for _, a := range aliases {
+		a = strings.ToLower(NormalizeIngredientName(a))
+		if a == "" || containsString(own, a) || containsString(out, a) {
+			continue
+		}
+		out = append(out, a)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Resolve finds the ingredient name refers to, as saves do: an exact match
+// first, then one differing only in case, then a singular or plural form of
+// the name, then an alias. Ties go to the oldest ingredient.
+func (c IngredientCatalogue) Resolve(name string) (IngredientSummary, bool) {
+	name = NormalizeIngredientName(name)
+	forms := IngredientNameForms(name)
+	if len(forms) == 0 {
+		return IngredientSummary{}, false
+	}
+
+	const noMatch = 4
+	best, bestRank := -1, noMatch
+	for i, ing := range c {
+		rank := noMatch
+		switch {
+		case ing.Name == name:
+			rank = 0
+		case strings.EqualFold(ing.Name, name):
+			rank = 1
+		case containsString(forms, strings.ToLower(ing.Name)):
+			rank = 2
+		default:
+			for _, a := range ing.Aliases {
+				if containsString(forms, a) {
+					rank = 3
+					break
+				}
+			}
+		}
+		if rank < bestRank || (rank == bestRank && rank < noMatch && ing.CreatedAt.Before(c[best].CreatedAt)) {
+			best, bestRank = i, rank
+		}
+	}
+	if best < 0 {
+		return IngredientSummary{}, false
+	}
+	return c[best], true
+}
+
+// Find returns the ingredient stored under exactly name, as the admin
+// changes address them, so "Salt" and "salt" can be told apart to merge.
+func (c IngredientCatalogue) Find(name string) (IngredientSummary, bool) {
+	name = NormalizeIngredientName(name)
+	for _, ing := range c {
+		if ing.Name == name {
+			return ing, true
+		}
+	}
+	return IngredientSummary{}, false
+}
+
+// CheckNames makes sure none of names already means an ingredient other
+// than self, so giving them to self leaves every name meaning one thing.
+func (c IngredientCatalogue) CheckNames(self string, names ...string) error {
+	for _, n := range names {
+		if other, ok := c.Resolve(n); ok && other.Name != self {
+			return InvalidIngredientError{Reason: fmt.Sprintf("%q already means %q; merge the ingredients instead", n, other.Name)}
+		}
+	}
+	return nil
+}
+
+// IngredientMatch is what free text, typed or scanned onto the shopping list,
+// resolved to: one ingredient, or candidates someone has to choose between,
+// or neither when the text isn't a known ingredient at all.
+type IngredientMatch struct {
+	Ingredient string   `json:"ingredient,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// maxMatchCandidates caps how many candidates Match offers.
+const maxMatchCandidates = 5
+
+// Match resolves text as Resolve does: exactly, by case, by a singular or
+// plural form, or by an alias. Failing that, it offers candidates without
+// assuming any: ingredients with a name or alias a letter or two off
+// ("tomatoe"), or, when there are none, ingredients text shares words with
+// ("free range eggs", "stock" for "chicken stock"). A near miss can be a
+// different thing entirely — "beer" isn't "beef" — so only someone choosing
+// turns a candidate into a match.
+func (c IngredientCatalogue) Match(text string) IngredientMatch {
+	if ing, ok := c.Resolve(text); ok {
+		return IngredientMatch{Ingredient: ing.Name}
+	}
+	forms := IngredientNameForms(text)
+	if len(forms) == 0 {
+		return IngredientMatch{}
+	}
+	words := strings.Fields(forms[0])
+
+	var typos, related []string
+	for _, ing := range c {
+		names := append([]string{strings.ToLower(ing.Name)}, ing.Aliases...)
+		switch {
+		case anyMisspelling(forms, names):
+			typos = append(typos, ing.Name)
+		case anySharedWords(words, names):
+			related = append(related, ing.Name)
+		}
+	}
+	candidates := typos
+	if len(candidates) == 0 {
+		candidates = related
+	}
+	sort.Strings(candidates)
+	if len(candidates) > maxMatchCandidates {
+		candidates = candidates[:maxMatchCandidates]
+	}
+	return IngredientMatch{Candidates: candidates}
+}
+
+// anyMisspelling reports whether one of forms is within a small edit distance
+// of one of names: one edit for short names, two for longer ones, none for
+// names of three letters or fewer, where one edit is another word.
+func anyMisspelling(forms, names []string) bool {
+	for _, n := range names {
+		allowed := 0
+		switch {
+		case len(n) > 8:
+			allowed = 2
+		case len(n) > 3:
+			allowed = 1
+		}
+		for _, f := range forms {
+			if allowed > 0 && editDistance(f, n) <= allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anySharedWords reports whether every word of one of names is among words,
+// or every one of words among one of names', matching words through their
+// plural forms.
+func anySharedWords(words, names []string) bool {
+	for _, n := range names {
+		nameWords := strings.Fields(n)
+		if wordsWithin(nameWords, words) || wordsWithin(words, nameWords) {
+			return true
+		}
+	}
+	return false
+}
+
+func wordsWithin(sub, words []string) bool {
+	if len(sub) == 0 {
+		return false
+	}
+	for _, s := range sub {
+		found := false
+		for _, w := range words {
+			if containsString(pluralVariants(w), s) || containsString(pluralVariants(s), w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// editDistance is the Levenshtein distance between a and b, in bytes.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
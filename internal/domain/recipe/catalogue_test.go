@@ -0,0 +1,114 @@
+package recipe
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testCatalogue = IngredientCatalogue{
+	{Name: "salt", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "Salt", CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "spring onion", Aliases: []string{"scallion", "scallions"}},
+	{Name: "tomatoes"},
+}
+
+func TestIngredientNameForms(t *testing.T) {
+	got := IngredientNameForms("  Spring   Onions ")
+	want := []string{"spring onions", "spring onion"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IngredientNameForms() = %v, want %v", got, want)
+	}
+	if got := IngredientNameForms("Peppers"); !reflect.DeepEqual(got, []string{"peppers"}) {
+		t.Errorf("IngredientNameForms(Peppers) = %v, want the bell peppers kept apart from pepper", got)
+	}
+	if got := IngredientNameForms("  "); got != nil {
+		t.Errorf("IngredientNameForms(blank) = %v, want nil", got)
+	}
+}
+
+func TestIngredientCatalogue_Resolve(t *testing.T) {
+	tests := map[string]string{
+		"Salt":          "Salt",
+		"SALT":          "salt",
+		"spring onions": "spring onion",
+		"Scallions":     "spring onion",
+		"tomato":        "tomatoes",
+	}
+	for name, want := range tests {
+		got, ok := testCatalogue.Resolve(name)
+		if !ok || got.Name != want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", name, got.Name, ok, want)
+		}
+	}
+	if got, ok := testCatalogue.Resolve("saffron"); ok {
+		t.Errorf("Resolve(saffron) = %q, want no match", got.Name)
+	}
+}
+
+func TestNormalizeIngredientAliases(t *testing.T) {
+	got := NormalizeIngredientAliases("spring onion", []string{" Scallions", "spring onions", "", "green  onion", "scallions"})
+	want := []string{"green onion", "scallions"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeIngredientAliases() = %v, want %v", got, want)
+	}
+}
+
+func TestIngredientCatalogue_CheckNames(t *testing.T) {
+	if err := testCatalogue.CheckNames("spring onion", "green onion", "scallion"); err != nil {
+		t.Errorf("CheckNames() = %v, want nil", err)
+	}
+	if err := testCatalogue.CheckNames("tomatoes", "scallions"); !errors.Is(err, ErrInvalidIngredient) {
+		t.Errorf("CheckNames() = %v, want ErrInvalidIngredient", err)
+	}
+}
+
+func TestIngredientCatalogue_Match(t *testing.T) {
+	catalogue := IngredientCatalogue{
+		{Name: "egg"},
+		{Name: "tomato"},
+		{Name: "chicken stock"},
+		{Name: "vegetable stock"},
+		{Name: "spring onion", Aliases: []string{"scallions"}},
+		{Name: "basil"},
+		{Name: "basmati rice"},
+	}
+
+	tests := []struct {
+		text       string
+		ingredient string
+		candidates []string
+	}{
+		{"Eggs", "egg", nil},
+		{"scallion", "spring onion", nil},
+		{"tomatoe", "", []string{"tomato"}},
+		{"stock", "", []string{"chicken stock", "vegetable stock"}},
+		{"free range eggs", "", []string{"egg"}},
+		{"washing-up liquid", "", nil},
+		{"bazil", "", []string{"basil"}},
+	}
+	for _, tt := range tests {
+		got := catalogue.Match(tt.text)
+		if got.Ingredient != tt.ingredient || strings.Join(got.Candidates, ",") != strings.Join(tt.candidates, ",") {
+			t.Errorf("Match(%q) = %+v, want ingredient %q candidates %q", tt.text, got, tt.ingredient, tt.candidates)
+		}
+	}
+}
+
+// Near misses are real shopping in their own right, so they're asked about,
+// never taken as the ingredient they're a letter away from.
+func TestIngredientCatalogue_MatchNearMissesNeedReview(t *testing.T) {
+	catalogue := IngredientCatalogue{{Name: "beef"}, {Name: "pears"}}
+
+	for text, candidate := range map[string]string{"beer": "beef", "peas": "pears"} {
+		got := catalogue.Match(text)
+		if got.Ingredient != "" {
+			t.Errorf("Match(%q) resolved to %q, want it held for review", text, got.Ingredient)
+		}
+		if len(got.Candidates) != 1 || got.Candidates[0] != candidate {
+			t.Errorf("Match(%q) candidates = %v, want [%s]", text, got.Candidates, candidate)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package recipe
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cook records one time a recipe was actually cooked: when, how it went and
+// anything worth remembering next time ("needed more salt"). Rating and
+// Servings are zero when not given.
+type Cook struct {
+	UUID      uuid.UUID `json:"uuid"`
+	RecipeID  uuid.UUID `json:"recipeId"`
+	CookedOn  time.Time `json:"cookedOn"`
+	Rating    int16     `json:"rating,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	Servings  int16     `json:"servings,omitempty"`
+	Photos    []Photo   `json:"photos"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// MarshalJSON emits CookedOn as a bare date: it's the day we ate it, not an
+// instant, and shouldn't shift for clients in other timezones.
+func (c Cook) MarshalJSON() ([]byte, error) {
+	type cookAlias Cook
+	photos := c.Photos
+	if photos == nil {
+		photos = []Photo{}
+	}
+	return json.Marshal(&struct {
+		CookedOn string  `json:"cookedOn"`
+		Photos   []Photo `json:"photos"`
+		cookAlias
+	}{
+		CookedOn:  c.CookedOn.Format(time.DateOnly),
+		Photos:    photos,
+		cookAlias: cookAlias(c),
+	})
+}
+
+// Validate checks the parts of a cook a caller supplies.
+func (c Cook) Validate() error {
+	if c.Rating != 0 && (c.Rating < 1 || c.Rating > 5) {
+		return InvalidCookError{Reason: "rating must be between 1 and 5"}
+	}
+	if c.Servings < 0 {
+		return InvalidCookError{Reason: "servings must not be negative"}
+	}
+	return nil
+}
+
+// CookStats sums up a recipe's cook log for summaries. LastCooked and
+// AverageRating are nil when it has never been cooked, or never rated.
+type CookStats struct {
+	TimesCooked   int        `json:"timesCooked"`
+	LastCooked    *time.Time `json:"lastCooked,omitempty"`
+	AverageRating *float64   `json:"averageRating,omitempty"`
+}
+
+// MarshalJSON emits LastCooked as a bare date, like Cook.CookedOn.
+func (s CookStats) MarshalJSON() ([]byte, error) {
+	var lastCooked *string
+	if s.LastCooked != nil {
+		day := s.LastCooked.Format(time.DateOnly)
+		lastCooked = &day
+	}
+	return json.Marshal(&struct {
+		TimesCooked   int      `json:"timesCooked"`
+		LastCooked    *string  `json:"lastCooked,omitempty"`
+		AverageRating *float64 `json:"averageRating,omitempty"`
+	}{
+		TimesCooked:   s.TimesCooked,
+		LastCooked:    lastCooked,
+		AverageRating: s.AverageRating,
+	})
+}
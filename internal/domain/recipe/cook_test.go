@@ -0,0 +1,52 @@
+package recipe
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookValidate(t *testing.T) {
+	if err := (Cook{}).Validate(); err != nil {
+		t.Errorf("expected an unrated cook to be valid, got %v", err)
+	}
+	if err := (Cook{Rating: 5, Servings: 4}).Validate(); err != nil {
+		t.Errorf("expected a 5-star cook to be valid, got %v", err)
+	}
+	for _, c := range []Cook{{Rating: 6}, {Rating: -1}, {Servings: -2}} {
+		if err := c.Validate(); !errors.Is(err, ErrInvalidCook) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidCook", c, err)
+		}
+	}
+}
+
+func TestCookJSON_DatesAreDays(t *testing.T) {
+	cook := Cook{CookedOn: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), Rating: 4}
+	data, err := json.Marshal(cook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"cookedOn":"2026-10-18"`) || !strings.Contains(string(data), `"photos":[]`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	last := cook.CookedOn
+	data, err = json.Marshal(Recipe{Name: "Dhal", CookStats: &CookStats{TimesCooked: 2, LastCooked: &last}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"cookStats":{"timesCooked":2,"lastCooked":"2026-10-18"}`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	// cookStats is output-only; a recipe read and saved back still decodes.
+	var r Recipe
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if r.CookStats != nil {
+		t.Errorf("expected cookStats to be ignored on input, got %+v", r.CookStats)
+	}
+}
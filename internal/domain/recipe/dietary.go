@@ -0,0 +1,210 @@
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DietFlag is something about an ingredient that rules it out of some diets.
+type DietFlag string
+
+const (
+	FlagMeat   DietFlag = "meat"
+	FlagFish   DietFlag = "fish"
+	FlagDairy  DietFlag = "dairy"
+	FlagEgg    DietFlag = "egg"
+	FlagGluten DietFlag = "gluten"
+	FlagNuts   DietFlag = "nuts"
+	// FlagAnimal is an animal product that is neither meat, fish, dairy nor
+	// egg: honey, gelatine.
+	FlagAnimal DietFlag = "animal"
+	// FlagFODMAP is a common high-FODMAP trigger: onion, garlic, wheat and
+	// the like.
+	FlagFODMAP DietFlag = "high_fodmap"
+)
+
+// dietaryRules flag ingredients by name. A name matching unless is stripped
+// of that phrase before pattern is tried, so "peanut butter" isn't dairy but
+// "peanut butter and butter" would be.
+var dietaryRules = []struct {
+	flag    DietFlag
+	pattern *regexp.Regexp
+	unless  *regexp.Regexp
+}{
+	{
+		FlagMeat,
+		regexp.MustCompile(`\b(chicken|beef|pork|lamb|mutton|veal|venison|turkey|duck|goose|rabbit|bacon|ham|gammon|sausages?|chorizo|pancetta|prosciutto|salami|pepperoni|mince|steaks?|lardons?|lard|suet|nduja|meatballs?|bone broth)\b`),
+		regexp.MustCompile(`\b(veggie|vegetarian|vegan|plant[- ]based|meat[- ]free|quorn|soya|soy) (mince|sausages?|burgers?|chicken|meatballs?|bacon)\b|\bvegetable suet\b|\b(tuna|salmon|cauliflower|mushroom) steaks?\b|\bbeef tomato(es)?\b|\bduck eggs?\b|\blamb'?s lettuce\b`),
+	},
+	{
+		FlagFish,
+		regexp.MustCompile(`\b(fish|salmon|tuna|cod|haddock|mackerel|sardines?|anchov(y|ies)|prawns?|shrimps?|crab|lobster|mussels?|clams?|squid|calamari|scallops?|oysters?|trout|sea bass|monkfish|kipper|worcestershire)\b`),
+		regexp.MustCompile(`\boyster mushrooms?\b|\bvegan (fish sauce|worcestershire)\b`),
+	},
+	{
+		FlagDairy,
+		regexp.MustCompile(`\b(milk|butter|buttermilk|cream|cheese|cheddar|parmesan|mozzarella|feta|halloumi|ricotta|mascarpone|paneer|burrata|brie|gruyere|gruyère|stilton|yogh?urt|ghee|cr[eè]me fra[iî]che|whey|custard)\b`),
+		regexp.MustCompile(`\b(coconut|almond|oat|soy|soya|rice|cashew|plant[- ]based|vegan|dairy[- ]free) (milk|cream|butter|cheese|yogh?urt)\b|\b(peanut|nut|almond|cashew|cocoa|shea) butter\b|\bcream of tartar\b|\bcream crackers?\b|\bbutter beans?\b`),
+	},
+	{
+		FlagEgg,
+		regexp.MustCompile(`\b(eggs?|egg (yolks?|whites?)|mayonnaise|mayo|meringues?|aioli)\b`),
+		regexp.MustCompile(`\b(vegan|egg[- ]free) (mayonnaise|mayo|meringues?)\b|\begg[- ]free\b`),
+	},
+	{
+		FlagGluten,
+		regexp.MustCompile(`\b(flour|wheat|bread|breadcrumbs|panko|pasta|spaghetti|linguine|penne|fusilli|tagliatelle|lasagne|orzo|noodles|couscous|bulgur|barley|rye|semolina|spelt|pastry|tortillas?|wraps?|pittas?|naan|seitan|soy sauce|beer|crackers?|biscuits?)\b`),
+		regexp.MustCompile(`\bgluten[- ]free \w+( \w+)?\b|\b(rice|corn|buckwheat|chickpea|gram|almond|coconut|potato|tapioca|cassava) (flour|noodles|pasta|tortillas?|wraps?)\b|\bcornflour\b`),
+	},
+	{
+		FlagNuts,
+		regexp.MustCompile(`\b(nuts?|almonds?|walnuts?|pecans?|cashews?|hazelnuts?|pistachios?|macadamias?|brazil nuts?|peanuts?|pine nuts|praline|marzipan|frangipane|nut butter)\b`),
+		regexp.MustCompile(`\bwater chestnuts?\b|\bnut[- ]free\b`),
+	},
+	{
+		FlagAnimal,
+		regexp.MustCompile(`\b(honey|gelatine?)\b`),
+		nil,
+	},
+	{
+		FlagFODMAP,
+		regexp.MustCompile(`\b(onions?|shallots?|leeks?|garlic|honey|agave|apples?|pears?|mangoes|mango|watermelon|cauliflower|mushrooms?|asparagus|artichokes?|wheat|rye|barley|chickpeas|kidney beans|baked beans|cashews?|pistachios?|milk|soft cheese|ricotta|cottage cheese|yogh?urt|inulin)\b`),
+		regexp.MustCompile(`\bgarlic[- ]infused (olive )?oil\b|\bspring onions? (greens|tops)\b|\bgreen (part|tops) of (the )?(leeks?|spring onions?)\b|\blactose[- ]free (milk|yogh?urt)\b|\b(almond|rice|oat|coconut) milk\b`),
+	},
+}
+
+// dietForbids says which flags rule a recipe out of each diet label.
+// low_carb and low_calorie aren't about ingredients, so aren't here.
+var dietForbids = map[string][]DietFlag{
+	"vegetarian":  {FlagMeat, FlagFish},
+	"vegan":       {FlagMeat, FlagFish, FlagDairy, FlagEgg, FlagAnimal},
+	"gluten_free": {FlagGluten},
+	"dairy_free":  {FlagDairy},
+	"egg_free":    {FlagEgg},
+	"nut_free":    {FlagNuts},
+	"low_fodmap":  {FlagFODMAP},
+}
+
+// IngredientFlags returns the dietary flags an ingredient name carries.
+func IngredientFlags(name string) []DietFlag {
+	name = strings.ToLower(name)
+	var flags []DietFlag
+	for _, rule := range dietaryRules {
+		text := name
+		if rule.unless != nil {
+			text = rule.unless.ReplaceAllString(text, "")
+		}
+		if rule.pattern.MatchString(text) {
+			flags = append(flags, rule.flag)
+		}
+	}
+	return flags
+}
+
+// LabelWarning is a label a recipe probably shouldn't have, and why.
+type LabelWarning struct {
+	Label      Label    `json:"label"`
+	Ingredient string   `json:"ingredient,omitempty"`
+	Flag       DietFlag `json:"flag,omitempty"`
+	Message    string   `json:"message"`
+}
+
+// CheckDietLabels finds diet labels r's ingredients contradict: a
+// diet:vegan recipe that lists butter. Sub-recipe lines are checked through
+// their expanded recipe (see SubRecipe.Recipe), by their own name otherwise.
+// It returns nil when there's nothing to report.
+func CheckDietLabels(r Recipe) []LabelWarning {
+	var warnings []LabelWarning
+	lines := flattenIngredients(r.Ingredients)
+	for _, l := range r.Labels {
+		if l.Type != "diet" {
+			continue
+		}
+		forbidden := dietForbids[l.Name]
+		for _, name := range lines {
+			for _, flag := range IngredientFlags(name) {
+				if !containsFlag(forbidden, flag) {
+					continue
+				}
+				warnings = append(warnings, LabelWarning{
+					Label:      Label{Type: l.Type, Name: l.Name},
+					Ingredient: name,
+					Flag:       flag,
+					Message:    fmt.Sprintf("labelled %s:%s, but %s is %s", l.Type, l.Name, name, flagDescription(flag)),
+				})
+				break
+			}
+		}
+	}
+	return warnings
+}
+
+// CheckLowCalorieLabel warns when r is labelled diet:low_calorie but est says
+// a serving is over the threshold. An estimate too patchy to judge by says
+// nothing.
+func CheckLowCalorieLabel(r Recipe, est NutritionEstimate) []LabelWarning {
+	if !HasLabel(r, "diet", "low_calorie") || est.LowCalorie == nil || *est.LowCalorie {
+		return nil
+	}
+	return []LabelWarning{{
+		Label: Label{Type: "diet", Name: "low_calorie"},
+		Message: fmt.Sprintf("labelled diet:low_calorie, but a serving is about %.0f kcal (over %.0f)",
+			est.PerServing.Calories, LowCalorieMaxPerServing),
+	}}
+}
+
+// HasLabel reports whether r carries the label typ:name.
+func HasLabel(r Recipe, typ, name string) bool {
+	for _, l := range r.Labels {
+		if l.Type == typ && l.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenIngredients lists ingredient names, descending into expanded
+// sub-recipes.
+func flattenIngredients(lines []RecipeIngredient) []string {
+	var names []string
+	for _, ri := range lines {
+		if ri.SubRecipe != nil && ri.SubRecipe.Recipe != nil {
+			names = append(names, flattenIngredients(ri.SubRecipe.Recipe.Ingredients)...)
+			continue
+		}
+		names = append(names, ri.Ingredient.Name)
+	}
+	return names
+}
+
+func containsFlag(flags []DietFlag, f DietFlag) bool {
+	for _, g := range flags {
+		if g == f {
+			return true
+		}
+	}
+	return false
+}
+
+func flagDescription(f DietFlag) string {
+	switch f {
+	case FlagMeat:
+		return "meat"
+	case FlagFish:
+		return "fish or seafood"
+	case FlagDairy:
+		return "dairy"
+	case FlagEgg:
+		return "egg"
+	case FlagGluten:
+		return "a source of gluten"
+	case FlagNuts:
+		return "a nut"
+	case FlagAnimal:
+		return "an animal product"
+	case FlagFODMAP:
+		return "high-FODMAP"
+	}
+	return string(f)
+}
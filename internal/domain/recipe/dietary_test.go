@@ -0,0 +1,109 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIngredientFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		want []DietFlag
+	}{
+		{"Butter", []DietFlag{FlagDairy}},
+		{"peanut butter", []DietFlag{FlagNuts}},
+		{"butter beans", nil},
+		{"coconut milk", nil},
+		{"whole milk", []DietFlag{FlagDairy, FlagFODMAP}},
+		{"chicken thighs", []DietFlag{FlagMeat}},
+		{"beef tomatoes", nil},
+		{"fish sauce", []DietFlag{FlagFish}},
+		{"oyster mushrooms", []DietFlag{FlagFODMAP}},
+		{"eggs", []DietFlag{FlagEgg}},
+		{"plain flour", []DietFlag{FlagGluten}},
+		{"gluten-free plain flour", nil},
+		{"rice noodles", nil},
+		{"garlic", []DietFlag{FlagFODMAP}},
+		{"garlic-infused olive oil", nil},
+		{"spring onion greens", nil},
+		{"honey", []DietFlag{FlagAnimal, FlagFODMAP}},
+		{"water chestnuts", nil},
+		{"nutmeg", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IngredientFlags(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IngredientFlags(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDietLabels(t *testing.T) {
+	line := func(name string) RecipeIngredient {
+		return RecipeIngredient{Ingredient: Ingredient{Name: name}}
+	}
+	r := Recipe{
+		Labels: []Label{
+			{Type: "diet", Name: "vegan"},
+			{Type: "diet", Name: "low_fodmap"},
+			{Type: "course", Name: "main"},
+		},
+		Ingredients: []RecipeIngredient{line("tofu"), line("butter"), line("garlic-infused oil")},
+	}
+
+	got := CheckDietLabels(r)
+	if len(got) != 1 {
+		t.Fatalf("CheckDietLabels() = %+v, want one warning", got)
+	}
+	w := got[0]
+	if w.Label.Name != "vegan" || w.Ingredient != "butter" || w.Flag != FlagDairy {
+		t.Errorf("warning = %+v, want vegan/butter/dairy", w)
+	}
+	if want := "labelled diet:vegan, but butter is dairy"; w.Message != want {
+		t.Errorf("Message = %q, want %q", w.Message, want)
+	}
+
+	r.Ingredients = []RecipeIngredient{line("tofu")}
+	if got := CheckDietLabels(r); got != nil {
+		t.Errorf("CheckDietLabels() = %+v, want nil", got)
+	}
+}
+
+func TestCheckDietLabels_SubRecipes(t *testing.T) {
+	stock := Recipe{Name: "Stock", Ingredients: []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "onion"}},
+		{Ingredient: Ingredient{Name: "carrot"}},
+	}}
+	r := Recipe{
+		Labels: []Label{{Type: "diet", Name: "low_fodmap"}},
+		Ingredients: []RecipeIngredient{
+			{Ingredient: Ingredient{Name: "Stock"}, SubRecipe: &SubRecipe{Recipe: &stock}},
+			{Ingredient: Ingredient{Name: "rice"}},
+		},
+	}
+
+	got := CheckDietLabels(r)
+	if len(got) != 1 || got[0].Ingredient != "onion" {
+		t.Errorf("CheckDietLabels() = %+v, want one warning for the stock's onion", got)
+	}
+}
+
+func TestCheckLowCalorieLabel(t *testing.T) {
+	r := Recipe{Labels: []Label{{Type: "diet", Name: "low_calorie"}}}
+	low, high := true, false
+
+	if got := CheckLowCalorieLabel(r, NutritionEstimate{PerServing: &Nutrients{Calories: 300}, LowCalorie: &low}); got != nil {
+		t.Errorf("under the threshold: got %+v, want nil", got)
+	}
+	if got := CheckLowCalorieLabel(r, NutritionEstimate{Coverage: 50}); got != nil {
+		t.Errorf("patchy estimate: got %+v, want nil", got)
+	}
+	got := CheckLowCalorieLabel(r, NutritionEstimate{PerServing: &Nutrients{Calories: 650}, LowCalorie: &high})
+	if len(got) != 1 || got[0].Label.Name != "low_calorie" {
+		t.Fatalf("over the threshold: got %+v, want one warning", got)
+	}
+	if got := CheckLowCalorieLabel(Recipe{}, NutritionEstimate{PerServing: &Nutrients{Calories: 650}, LowCalorie: &high}); got != nil {
+		t.Errorf("unlabelled: got %+v, want nil", got)
+	}
+}
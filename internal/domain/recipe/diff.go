@@ -0,0 +1,210 @@
+package recipe
+
+import (
+	"sort"
+	"strings"
+)
+
+// Diff is a structured comparison of two versions of a recipe: which scalar
+// fields changed, and which ingredients, steps, labels and equipment were
+// added, removed or changed. Used for revision history and for comparing a
+// variant with the recipe it was forked from.
+type Diff struct {
+	Fields             []FieldChange      `json:"fields"`
+	IngredientsAdded   []RecipeIngredient `json:"ingredientsAdded"`
+	IngredientsRemoved []RecipeIngredient `json:"ingredientsRemoved"`
+	IngredientsChanged []IngredientChange `json:"ingredientsChanged"`
+	StepsAdded         []StepChange       `json:"stepsAdded"`
+	StepsRemoved       []StepChange       `json:"stepsRemoved"`
+	StepsChanged       []StepChange       `json:"stepsChanged"`
+	LabelsAdded        []Label            `json:"labelsAdded"`
+	LabelsRemoved      []Label            `json:"labelsRemoved"`
+	EquipmentAdded     []string           `json:"equipmentAdded"`
+	EquipmentRemoved   []string           `json:"equipmentRemoved"`
+}
+
+// FieldChange is a change to one of the recipe's own fields.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// IngredientChange is the same ingredient (by name and component) with a
+// different quantity, unit or preparation.
+type IngredientChange struct {
+	From RecipeIngredient `json:"from"`
+	To   RecipeIngredient `json:"to"`
+}
+
+// StepChange describes a step by position. From is empty for an added step,
+// To for a removed one. The sections are given when the step has one.
+type StepChange struct {
+	Position    int    `json:"position"`
+	From        string `json:"from,omitempty"`
+	To          string `json:"to,omitempty"`
+	FromSection string `json:"fromSection,omitempty"`
+	ToSection   string `json:"toSection,omitempty"`
+}
+
+// IsEmpty reports whether the two versions were identical in every compared
+// respect.
+func (d Diff) IsEmpty() bool {
+	return len(d.Fields) == 0 &&
+		len(d.IngredientsAdded) == 0 && len(d.IngredientsRemoved) == 0 && len(d.IngredientsChanged) == 0 &&
+		len(d.StepsAdded) == 0 && len(d.StepsRemoved) == 0 && len(d.StepsChanged) == 0 &&
+		len(d.LabelsAdded) == 0 && len(d.LabelsRemoved) == 0 &&
+		len(d.EquipmentAdded) == 0 && len(d.EquipmentRemoved) == 0
+}
+
+// Compare returns what changed going from one version of a recipe to another.
+// Timestamps, identity and meal-plan state are ignored.
+func Compare(from, to Recipe) Diff {
+	d := Diff{
+		Fields:             []FieldChange{},
+		IngredientsAdded:   []RecipeIngredient{},
+		IngredientsRemoved: []RecipeIngredient{},
+		IngredientsChanged: []IngredientChange{},
+		StepsAdded:         []StepChange{},
+		StepsRemoved:       []StepChange{},
+		StepsChanged:       []StepChange{},
+		LabelsAdded:        []Label{},
+		LabelsRemoved:      []Label{},
+		EquipmentAdded:     []string{},
+		EquipmentRemoved:   []string{},
+	}
+
+	field := func(name string, a, b any) {
+		if a != b {
+			d.Fields = append(d.Fields, FieldChange{Field: name, From: a, To: b})
+		}
+	}
+	field("name", from.Name, to.Name)
+	field("description", from.Description, to.Description)
+	field("prepTime", from.PrepTime, to.PrepTime)
+	field("cookTime", from.CookTime, to.CookTime)
+	field("servings", from.Servings, to.Servings)
+	field("url", from.Url, to.Url)
+	field("mainPhoto", photoURL(from.MainPhoto), photoURL(to.MainPhoto))
+
+	compareIngredients(&d, from.Ingredients, to.Ingredients)
+	compareSteps(&d, from.Steps, to.Steps)
+	compareLabels(&d, from.Labels, to.Labels)
+	compareEquipment(&d, from.Equipment, to.Equipment)
+	return d
+}
+
+func photoURL(p *Photo) string {
+	if p == nil {
+		return ""
+	}
+	return p.URL
+}
+
+// ingredientKey identifies "the same ingredient" across versions: its name,
+// case-insensitively, within the same component. A recipe can list the same
+// ingredient more than once, so matching pairs lines up in order.
+func ingredientKey(ri RecipeIngredient) string {
+	return strings.ToLower(strings.TrimSpace(ri.Ingredient.Name)) + "\x00" + strings.ToLower(strings.TrimSpace(ri.Component))
+}
+
+func compareIngredients(d *Diff, from, to []RecipeIngredient) {
+	unmatched := map[string][]int{} // key -> indexes into from, in order
+	for i, ri := range from {
+		k := ingredientKey(ri)
+		unmatched[k] = append(unmatched[k], i)
+	}
+
+	matched := make([]bool, len(from))
+	for _, ri := range to {
+		k := ingredientKey(ri)
+		if len(unmatched[k]) == 0 {
+			d.IngredientsAdded = append(d.IngredientsAdded, ri)
+			continue
+		}
+		i := unmatched[k][0]
+		unmatched[k] = unmatched[k][1:]
+		matched[i] = true
+		old := from[i]
+		if old.Quantity != ri.Quantity ||
+			!strings.EqualFold(old.Unit.Name, ri.Unit.Name) ||
+			old.Preparation != ri.Preparation {
+			d.IngredientsChanged = append(d.IngredientsChanged, IngredientChange{From: old, To: ri})
+		}
+	}
+
+	for i, ri := range from {
+		if !matched[i] {
+			d.IngredientsRemoved = append(d.IngredientsRemoved, ri)
+		}
+	}
+}
+
+// compareSteps compares steps by position. Inserting a step mid-method shows
+// up as every later step changing, which is accurate enough to read and
+// doesn't pretend to know which step "moved".
+func compareSteps(d *Diff, from, to []Step) {
+	a, b := sortedSteps(from), sortedSteps(to)
+	for i := 0; i < max(len(a), len(b)); i++ {
+		switch {
+		case i >= len(a):
+			d.StepsAdded = append(d.StepsAdded, StepChange{Position: i + 1, To: b[i].Description, ToSection: b[i].Section})
+		case i >= len(b):
+			d.StepsRemoved = append(d.StepsRemoved, StepChange{Position: i + 1, From: a[i].Description, FromSection: a[i].Section})
+		case a[i].Description != b[i].Description || a[i].Section != b[i].Section:
+			d.StepsChanged = append(d.StepsChanged, StepChange{
+				Position:    i + 1,
+				From:        a[i].Description,
+				To:          b[i].Description,
+				FromSection: a[i].Section,
+				ToSection:   b[i].Section,
+			})
+		}
+	}
+}
+
+func sortedSteps(steps []Step) []Step {
+	out := make([]Step, len(steps))
+	copy(out, steps)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Order < out[j].Order })
+	return out
+}
+
+func compareLabels(d *Diff, from, to []Label) {
+	key := func(l Label) string { return l.Type + ":" + l.Name }
+	had := map[string]bool{}
+	for _, l := range from {
+		had[key(l)] = true
+	}
+	has := map[string]bool{}
+	for _, l := range to {
+		has[key(l)] = true
+		if !had[key(l)] {
+			d.LabelsAdded = append(d.LabelsAdded, Label{Type: l.Type, Name: l.Name})
+		}
+	}
+	for _, l := range from {
+		if !has[key(l)] {
+			d.LabelsRemoved = append(d.LabelsRemoved, Label{Type: l.Type, Name: l.Name})
+		}
+	}
+}
+
+func compareEquipment(d *Diff, from, to []string) {
+	had := map[string]bool{}
+	for _, e := range from {
+		had[e] = true
+	}
+	has := map[string]bool{}
+	for _, e := range to {
+		has[e] = true
+		if !had[e] {
+			d.EquipmentAdded = append(d.EquipmentAdded, e)
+		}
+	}
+	for _, e := range from {
+		if !has[e] {
+			d.EquipmentRemoved = append(d.EquipmentRemoved, e)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package recipe
+
+import "testing"
+
+func TestCompare_NoChanges(t *testing.T) {
+	r := Recipe{
+		Name:        "Dhal",
+		Ingredients: []RecipeIngredient{{Ingredient: Ingredient{Name: "lentils"}, Quantity: 250}},
+		Steps:       []Step{{Order: 1, Description: "Simmer."}},
+		Labels:      []Label{{Type: "cuisine", Name: "indian"}},
+	}
+	if d := Compare(r, r); !d.IsEmpty() {
+		t.Errorf("expected empty diff, got %+v", d)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	from := Recipe{
+		Name:     "Dhal",
+		CookTime: 30,
+		Ingredients: []RecipeIngredient{
+			{Ingredient: Ingredient{Name: "lentils"}, Unit: Unit{Name: "grams"}, Quantity: 250},
+			{Ingredient: Ingredient{Name: "onion"}, Quantity: 1},
+			{Ingredient: Ingredient{Name: "salt"}, Quantity: 1, Component: "tarka"},
+		},
+		Steps: []Step{
+			{Order: 1, Description: "Rinse the lentils."},
+			{Order: 2, Description: "Simmer for 30 minutes."},
+		},
+		Labels: []Label{{Type: "cuisine", Name: "indian"}, {Type: "diet", Name: "vegan"}},
+	}
+	to := Recipe{
+		Name:     "Dhal",
+		CookTime: 40,
+		Ingredients: []RecipeIngredient{
+			{Ingredient: Ingredient{Name: "Lentils"}, Unit: Unit{Name: "grams"}, Quantity: 300},
+			{Ingredient: Ingredient{Name: "salt"}, Quantity: 1, Component: "tarka"},
+			{Ingredient: Ingredient{Name: "garlic"}, Quantity: 2},
+		},
+		Steps: []Step{
+			{Order: 1, Description: "Rinse the lentils."},
+			{Order: 2, Description: "Simmer for 40 minutes."},
+			{Order: 3, Description: "Add the tarka."},
+		},
+		Labels: []Label{{Type: "cuisine", Name: "indian"}, {Type: "course", Name: "main"}},
+	}
+
+	d := Compare(from, to)
+
+	if len(d.Fields) != 1 || d.Fields[0].Field != "cookTime" || d.Fields[0].From != int32(30) || d.Fields[0].To != int32(40) {
+		t.Errorf("fields = %+v", d.Fields)
+	}
+	if len(d.IngredientsChanged) != 1 || d.IngredientsChanged[0].To.Quantity != 300 {
+		t.Errorf("ingredientsChanged = %+v", d.IngredientsChanged)
+	}
+	if len(d.IngredientsAdded) != 1 || d.IngredientsAdded[0].Ingredient.Name != "garlic" {
+		t.Errorf("ingredientsAdded = %+v", d.IngredientsAdded)
+	}
+	if len(d.IngredientsRemoved) != 1 || d.IngredientsRemoved[0].Ingredient.Name != "onion" {
+		t.Errorf("ingredientsRemoved = %+v", d.IngredientsRemoved)
+	}
+	if len(d.StepsChanged) != 1 || d.StepsChanged[0].Position != 2 {
+		t.Errorf("stepsChanged = %+v", d.StepsChanged)
+	}
+	if len(d.StepsAdded) != 1 || d.StepsAdded[0].To != "Add the tarka." {
+		t.Errorf("stepsAdded = %+v", d.StepsAdded)
+	}
+	if len(d.LabelsAdded) != 1 || d.LabelsAdded[0].Name != "main" {
+		t.Errorf("labelsAdded = %+v", d.LabelsAdded)
+	}
+	if len(d.LabelsRemoved) != 1 || d.LabelsRemoved[0].Name != "vegan" {
+		t.Errorf("labelsRemoved = %+v", d.LabelsRemoved)
+	}
+}
+
+func TestCompare_RepeatedIngredientPairsInOrder(t *testing.T) {
+	from := Recipe{Ingredients: []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "butter"}, Quantity: 50},
+		{Ingredient: Ingredient{Name: "butter"}, Quantity: 20},
+	}}
+	to := Recipe{Ingredients: []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "butter"}, Quantity: 50},
+	}}
+
+	d := Compare(from, to)
+	if len(d.IngredientsChanged) != 0 {
+		t.Errorf("ingredientsChanged = %+v", d.IngredientsChanged)
+	}
+	if len(d.IngredientsRemoved) != 1 || d.IngredientsRemoved[0].Quantity != 20 {
+		t.Errorf("ingredientsRemoved = %+v", d.IngredientsRemoved)
+	}
+}
+
+func TestCompare_StepSectionChange(t *testing.T) {
+	from := Recipe{Steps: []Step{{Order: 1, Description: "Knead."}}}
+	to := Recipe{Steps: []Step{{Order: 1, Description: "Knead.", Section: "Dough"}}}
+
+	d := Compare(from, to)
+	if len(d.StepsChanged) != 1 || d.StepsChanged[0].ToSection != "Dough" {
+		t.Errorf("stepsChanged = %+v", d.StepsChanged)
+	}
+}
+
+func TestCompare_Equipment(t *testing.T) {
+	d := Compare(Recipe{Equipment: []string{"wok"}}, Recipe{Equipment: []string{"slow_cooker"}})
+	if len(d.EquipmentAdded) != 1 || d.EquipmentAdded[0] != "slow_cooker" {
+		t.Errorf("EquipmentAdded = %v", d.EquipmentAdded)
+	}
+	if len(d.EquipmentRemoved) != 1 || d.EquipmentRemoved[0] != "wok" {
+		t.Errorf("EquipmentRemoved = %v", d.EquipmentRemoved)
+	}
+	if d.IsEmpty() {
+		t.Error("an equipment change should not be empty")
+	}
+}
@@ -0,0 +1,113 @@
+package recipe
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Equipment names are lowercase snake_case ("stand_mixer"), like label names.
+// Any name is allowed; the ones below are those SuggestEquipment can spot in a
+// method. migrations/00022_equipment.sql backfills existing recipes with the
+// same patterns in SQL — keep the two in sync.
+var equipmentPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"slow_cooker", regexp.MustCompile(`(?i)\b(slow[- ]?cooker|crock[- ]?pot)\b`)},
+	{"pressure_cooker", regexp.MustCompile(`(?i)\b(pressure[- ]?cooker|instant pot)\b`)},
+	{"stand_mixer", regexp.MustCompile(`(?i)\b(stand mixer|dough hook|paddle attachment)\b`)},
+	{"hand_mixer", regexp.MustCompile(`(?i)\b(hand mixer|electric whisk|electric beaters?)\b`)},
+	{"food_processor", regexp.MustCompile(`(?i)\bfood processor\b`)},
+	{"stick_blender", stickBlender},
+	{"blender", regexp.MustCompile(`(?i)\bblender\b`)},
+	{"wok", regexp.MustCompile(`(?i)\bwok\b`)},
+	{"sous_vide_circulator", regexp.MustCompile(`(?i)\b(sous[- ]vide|immersion circulator)\b`)},
+	{"pasta_machine", regexp.MustCompile(`(?i)\bpasta (machine|roller)\b`)},
+	{"air_fryer", regexp.MustCompile(`(?i)\bair[- ]?fryer\b`)},
+	{"deep_fryer", regexp.MustCompile(`(?i)\bdeep[- ](fat )?fryer\b`)},
+	{"dutch_oven", regexp.MustCompile(`(?i)\bdutch oven\b`)},
+	{"ice_cream_maker", regexp.MustCompile(`(?i)\bice[- ]cream (maker|machine)\b`)},
+	{"mandoline", regexp.MustCompile(`(?i)\bmandoline?\b`)},
+	{"pizza_stone", regexp.MustCompile(`(?i)\b(pizza stone|baking steel)\b`)},
+	{"thermometer", regexp.MustCompile(`(?i)\bthermometer\b`)},
+	{"smoker", regexp.MustCompile(`(?i)\bsmoker\b`)},
+}
+
+// A stick blender isn't a blender as well, so SuggestEquipment looks for
+// blenders only in what's left once stick blenders are taken out.
+var stickBlender = regexp.MustCompile(`(?i)\b(stick|hand|immersion) blender\b`)
+
+// EquipmentItem is a piece of kitchen equipment the household owns.
+type EquipmentItem struct {
+	Name    string    `json:"name"`
+	AddedAt time.Time `json:"addedAt,omitempty"`
+}
+
+// NormalizeEquipmentName puts a name into the stored form: "Stand mixer" and
+// "stand-mixer" both become "stand_mixer". Anything but letters and digits
+// separates words.
+func NormalizeEquipmentName(name string) string {
+	return snakeCase(name)
+}
+
+// snakeCase lowercases name and joins its words with underscores. Anything
+// but letters and digits separates words.
+func snakeCase(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_")
+}
+
+// NormalizeEquipment normalises each name, dropping blanks and repeats. nil
+// stays nil, "not supplied".
+func NormalizeEquipment(names []string) []string {
+	if names == nil {
+		return nil
+	}
+	out := []string{}
+	seen := map[string]bool{}
+	for _, n := range names {
+		n = NormalizeEquipmentName(n)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
+
+// SuggestEquipment finds the equipment a method calls for by name. It never
+// returns nil.
+func SuggestEquipment(steps []Step) []string {
+	found := []string{}
+	for _, e := range equipmentPatterns {
+		for _, s := range steps {
+			text := s.Description
+			if e.name == "blender" {
+				text = stickBlender.ReplaceAllString(text, "")
+			}
+			if !e.pattern.MatchString(text) {
+				continue
+			}
+			found = append(found, e.name)
+			break
+		}
+	}
+	return found
+}
+
+// FillEquipment normalises r's equipment, suggesting it from the steps when
+// none was supplied, and sorts it by name as it's read back. A recipe given
+// an empty list keeps it empty.
+func FillEquipment(r *Recipe) {
+	if r.Equipment == nil {
+		r.Equipment = SuggestEquipment(r.Steps)
+	} else {
+		r.Equipment = NormalizeEquipment(r.Equipment)
+	}
+	sort.Strings(r.Equipment)
+}
@@ -0,0 +1,69 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestEquipment(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []string
+		want  []string
+	}{
+		{"none", []string{"Chop the onions."}, []string{}},
+		{"slow cooker", []string{"Tip everything into the slow cooker and cook on low for 8 hours."}, []string{"slow_cooker"}},
+		{"several steps", []string{"Heat the wok until smoking.", "Knead with the dough hook for 5 minutes."}, []string{"stand_mixer", "wok"}},
+		{"stick blender isn't a blender", []string{"Blitz with a stick blender."}, []string{"stick_blender"}},
+		{"both blenders", []string{"Blitz with a stick blender, then pour into a blender."}, []string{"stick_blender", "blender"}},
+		{"pasta machine", []string{"Roll through the pasta machine to setting 6."}, []string{"pasta_machine"}},
+		{"sous vide", []string{"Cook sous-vide at 57C for 2 hours."}, []string{"sous_vide_circulator"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := make([]Step, len(tt.steps))
+			for i, d := range tt.steps {
+				steps[i] = Step{Order: int16(i + 1), Description: d}
+			}
+			if got := SuggestEquipment(steps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestEquipment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeEquipmentName(t *testing.T) {
+	for in, want := range map[string]string{
+		"Stand Mixer":        "stand_mixer",
+		" stand-mixer ":      "stand_mixer",
+		"sous_vide__stick":   "sous_vide_stick",
+		"Crème brûlée torch": "crème_brûlée_torch",
+		" - ":                "",
+	} {
+		if got := NormalizeEquipmentName(in); got != want {
+			t.Errorf("NormalizeEquipmentName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFillEquipment(t *testing.T) {
+	steps := []Step{{Order: 1, Description: "Fry in the wok."}}
+
+	r := Recipe{Steps: steps}
+	FillEquipment(&r)
+	if !reflect.DeepEqual(r.Equipment, []string{"wok"}) {
+		t.Errorf("nil equipment: got %v, want suggestion", r.Equipment)
+	}
+
+	r = Recipe{Steps: steps, Equipment: []string{}}
+	FillEquipment(&r)
+	if len(r.Equipment) != 0 {
+		t.Errorf("empty equipment should stay empty, got %v", r.Equipment)
+	}
+
+	r = Recipe{Steps: steps, Equipment: []string{"Pasta Machine", "wok", "pasta-machine"}}
+	FillEquipment(&r)
+	if !reflect.DeepEqual(r.Equipment, []string{"pasta_machine", "wok"}) {
+		t.Errorf("given equipment: got %v", r.Equipment)
+	}
+}
@@ -57,3 +57,226 @@ func (e ArchivedRecipeNotFoundError) Error() string {
 func (e ArchivedRecipeNotFoundError) Is(target error) bool {
 	return target == ErrArchivedRecipeNotFound
 }
+
+// ErrRevisionNotFound indicates a recipe has no revision with the requested number.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RevisionNotFoundError provides context about which revision was not found
+type RevisionNotFoundError struct {
+	RecipeID uuid.UUID
+	Revision int
+}
+
+func (e RevisionNotFoundError) Error() string {
+	return fmt.Sprintf("recipe %s has no revision %d", e.RecipeID, e.Revision)
+}
+
+func (e RevisionNotFoundError) Is(target error) bool {
+	return target == ErrRevisionNotFound
+}
+
+// ErrInvalidSubRecipe indicates a recipe line refers to a recipe it can't use:
+// one that doesn't exist, or one that would make the recipe contain itself.
+var ErrInvalidSubRecipe = errors.New("invalid sub-recipe")
+
+// SubRecipeError provides context about which sub-recipe reference was rejected
+type SubRecipeError struct {
+	RecipeID    uuid.UUID
+	SubRecipeID uuid.UUID
+	Reason      string
+}
+
+func (e SubRecipeError) Error() string {
+	return fmt.Sprintf("recipe %s cannot use %s as a sub-recipe: %s", e.RecipeID, e.SubRecipeID, e.Reason)
+}
+
+func (e SubRecipeError) Is(target error) bool {
+	return target == ErrInvalidSubRecipe
+}
+
+// ErrInvalidCook indicates a cook log entry with out-of-range values.
+var ErrInvalidCook = errors.New("invalid cook")
+
+// InvalidCookError says what was wrong with a cook log entry
+type InvalidCookError struct {
+	Reason string
+}
+
+func (e InvalidCookError) Error() string {
+	return fmt.Sprintf("invalid cook: %s", e.Reason)
+}
+
+func (e InvalidCookError) Is(target error) bool {
+	return target == ErrInvalidCook
+}
+
+// ErrAnnotationNotFound indicates a recipe has no annotation with the requested ID.
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// AnnotationNotFoundError provides context about which annotation was not found
+type AnnotationNotFoundError struct {
+	RecipeID     uuid.UUID
+	AnnotationID uuid.UUID
+}
+
+func (e AnnotationNotFoundError) Error() string {
+	return fmt.Sprintf("recipe %s has no annotation %s", e.RecipeID, e.AnnotationID)
+}
+
+func (e AnnotationNotFoundError) Is(target error) bool {
+	return target == ErrAnnotationNotFound
+}
+
+// ErrInvalidAnnotation indicates an empty note or one on a step that doesn't exist.
+var ErrInvalidAnnotation = errors.New("invalid annotation")
+
+// InvalidAnnotationError says what was wrong with an annotation
+type InvalidAnnotationError struct {
+	Reason string
+}
+
+func (e InvalidAnnotationError) Error() string {
+	return fmt.Sprintf("invalid annotation: %s", e.Reason)
+}
+
+func (e InvalidAnnotationError) Is(target error) bool {
+	return target == ErrInvalidAnnotation
+}
+
+// ErrPersonNotFound indicates no household member has the requested name.
+var ErrPersonNotFound = errors.New("person not found")
+
+// PersonNotFoundError provides context about which person was not found
+type PersonNotFoundError struct {
+	Name string
+}
+
+func (e PersonNotFoundError) Error() string {
+	return fmt.Sprintf("no household member named %q", e.Name)
+}
+
+func (e PersonNotFoundError) Is(target error) bool {
+	return target == ErrPersonNotFound
+}
+
+// ErrInvalidPerson indicates a household member with no name or an allergen
+// or diet the dietary rules don't know.
+var ErrInvalidPerson = errors.New("invalid person")
+
+// InvalidPersonError says what was wrong with a household member
+type InvalidPersonError struct {
+	Reason string
+}
+
+func (e InvalidPersonError) Error() string {
+	return fmt.Sprintf("invalid person: %s", e.Reason)
+}
+
+func (e InvalidPersonError) Is(target error) bool {
+	return target == ErrInvalidPerson
+}
+
+// ErrInvalidLabel indicates a label the taxonomy doesn't allow, or a change
+// to the taxonomy that would make it inconsistent.
+var ErrInvalidLabel = errors.New("invalid label")
+
+// InvalidLabelError says what was wrong with a label
+type InvalidLabelError struct {
+	Reason string
+}
+
+func (e InvalidLabelError) Error() string {
+	return fmt.Sprintf("invalid label: %s", e.Reason)
+}
+
+func (e InvalidLabelError) Is(target error) bool {
+	return target == ErrInvalidLabel
+}
+
+// ErrLabelNotFound indicates the taxonomy has no such label or label type.
+var ErrLabelNotFound = errors.New("label not found")
+
+// LabelNotFoundError provides context about which label was not found. An
+// empty Name means the type itself.
+type LabelNotFoundError struct {
+	Type string
+	Name string
+}
+
+func (e LabelNotFoundError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("no label type %q", e.Type)
+	}
+	return fmt.Sprintf("no label %s:%s", e.Type, e.Name)
+}
+
+func (e LabelNotFoundError) Is(target error) bool {
+	return target == ErrLabelNotFound
+}
+
+// ErrIngredientNotFound indicates no ingredient has the requested name.
+var ErrIngredientNotFound = errors.New("ingredient not found")
+
+// IngredientNotFoundError provides context about which ingredient was not
+// found
+type IngredientNotFoundError struct {
+	Name string
+}
+
+func (e IngredientNotFoundError) Error() string {
+	return fmt.Sprintf("no ingredient named %q", e.Name)
+}
+
+func (e IngredientNotFoundError) Is(target error) bool {
+	return target == ErrIngredientNotFound
+}
+
+// ErrInvalidIngredient indicates a catalogue change that would leave a name
+// meaning two ingredients.
+var ErrInvalidIngredient = errors.New("invalid ingredient")
+
+// InvalidIngredientError says what was wrong with an ingredient change
+type InvalidIngredientError struct {
+	Reason string
+}
+
+func (e InvalidIngredientError) Error() string {
+	return fmt.Sprintf("invalid ingredient: %s", e.Reason)
+}
+
+func (e InvalidIngredientError) Is(target error) bool {
+	return target == ErrInvalidIngredient
+}
+
+// ErrUnitNotFound indicates no unit has the requested name.
+var ErrUnitNotFound = errors.New("unit not found")
+
+// UnitNotFoundError provides context about which unit was not found
+type UnitNotFoundError struct {
+	Name string
+}
+
+func (e UnitNotFoundError) Error() string {
+	return fmt.Sprintf("no unit named %q", e.Name)
+}
+
+func (e UnitNotFoundError) Is(target error) bool {
+	return target == ErrUnitNotFound
+}
+
+// ErrInvalidUnit indicates a unit that can't be saved, or a change that would
+// leave a spelling meaning two units.
+var ErrInvalidUnit = errors.New("invalid unit")
+
+// InvalidUnitError says what was wrong with a unit change
+type InvalidUnitError struct {
+	Reason string
+}
+
+func (e InvalidUnitError) Error() string {
+	return fmt.Sprintf("invalid unit: %s", e.Reason)
+}
+
+func (e InvalidUnitError) Is(target error) bool {
+	return target == ErrInvalidUnit
+}
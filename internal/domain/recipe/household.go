@@ -0,0 +1,191 @@
+package recipe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Everyone stands for the whole household wherever the people eating are
+// named, so "for the four of us" needn't list four names.
+const Everyone = "everyone"
+
+// Person is someone we cook for. Allergens are dietary flags they can't eat
+// at all ("gluten" for a coeliac); Diets are diet label names they keep to
+// ("vegetarian", "low_fodmap"), each ruling out whatever that label does.
+type Person struct {
+	Name      string     `json:"name"`
+	Allergens []DietFlag `json:"allergens"`
+	Diets     []string   `json:"diets"`
+	CreatedAt time.Time  `json:"createdAt,omitempty"`
+	UpdatedAt time.Time  `json:"updatedAt,omitempty"`
+}
+
+// Avoids returns every flag p can't eat, from their allergens and diets,
+// sorted.
+func (p Person) Avoids() []DietFlag {
+	seen := map[DietFlag]bool{}
+	var flags []DietFlag
+	add := func(f DietFlag) {
+		if !seen[f] {
+			seen[f] = true
+			flags = append(flags, f)
+		}
+	}
+	for _, f := range p.Allergens {
+		add(f)
+	}
+	for _, d := range p.Diets {
+		for _, f := range dietForbids[d] {
+			add(f)
+		}
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i] < flags[j] })
+	return flags
+}
+
+// NormalizePerson trims p's name and puts its allergens and diets into the
+// stored form: lowercase, without blanks or repeats, sorted. Both lists are
+// never nil afterwards.
+func NormalizePerson(p *Person) {
+	p.Name = strings.TrimSpace(p.Name)
+
+	allergens := make([]string, len(p.Allergens))
+	for i, f := range p.Allergens {
+		allergens[i] = string(f)
+	}
+	p.Allergens = nil
+	for _, f := range normalizeNames(allergens) {
+		p.Allergens = append(p.Allergens, DietFlag(f))
+	}
+	if p.Allergens == nil {
+		p.Allergens = []DietFlag{}
+	}
+	p.Diets = normalizeNames(p.Diets)
+}
+
+// ValidatePerson checks a normalised person about to be saved.
+func ValidatePerson(p Person) error {
+	if p.Name == "" {
+		return InvalidPersonError{Reason: "name is required"}
+	}
+	if strings.EqualFold(p.Name, Everyone) {
+		return InvalidPersonError{Reason: fmt.Sprintf("%q is reserved for the whole household", Everyone)}
+	}
+	for _, f := range p.Allergens {
+		if !knownFlag(f) {
+			return InvalidPersonError{Reason: fmt.Sprintf("unknown allergen %q (one of %s)", f, strings.Join(DietFlagNames(), ", "))}
+		}
+	}
+	for _, d := range p.Diets {
+		if _, ok := dietForbids[d]; !ok {
+			return InvalidPersonError{Reason: fmt.Sprintf("unknown diet %q (one of %s)", d, strings.Join(DietNames(), ", "))}
+		}
+	}
+	return nil
+}
+
+// DietNames lists the diet labels a person can keep to: those whose
+// ingredients can be checked.
+func DietNames() []string {
+	names := make([]string, 0, len(dietForbids))
+	for d := range dietForbids {
+		names = append(names, d)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Conflict is an ingredient in a recipe that someone eating it can't have.
+type Conflict struct {
+	Person     string   `json:"person"`
+	Ingredient string   `json:"ingredient"`
+	Flag       DietFlag `json:"flag"`
+}
+
+// FindConflicts lists the ingredients in r each of people can't eat, one
+// conflict per person per ingredient. Sub-recipe lines are checked through
+// their expanded recipe, as in CheckDietLabels. It returns nil when everyone
+// can eat r.
+func FindConflicts(r Recipe, people []Person) []Conflict {
+	var conflicts []Conflict
+	lines := flattenIngredients(r.Ingredients)
+	for _, p := range people {
+		avoids := p.Avoids()
+		for _, name := range lines {
+			for _, flag := range IngredientFlags(name) {
+				if containsFlag(avoids, flag) {
+					conflicts = append(conflicts, Conflict{Person: p.Name, Ingredient: name, Flag: flag})
+					break
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// AvoidedIngredients picks out the ingredient names any of people can't eat,
+// so a listing can leave out the recipes that use them.
+func AvoidedIngredients(names []string, people []Person) []string {
+	var avoids []DietFlag
+	for _, p := range people {
+		avoids = append(avoids, p.Avoids()...)
+	}
+	if len(avoids) == 0 {
+		return nil
+	}
+	var avoided []string
+	for _, name := range names {
+		for _, flag := range IngredientFlags(name) {
+			if containsFlag(avoids, flag) {
+				avoided = append(avoided, name)
+				break
+			}
+		}
+	}
+	return avoided
+}
+
+// Eaters says who a listing is for: household members by name, or Everyone.
+// Recipes any of them can't eat are marked with their conflicts, or left out
+// altogether with Exclude.
+type Eaters struct {
+	Names   []string
+	Exclude bool
+}
+
+// normalizeNames lowercases and trims names, dropping blanks and repeats, and
+// sorts them. It never returns nil.
+func normalizeNames(names []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func knownFlag(f DietFlag) bool {
+	for _, rule := range dietaryRules {
+		if rule.flag == f {
+			return true
+		}
+	}
+	return false
+}
+
+// DietFlagNames lists the flags a person can be allergic to.
+func DietFlagNames() []string {
+	names := make([]string, len(dietaryRules))
+	for i, rule := range dietaryRules {
+		names[i] = string(rule.flag)
+	}
+	return names
+}
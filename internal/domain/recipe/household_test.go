@@ -0,0 +1,79 @@
+package recipe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPerson_Avoids(t *testing.T) {
+	p := Person{Allergens: []DietFlag{FlagNuts, FlagFish}, Diets: []string{"vegetarian"}}
+	want := []DietFlag{FlagFish, FlagMeat, FlagNuts}
+	if got := p.Avoids(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Avoids() = %v, want %v", got, want)
+	}
+}
+
+func TestValidatePerson(t *testing.T) {
+	tests := []struct {
+		name   string
+		person Person
+		ok     bool
+	}{
+		{"valid", Person{Name: "Sam", Allergens: []DietFlag{" Gluten "}, Diets: []string{"LOW_FODMAP"}}, true},
+		{"no name", Person{Name: "  "}, false},
+		{"reserved name", Person{Name: "Everyone"}, false},
+		{"unknown allergen", Person{Name: "Sam", Allergens: []DietFlag{"kryptonite"}}, false},
+		{"unknown diet", Person{Name: "Sam", Diets: []string{"carnivore"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.person
+			NormalizePerson(&p)
+			err := ValidatePerson(p)
+			if tt.ok && err != nil {
+				t.Errorf("ValidatePerson() = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidPerson) {
+				t.Errorf("ValidatePerson() = %v, want ErrInvalidPerson", err)
+			}
+		})
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	sauce := Recipe{Ingredients: []RecipeIngredient{{Ingredient: Ingredient{Name: "plain flour"}}}}
+	r := Recipe{Ingredients: []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "chicken thighs"}},
+		{Ingredient: Ingredient{Name: "White sauce"}, SubRecipe: &SubRecipe{Recipe: &sauce}},
+		{Ingredient: Ingredient{Name: "rice"}},
+	}}
+	people := []Person{
+		{Name: "Sam", Allergens: []DietFlag{FlagGluten}},
+		{Name: "Alex", Diets: []string{"vegetarian"}},
+		{Name: "Jo"},
+	}
+
+	want := []Conflict{
+		{Person: "Sam", Ingredient: "plain flour", Flag: FlagGluten},
+		{Person: "Alex", Ingredient: "chicken thighs", Flag: FlagMeat},
+	}
+	if got := FindConflicts(r, people); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindConflicts() = %+v, want %+v", got, want)
+	}
+	if got := FindConflicts(r, people[2:]); got != nil {
+		t.Errorf("FindConflicts() for Jo = %+v, want nil", got)
+	}
+}
+
+func TestAvoidedIngredients(t *testing.T) {
+	names := []string{"spaghetti", "gluten-free spaghetti", "butter", "olive oil"}
+	people := []Person{{Name: "Sam", Allergens: []DietFlag{FlagGluten}}}
+
+	if got, want := AvoidedIngredients(names, people), []string{"spaghetti"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvoidedIngredients() = %v, want %v", got, want)
+	}
+	if got := AvoidedIngredients(names, []Person{{Name: "Jo"}}); got != nil {
+		t.Errorf("AvoidedIngredients() with nothing to avoid = %v, want nil", got)
+	}
+}
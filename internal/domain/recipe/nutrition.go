@@ -0,0 +1,214 @@
+package recipe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Nutrients are amounts of each tracked nutrient: per 100 g in a
+// NutrientProfile, absolute in an estimate. Calories are kcal; the rest are
+// grams.
+type Nutrients struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+	Fibre    float64 `json:"fibre"`
+	Salt     float64 `json:"salt"`
+}
+
+func (n Nutrients) plus(m Nutrients) Nutrients {
+	return Nutrients{
+		Calories: n.Calories + m.Calories,
+		Protein:  n.Protein + m.Protein,
+		Fat:      n.Fat + m.Fat,
+		Carbs:    n.Carbs + m.Carbs,
+		Fibre:    n.Fibre + m.Fibre,
+		Salt:     n.Salt + m.Salt,
+	}
+}
+
+func (n Nutrients) scaled(f float64) Nutrients {
+	return Nutrients{
+		Calories: n.Calories * f,
+		Protein:  n.Protein * f,
+		Fat:      n.Fat * f,
+		Carbs:    n.Carbs * f,
+		Fibre:    n.Fibre * f,
+		Salt:     n.Salt * f,
+	}
+}
+
+// rounded gives calories to the nearest whole kcal and the rest to a tenth of
+// a gram; an estimate is no more precise than that.
+func (n Nutrients) rounded() Nutrients {
+	tenth := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return Nutrients{
+		Calories: math.Round(n.Calories),
+		Protein:  tenth(n.Protein),
+		Fat:      tenth(n.Fat),
+		Carbs:    tenth(n.Carbs),
+		Fibre:    tenth(n.Fibre),
+		Salt:     tenth(n.Salt),
+	}
+}
+
+// NutrientProfile is what 100 g of an ingredient contains, plus what it takes
+// to weigh a line measured some other way. Ingredient is the lowercase name.
+type NutrientProfile struct {
+	Ingredient string    `json:"ingredient"`
+	Per100g    Nutrients `json:"per100g"`
+	// Density is grams per millilitre, for lines measured by volume, and
+	// PieceWeight grams per whole one ("2 onions"). Zero means unknown.
+	Density     float64 `json:"density,omitempty"`
+	PieceWeight float64 `json:"pieceWeight,omitempty"`
+}
+
+// NutritionEstimate is a recipe's nutrition worked out from its ingredients.
+// Coverage is the percentage of the measured ingredient lines that could be
+// counted; Unmatched says why each of the others couldn't. Lines with no
+// quantity ("salt, to taste") are left out of both.
+type NutritionEstimate struct {
+	Total      Nutrients             `json:"total"`
+	PerServing *Nutrients            `json:"perServing,omitempty"`
+	Servings   int16                 `json:"servings,omitempty"`
+	Coverage   float64               `json:"coverage"`
+	Unmatched  []UnmatchedIngredient `json:"unmatched"`
+	// LowCalorie is whether a serving is within LowCalorieMaxPerServing. It
+	// is only given when the estimate is per serving and covers at least
+	// MinCoverageForLabels of the recipe.
+	LowCalorie *bool `json:"lowCalorie,omitempty"`
+}
+
+// UnmatchedIngredient is an ingredient line left out of an estimate.
+type UnmatchedIngredient struct {
+	Ingredient string `json:"ingredient"`
+	Reason     string `json:"reason"`
+}
+
+// Thresholds for labelling a recipe from its estimate.
+const (
+	LowCalorieMaxPerServing = 400.0
+	MinCoverageForLabels    = 80.0
+)
+
+// Grams per unit for measures of mass, and millilitres per unit for measures
+// of volume. Units are matched lowercase.
+var (
+	massUnits = map[string]float64{
+		"g": 1, "gram": 1, "grams": 1, "gr": 1,
+		"kg": 1000, "kilogram": 1000, "kilograms": 1000,
+		"mg": 0.001, "milligram": 0.001, "milligrams": 0.001,
+		"oz": 28.35, "ounce": 28.35, "ounces": 28.35,
+		"lb": 453.6, "lbs": 453.6, "pound": 453.6, "pounds": 453.6,
+		// A standard tin.
+		"tin": 400, "tins": 400, "can": 400, "cans": 400,
+	}
+	volumeUnits = map[string]float64{
+		"ml": 1, "millilitre": 1, "millilitres": 1, "milliliter": 1, "milliliters": 1,
+		"cl": 10, "centilitre": 10, "centilitres": 10,
+		"dl": 100, "decilitre": 100, "decilitres": 100,
+		"l": 1000, "litre": 1000, "litres": 1000, "liter": 1000, "liters": 1000,
+		"tsp": 5, "teaspoon": 5, "teaspoons": 5,
+		"tbsp": 15, "tablespoon": 15, "tablespoons": 15,
+		"cup": 240, "cups": 240,
+		"pint": 568, "pints": 568,
+		"fl oz": 28.4, "fluid ounce": 28.4, "fluid ounces": 28.4,
+		"pinch": 0.3, "pinches": 0.3,
+		"dash": 0.6, "dashes": 0.6,
+	}
+	// Counted units, weighed by PieceWeight. A clove is garlic's piece.
+	pieceUnits = map[string]bool{
+		"": true, "whole": true, "piece": true, "pieces": true, "each": true,
+		"clove": true, "cloves": true,
+	}
+)
+
+// Grams weighs an ingredient line using p. It reports an error saying what's
+// missing when the line's unit can't be turned into grams.
+func (p NutrientProfile) Grams(quantity float64, unit string) (float64, error) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if g, ok := massUnits[unit]; ok {
+		return quantity * g, nil
+	}
+	if ml, ok := volumeUnits[unit]; ok {
+		if p.Density <= 0 {
+			return 0, fmt.Errorf("no density to convert %s to grams", unit)
+		}
+		return quantity * ml * p.Density, nil
+	}
+	if pieceUnits[unit] {
+		if p.PieceWeight <= 0 {
+			return 0, fmt.Errorf("no weight for a whole one")
+		}
+		return quantity * p.PieceWeight, nil
+	}
+	return 0, fmt.Errorf("can't convert %q to grams", unit)
+}
+
+// LookupNutrientProfile finds the profile for an ingredient name, trying its
+// singular and plural forms. profiles is keyed by lowercase name.
+func LookupNutrientProfile(profiles map[string]NutrientProfile, name string) (NutrientProfile, bool) {
+	for _, v := range pluralVariants(strings.ToLower(strings.TrimSpace(name))) {
+		if p, ok := profiles[v]; ok {
+			return p, true
+		}
+	}
+	return NutrientProfile{}, false
+}
+
+// EstimateNutrition works out r's nutrition from profiles. Sub-recipe lines
+// count the expanded sub-recipe (see SubRecipe.Recipe); one that wasn't
+// expanded is unmatched.
+func EstimateNutrition(r Recipe, profiles map[string]NutrientProfile) NutritionEstimate {
+	est := NutritionEstimate{Unmatched: []UnmatchedIngredient{}}
+	var counted, measured int
+	var tally func(lines []RecipeIngredient)
+	tally = func(lines []RecipeIngredient) {
+		for _, ri := range lines {
+			if ri.SubRecipe != nil {
+				if ri.SubRecipe.Recipe != nil {
+					tally(ri.SubRecipe.Recipe.Ingredients)
+					continue
+				}
+				measured++
+				est.Unmatched = append(est.Unmatched, UnmatchedIngredient{Ingredient: ri.Ingredient.Name, Reason: "sub-recipe not available"})
+				continue
+			}
+			if ri.Quantity <= 0 {
+				continue
+			}
+			measured++
+			p, ok := LookupNutrientProfile(profiles, ri.Ingredient.Name)
+			if !ok {
+				est.Unmatched = append(est.Unmatched, UnmatchedIngredient{Ingredient: ri.Ingredient.Name, Reason: "no nutrition data"})
+				continue
+			}
+			grams, err := p.Grams(ri.Quantity, ri.Unit.Name)
+			if err != nil {
+				est.Unmatched = append(est.Unmatched, UnmatchedIngredient{Ingredient: ri.Ingredient.Name, Reason: err.Error()})
+				continue
+			}
+			est.Total = est.Total.plus(p.Per100g.scaled(grams / 100))
+			counted++
+		}
+	}
+	tally(r.Ingredients)
+
+	est.Coverage = 100
+	if measured > 0 {
+		est.Coverage = math.Round(float64(counted)/float64(measured)*1000) / 10
+	}
+	if r.Servings > 0 {
+		per := est.Total.scaled(1 / float64(r.Servings)).rounded()
+		est.PerServing = &per
+		est.Servings = r.Servings
+		if est.Coverage >= MinCoverageForLabels {
+			low := per.Calories <= LowCalorieMaxPerServing
+			est.LowCalorie = &low
+		}
+	}
+	est.Total = est.Total.rounded()
+	return est
+}
@@ -0,0 +1,112 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+)
+
+var testProfiles = map[string]NutrientProfile{
+	"red lentils": {Ingredient: "red lentils", Per100g: Nutrients{Calories: 318, Protein: 23.8, Carbs: 56.3, Fibre: 10.8}},
+	"olive oil":   {Ingredient: "olive oil", Per100g: Nutrients{Calories: 884, Fat: 100}, Density: 0.91},
+	"onion":       {Ingredient: "onion", Per100g: Nutrients{Calories: 40, Carbs: 9.3}, PieceWeight: 150},
+	"coriander":   {Ingredient: "coriander", Per100g: Nutrients{Calories: 23}},
+}
+
+func line(name string, quantity float64, unit string) RecipeIngredient {
+	return RecipeIngredient{Ingredient: Ingredient{Name: name}, Quantity: quantity, Unit: Unit{Name: unit}}
+}
+
+func TestNutrientProfileGrams(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  string
+		quantity float64
+		unit     string
+		want     float64
+		wantErr  string
+	}{
+		{"grams", "red lentils", 250, "g", 250, ""},
+		{"kilograms", "red lentils", 0.5, "kg", 500, ""},
+		{"volume by density", "olive oil", 2, "tbsp", 27.3, ""},
+		{"volume without density", "red lentils", 1, "cup", 0, "no density"},
+		{"whole ones", "onion", 2, "", 300, ""},
+		{"whole without weight", "red lentils", 2, "", 0, "no weight"},
+		{"unknown unit", "coriander", 1, "bunch", 0, "bunch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testProfiles[tt.profile].Grams(tt.quantity, tt.unit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Grams() error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Grams() error = %v", err)
+			}
+			if diff := got - tt.want; diff > 0.01 || diff < -0.01 {
+				t.Errorf("Grams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateNutrition(t *testing.T) {
+	r := Recipe{
+		Servings: 4,
+		Ingredients: []RecipeIngredient{
+			line("red lentils", 250, "g"),
+			line("Olive Oil", 1, "tbsp"),
+			line("onions", 1, ""),
+			line("coriander", 1, "bunch"),
+			line("salt", 0, ""),
+		},
+	}
+	est := EstimateNutrition(r, testProfiles)
+
+	// 795 + 120.7 + 60
+	if est.Total.Calories != 976 {
+		t.Errorf("total calories = %v, want 976", est.Total.Calories)
+	}
+	if est.PerServing == nil || est.PerServing.Calories != 244 {
+		t.Errorf("per serving = %+v, want 244 kcal", est.PerServing)
+	}
+	if est.Coverage != 75 {
+		t.Errorf("coverage = %v, want 75 (salt has no quantity and isn't counted)", est.Coverage)
+	}
+	if len(est.Unmatched) != 1 || est.Unmatched[0].Ingredient != "coriander" {
+		t.Errorf("unmatched = %+v", est.Unmatched)
+	}
+	if est.LowCalorie != nil {
+		t.Errorf("low coverage should give no lowCalorie verdict, got %v", *est.LowCalorie)
+	}
+}
+
+func TestEstimateNutrition_LowCalorieAndSubRecipes(t *testing.T) {
+	dressing := Recipe{Ingredients: []RecipeIngredient{line("olive oil", 2, "tbsp")}}
+	r := Recipe{
+		Servings: 2,
+		Ingredients: []RecipeIngredient{
+			line("onion", 2, ""),
+			{Ingredient: Ingredient{Name: "dressing"}, Quantity: 1, Unit: Unit{Name: "batches"}, SubRecipe: &SubRecipe{Recipe: &dressing}},
+		},
+	}
+	est := EstimateNutrition(r, testProfiles)
+
+	if est.Coverage != 100 {
+		t.Errorf("coverage = %v, want 100", est.Coverage)
+	}
+	// (120 + 241.3) / 2
+	if est.PerServing.Calories != 181 {
+		t.Errorf("per serving = %v kcal, want 181", est.PerServing.Calories)
+	}
+	if est.LowCalorie == nil || !*est.LowCalorie {
+		t.Errorf("expected lowCalorie = true, got %v", est.LowCalorie)
+	}
+
+	r.Ingredients[1].SubRecipe.Recipe = nil
+	if est := EstimateNutrition(r, testProfiles); est.Coverage != 50 {
+		t.Errorf("unexpanded sub-recipe: coverage = %v, want 50", est.Coverage)
+	}
+}
@@ -7,6 +7,7 @@ type Probe interface {
 	RecipeArchived(id string)
 	RecipeRestored(id string)
 	MealPlanChanged(action string, recipeID string)
+	CookLogged(recipeID string, rating int16)
 	RecipeSearched(resultCount int)
 	RecipeError(operation string, err error)
 }
@@ -2,6 +2,8 @@ package recipe
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,15 +26,62 @@ type Recipe struct {
 	Ingredients  []RecipeIngredient `json:"ingredients"`
 	Labels       []Label            `json:"labels"`
 	Photos       []Photo            `json:"photos"`
+
+	// Equipment is the kit the recipe needs beyond the everyday ("wok",
+	// "stand_mixer"). nil means "not supplied": saving then suggests it from
+	// the steps.
+	Equipment []string `json:"equipment"`
+
+	// PlannedFor is the day a meal-plan recipe is scheduled for, if any.
+	// MealPlanAddedAt is when it went onto the plan. Both are only populated
+	// on meal-plan listings; neither is part of the recipe proper.
+	PlannedFor      *time.Time `json:"plannedFor,omitempty"`
+	MealPlanAddedAt time.Time  `json:"-"`
+
+	// Parent is the recipe this one was forked from, if it is a variant, and
+	// Variants the live recipes forked from it. ChangesFromParent summarises
+	// how a variant differs from its parent. All three are only populated
+	// when reading a single recipe.
+	Parent            *RecipeRef  `json:"parent,omitempty"`
+	Variants          []RecipeRef `json:"variants,omitempty"`
+	ChangesFromParent *Diff       `json:"changesFromParent,omitempty"`
+
+	// CookStats sums up the recipe's cook log. Populated on reads; not part
+	// of the recipe proper.
+	CookStats *CookStats `json:"cookStats,omitempty"`
+
+	// Annotations are personal notes on the recipe and its steps, populated
+	// when reading a single recipe. They are edited on their own, never
+	// through the recipe.
+	Annotations []Annotation `json:"annotations,omitempty"`
+
+	// LabelWarnings are diet labels the recipe's ingredients contradict (see
+	// CheckDietLabels), set in the reply to a save. A warning never stops
+	// the save.
+	LabelWarnings []LabelWarning `json:"labelWarnings,omitempty"`
+
+	// Conflicts are the ingredients the people it's being listed for can't
+	// eat (see FindConflicts). Only set on listings for named eaters.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
 }
 
 // Step is a value object representing a step in a recipe.
 type Step struct {
 	Order       int16     `json:"order"`
 	Description string    `json:"description"`
+	Section     string    `json:"section"`
 	Photos      []Photo   `json:"photos"`
 	CreatedAt   time.Time `json:"createdAt,omitempty"`
 	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+
+	// Timers and Temperatures are parsed from Description (see
+	// FillStepDetails) unless supplied.
+	Timers       []Timer       `json:"timers"`
+	Temperatures []Temperature `json:"temperatures"`
+
+	// Ingredients are the ingredient lines this step uses, inferred from
+	// Description (see LinkStepIngredients) unless supplied.
+	Ingredients []StepIngredient `json:"ingredients"`
 }
 
 // Ingredient is a value object representing an ingredient.
@@ -42,11 +91,15 @@ type Ingredient struct {
 	UpdatedAt time.Time `json:"updatedAt,omitempty"`
 }
 
+// Unit is a unit of measure. On a recipe line only Name and Abbreviation are
+// set; Name is the line's unit text as written when it resolved to no unit.
 type Unit struct {
-	Name         string    `json:"name"`
-	Abbreviation string    `json:"abbreviation"`
-	CreatedAt    time.Time `json:"createdAt,omitempty"`
-	UpdatedAt    time.Time `json:"updatedAt,omitempty"`
+	Name         string        `json:"name"`
+	Abbreviation string        `json:"abbreviation"`
+	Aliases      []string      `json:"aliases,omitempty"`
+	Dimension    UnitDimension `json:"dimension,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt,omitempty"`
+	UpdatedAt    time.Time     `json:"updatedAt,omitempty"`
 }
 
 // RecipeIngredient ties an ingredient to a recipe with quantity and unit.
@@ -56,6 +109,31 @@ type RecipeIngredient struct {
 	Quantity    float64    `json:"quantity"`
 	Preparation string     `json:"preparation"`
 	Component   string     `json:"component"`
+
+	// SubRecipe, when set, makes this line a use of another recipe ("1 batch
+	// pizza dough") rather than an ingredient. Unit is then servings or
+	// batches of it, and Ingredient.Name carries the sub-recipe's name.
+	SubRecipe *SubRecipe `json:"subRecipe,omitempty"`
+}
+
+// Text renders the line as a shopping list or printed recipe would: "200 g
+// flour, sifted".
+func (ri RecipeIngredient) Text() string {
+	var parts []string
+	if ri.Quantity > 0 {
+		parts = append(parts, strconv.FormatFloat(ri.Quantity, 'f', -1, 64))
+	}
+	if ri.Unit.Abbreviation != "" {
+		parts = append(parts, ri.Unit.Abbreviation)
+	} else if ri.Unit.Name != "" {
+		parts = append(parts, ri.Unit.Name)
+	}
+	parts = append(parts, ri.Ingredient.Name)
+	text := strings.Join(parts, " ")
+	if ri.Preparation != "" {
+		text += ", " + ri.Preparation
+	}
+	return text
 }
 
 type Label struct {
@@ -67,9 +145,10 @@ type Label struct {
 
 // LabelSummary is a label plus its usage count, returned by the labels listing endpoint.
 type LabelSummary struct {
-	Type string `json:"type"`
-	Name string `json:"name"`
-	Uses int    `json:"uses"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Uses        int    `json:"uses"`
 }
 
 // Photo is a value object representing a photo attached to a recipe or step.
@@ -84,6 +163,17 @@ func (r Recipe) MarshalJSON() ([]byte, error) {
 	if r.MainPhoto != nil && r.MainPhoto.URL != "" {
 		mainPhoto = &r.MainPhoto.URL
 	}
+	// A plan date is a calendar day, not an instant — emit it as YYYY-MM-DD so
+	// clients in other timezones don't shift it.
+	var plannedFor *string
+	if r.PlannedFor != nil {
+		day := r.PlannedFor.Format(time.DateOnly)
+		plannedFor = &day
+	}
+	equipment := r.Equipment
+	if equipment == nil {
+		equipment = []string{}
+	}
 	return json.Marshal(&struct {
 		UUID         uuid.UUID          `json:"uuid,omitempty"`
 		Name         string             `json:"name"`
@@ -96,10 +186,22 @@ func (r Recipe) MarshalJSON() ([]byte, error) {
 		CreatedAt    time.Time          `json:"createdAt,omitempty"`
 		UpdatedAt    time.Time          `json:"updatedAt,omitempty"`
 		IsInMealPlan bool               `json:"isInMealPlan"`
+		PlannedFor   *string            `json:"plannedFor,omitempty"`
 		Steps        []Step             `json:"steps"`
 		Ingredients  []RecipeIngredient `json:"ingredients"`
 		Labels       []Label            `json:"labels"`
 		Photos       []Photo            `json:"photos"`
+		Equipment    []string           `json:"equipment"`
+
+		Parent            *RecipeRef  `json:"parent,omitempty"`
+		Variants          []RecipeRef `json:"variants,omitempty"`
+		ChangesFromParent *Diff       `json:"changesFromParent,omitempty"`
+
+		CookStats   *CookStats   `json:"cookStats,omitempty"`
+		Annotations []Annotation `json:"annotations,omitempty"`
+
+		LabelWarnings []LabelWarning `json:"labelWarnings,omitempty"`
+		Conflicts     []Conflict     `json:"conflicts,omitempty"`
 	}{
 		UUID:         r.UUID,
 		Name:         r.Name,
@@ -112,10 +214,22 @@ func (r Recipe) MarshalJSON() ([]byte, error) {
 		CreatedAt:    r.CreatedAt,
 		UpdatedAt:    r.UpdatedAt,
 		IsInMealPlan: r.IsInMealPlan,
+		PlannedFor:   plannedFor,
 		Steps:        r.Steps,
 		Ingredients:  r.Ingredients,
 		Labels:       r.Labels,
 		Photos:       r.Photos,
+		Equipment:    equipment,
+
+		Parent:            r.Parent,
+		Variants:          r.Variants,
+		ChangesFromParent: r.ChangesFromParent,
+
+		CookStats:   r.CookStats,
+		Annotations: r.Annotations,
+
+		LabelWarnings: r.LabelWarnings,
+		Conflicts:     r.Conflicts,
 	})
 }
 
@@ -128,6 +242,16 @@ func (r *Recipe) UnmarshalJSON(data []byte) error {
 	type recipeAlias Recipe
 	aux := &struct {
 		MainPhoto json.RawMessage `json:"mainPhoto"`
+		// plannedFor is output-only (set via the meal-plan endpoints) and is
+		// emitted as a bare date, so swallow it rather than decode it.
+		PlannedFor json.RawMessage `json:"plannedFor"`
+		// Likewise cookStats, which comes from the cook log, annotations,
+		// which have their own endpoints, and labelWarnings and conflicts,
+		// which are worked out on save and listing.
+		CookStats     json.RawMessage `json:"cookStats"`
+		Annotations   json.RawMessage `json:"annotations"`
+		LabelWarnings json.RawMessage `json:"labelWarnings"`
+		Conflicts     json.RawMessage `json:"conflicts"`
 		*recipeAlias
 	}{
 		recipeAlias: (*recipeAlias)(r),
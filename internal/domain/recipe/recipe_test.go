@@ -188,3 +188,19 @@ func TestRecipeMainPhotoUnmarshalShapes(t *testing.T) {
 		})
 	}
 }
+
+func TestRecipeIngredientText(t *testing.T) {
+	tests := []struct {
+		line RecipeIngredient
+		want string
+	}{
+		{RecipeIngredient{Ingredient: Ingredient{Name: "flour"}, Unit: Unit{Name: "gram", Abbreviation: "g"}, Quantity: 200, Preparation: "sifted"}, "200 g flour, sifted"},
+		{RecipeIngredient{Ingredient: Ingredient{Name: "garlic"}, Unit: Unit{Name: "cloves"}, Quantity: 2.5}, "2.5 cloves garlic"},
+		{RecipeIngredient{Ingredient: Ingredient{Name: "salt"}, Preparation: "to taste"}, "salt, to taste"},
+	}
+	for _, tt := range tests {
+		if got := tt.line.Text(); got != tt.want {
+			t.Errorf("Text() = %q, want %q", got, tt.want)
+		}
+	}
+}
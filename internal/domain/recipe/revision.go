@@ -0,0 +1,58 @@
+package recipe
+
+import (
+	"context"
+	"time"
+)
+
+// Revision sources: who made the change a revision records. Chat goes through
+// the MCP tools but is recorded separately, since "the assistant did it" is
+// the case people most want to find and undo.
+const (
+	RevisionSourceREST     = "rest"
+	RevisionSourceMCP      = "mcp"
+	RevisionSourceChat     = "chat"
+	RevisionSourceBaseline = "baseline" // state before history was kept
+	RevisionSourceUnknown  = "unknown"
+)
+
+// Revision is an immutable snapshot of a recipe as it stood after one create
+// or update. Recipe is only populated when a single revision is fetched;
+// listings leave it nil.
+type Revision struct {
+	Number    int       `json:"revision"`
+	Source    string    `json:"source"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Recipe    *Recipe   `json:"recipe,omitempty"`
+}
+
+type revisionSourceKey struct{}
+type revisionNoteKey struct{}
+
+// WithRevisionSource tags ctx with who is making a change, so the revision it
+// produces records it. Set once at the edge (REST handler, MCP server).
+func WithRevisionSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, revisionSourceKey{}, source)
+}
+
+// RevisionSourceFrom returns the source set by WithRevisionSource, or
+// RevisionSourceUnknown.
+func RevisionSourceFrom(ctx context.Context) string {
+	if s, ok := ctx.Value(revisionSourceKey{}).(string); ok && s != "" {
+		return s
+	}
+	return RevisionSourceUnknown
+}
+
+// WithRevisionNote attaches a note to the revision a change produces, e.g.
+// "reverted to revision 3".
+func WithRevisionNote(ctx context.Context, note string) context.Context {
+	return context.WithValue(ctx, revisionNoteKey{}, note)
+}
+
+// RevisionNoteFrom returns the note set by WithRevisionNote, if any.
+func RevisionNoteFrom(ctx context.Context) string {
+	note, _ := ctx.Value(revisionNoteKey{}).(string)
+	return note
+}
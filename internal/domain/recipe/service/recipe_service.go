@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
@@ -11,7 +15,10 @@ import (
 type RecipeService interface {
 	CreateRecipe(ctx context.Context, recipe recipe.Recipe) (*recipe.Recipe, error)
 	GetRecipe(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error)
-	ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string) ([]*recipe.Recipe, int, error)
+	// ListRecipes pages through live recipes. With ownedEquipmentOnly, recipes
+	// needing equipment the household doesn't own are left out. Named eaters
+	// leave out, or mark, recipes any of them can't eat.
+	ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string, ownedEquipmentOnly bool, eaters recipe.Eaters) ([]*recipe.Recipe, int, error)
 	UpdateRecipe(ctx context.Context, id uuid.UUID, recipe recipe.Recipe) (*recipe.Recipe, error)
 
 	// Archival methods
@@ -19,17 +26,104 @@ type RecipeService interface {
 	RestoreRecipe(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error)
 	ListArchivedRecipes(ctx context.Context, limit, offset int) ([]*recipe.Recipe, int, error)
 
-	// Meal planning methods
-	AddToMealPlan(ctx context.Context, recipeID uuid.UUID) error
+	// Meal planning methods. plannedFor optionally schedules the recipe for a
+	// given day; nil leaves any existing date alone. Listing the plan for
+	// named eaters marks what each of them can't eat; nothing is left out.
+	AddToMealPlan(ctx context.Context, recipeID uuid.UUID, plannedFor *time.Time) error
 	RemoveFromMealPlan(ctx context.Context, recipeID uuid.UUID) error
-	ListMealPlanRecipes(ctx context.Context) ([]*recipe.Recipe, error)
+	ListMealPlanRecipes(ctx context.Context, eaters []string) ([]*recipe.Recipe, error)
+
+	// Revision history. Every create and update records an immutable
+	// snapshot; DiffRevisions compares two of them (to == 0 means the recipe as
+	// it stands now) and RevertToRevision restores one as a new revision, so a
+	// revert can itself be undone.
+	ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]recipe.Revision, error)
+	GetRevision(ctx context.Context, recipeID uuid.UUID, number int) (*recipe.Revision, error)
+	DiffRevisions(ctx context.Context, recipeID uuid.UUID, from, to int) (recipe.Diff, error)
+	RevertToRevision(ctx context.Context, recipeID uuid.UUID, number int) (*recipe.Recipe, error)
+
+	// Cook log. LogCook records that recipeID was cooked; a zero CookedOn
+	// means today. ListCooks returns the log, most recent first.
+	LogCook(ctx context.Context, recipeID uuid.UUID, cook recipe.Cook) (*recipe.Cook, error)
+	ListCooks(ctx context.Context, recipeID uuid.UUID) ([]recipe.Cook, error)
+
+	// Annotations: personal notes on a recipe or one of its steps, added and
+	// edited without touching the recipe itself.
+	AddAnnotation(ctx context.Context, recipeID uuid.UUID, a recipe.Annotation) (*recipe.Annotation, error)
+	UpdateAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID, note string) (*recipe.Annotation, error)
+	DeleteAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID) error
+
+	// Household equipment: the kit we own, which ListRecipes can filter by.
+	// Names are normalised to snake_case; adding one already owned, or
+	// removing one that isn't, is a no-op.
+	ListEquipment(ctx context.Context) ([]recipe.EquipmentItem, error)
+	AddEquipment(ctx context.Context, name string) error
+	RemoveEquipment(ctx context.Context, name string) error
+
+	// Nutrition estimates recipeID's nutrition, in total and per serving,
+	// from the nutrient table, counting sub-recipes in full.
+	Nutrition(ctx context.Context, recipeID uuid.UUID) (*recipe.NutritionEstimate, error)
+
+	// CheckLabels finds diet labels r's ingredients contradict, counting
+	// sub-recipes in full, and a low_calorie label its nutrition estimate
+	// doesn't bear out. Create, update and fork report these on the saved
+	// recipe as LabelWarnings.
+	CheckLabels(ctx context.Context, r recipe.Recipe) ([]recipe.LabelWarning, error)
+
+	// Household members: who we cook for and what they can't eat. Names are
+	// matched case-insensitively; saving a name already there replaces that
+	// person.
+	ListPeople(ctx context.Context) ([]recipe.Person, error)
+	SavePerson(ctx context.Context, p recipe.Person) (*recipe.Person, error)
+	DeletePerson(ctx context.Context, name string) error
+
+	// ForkRecipe creates a variant of parentID. Fields set in changes
+	// override the parent's; the rest are copied (see recipe.Fork).
+	ForkRecipe(ctx context.Context, parentID uuid.UUID, changes recipe.Recipe) (*recipe.Recipe, error)
+
+	// ExpandSubRecipes fills in, recursively, the recipe behind each of r's
+	// sub-recipe lines, scaled to the amount the line calls for.
+	ExpandSubRecipes(ctx context.Context, r *recipe.Recipe) error
 
 	// Label browsing
 	ListLabels(ctx context.Context) ([]recipe.LabelSummary, error)
 
+	// Label taxonomy: the label types and the names allowed under each.
+	// Recipe saves resolve labels through it, following aliases, and reject
+	// any it doesn't allow. RenameLabel keeps the old name as an alias;
+	// MergeLabels moves from's recipes onto into, which takes from's name and
+	// aliases as its own aliases. Both work within a single type, and report
+	// the recipes affected; with dryRun they change nothing.
+	Taxonomy(ctx context.Context) (recipe.Taxonomy, error)
+	SaveLabelType(ctx context.Context, lt recipe.LabelType) (*recipe.LabelType, error)
+	SaveLabel(ctx context.Context, d recipe.LabelDefinition) (*recipe.LabelDefinition, error)
+	RenameLabel(ctx context.Context, label recipe.Label, to string, dryRun bool) (*recipe.LabelChange, error)
+	MergeLabels(ctx context.Context, from, into recipe.Label, dryRun bool) (*recipe.LabelChange, error)
+
 	// Lookup methods
-	ListUnits(ctx context.Context) ([]recipe.Unit, error)
 	ListIngredients(ctx context.Context) ([]recipe.Ingredient, error)
+
+	// Unit registry: the canonical units with their aliases and dimension.
+	// Recipe lines resolve their unit text through it; text that resolves to
+	// nothing is kept on the line and queued for review. Saving a unit gives
+	// it the queued lines its names now match. Merging moves from's lines
+	// onto into, which takes from's names as aliases; only units of the same
+	// dimension merge. No spelling may end up meaning two units.
+	ListUnits(ctx context.Context) (recipe.UnitRegistry, error)
+	SaveUnit(ctx context.Context, u recipe.Unit) (*recipe.Unit, error)
+	MergeUnits(ctx context.Context, from, into string) (*recipe.Unit, error)
+	UnitReviews(ctx context.Context) ([]recipe.UnitReview, error)
+	DismissUnitReview(ctx context.Context, name string) error
+
+	// Ingredient catalogue: every ingredient with its aliases and usage.
+	// Ingredients are addressed by their exact stored name, so "Salt" and
+	// "salt" can be merged. Renaming keeps the old name as an alias; merging
+	// moves from's recipe lines and pantry entry onto into, which takes
+	// from's names as aliases. No name may end up meaning two ingredients.
+	IngredientCatalogue(ctx context.Context) (recipe.IngredientCatalogue, error)
+	SetIngredientAliases(ctx context.Context, name string, aliases []string) (*recipe.IngredientSummary, error)
+	RenameIngredient(ctx context.Context, name, to string) (*recipe.IngredientSummary, error)
+	MergeIngredients(ctx context.Context, from, into string) (*recipe.IngredientSummary, error)
 }
 
 type recipeService struct {
@@ -48,6 +142,18 @@ func (s *recipeService) CreateRecipe(ctx context.Context, r recipe.Recipe) (*rec
 	if r.UUID == uuid.Nil {
 		r.UUID = uuid.New()
 	}
+	// Variants are made with ForkRecipe, which checks the parent exists; a
+	// parent echoed back in a create body is ignored.
+	r.Parent = nil
+	recipe.NormalizeStepSections(r.Steps)
+	recipe.FillStepDetails(r.Steps)
+	if err := s.linkStepIngredients(ctx, &r); err != nil {
+		return nil, err
+	}
+	recipe.FillEquipment(&r)
+	if err := s.resolveLabels(ctx, &r); err != nil {
+		return nil, err
+	}
 
 	result, err := s.repo.SaveRecipe(ctx, r)
 	if err != nil {
@@ -55,20 +161,54 @@ func (s *recipeService) CreateRecipe(ctx context.Context, r recipe.Recipe) (*rec
 		return nil, err
 	}
 	s.probe.RecipeCreated(result.Name)
+	s.attachLabelWarnings(ctx, result)
 	return result, nil
 }
 
 func (s *recipeService) GetRecipe(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
-	return s.repo.GetRecipeByID(ctx, id)
+	r, err := s.repo.GetRecipeByID(ctx, id)
+	if err != nil || r == nil || r.Parent == nil {
+		return r, err
+	}
+
+	parent, err := s.repo.GetRecipeByID(ctx, r.Parent.UUID)
+	if err != nil {
+		return nil, err
+	}
+	changes := recipe.Compare(*parent, *r)
+	r.ChangesFromParent = &changes
+	return r, nil
 }
 
-func (s *recipeService) ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string) ([]*recipe.Recipe, int, error) {
-	recipes, total, err := s.repo.ListRecipes(ctx, limit, offset, search, labels, sort)
+func (s *recipeService) ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string, ownedEquipmentOnly bool, eaters recipe.Eaters) ([]*recipe.Recipe, int, error) {
+	people, err := s.resolvePeople(ctx, eaters.Names)
+	if err != nil {
+		return nil, 0, err
+	}
+	var avoid []string
+	if eaters.Exclude && len(people) > 0 {
+		ingredients, err := s.repo.ListIngredients(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		names := make([]string, len(ingredients))
+		for i, ing := range ingredients {
+			names[i] = ing.Name
+		}
+		avoid = recipe.AvoidedIngredients(names, people)
+	}
+
+	recipes, total, err := s.repo.ListRecipes(ctx, limit, offset, search, labels, sort, ownedEquipmentOnly, avoid)
 	if err != nil {
 		s.probe.RecipeError("search", err)
 		return nil, 0, err
 	}
 	s.probe.RecipeSearched(total)
+	if !eaters.Exclude {
+		if err := s.markConflicts(ctx, recipes, people); err != nil {
+			return nil, 0, err
+		}
+	}
 	return recipes, total, nil
 }
 
@@ -81,12 +221,31 @@ func (s *recipeService) UpdateRecipe(ctx context.Context, id uuid.UUID, r recipe
 		return nil, nil
 	}
 
+	recipe.NormalizeStepSections(r.Steps)
+	// The app doesn't send sections yet; don't let its edits erase them.
+	recipe.KeepStepSections(r.Steps, existingRecipe.Steps)
+	recipe.FillStepDetails(r.Steps)
+	// Links given are to the lines as they were stored.
+	recipe.DropMovedStepLinks(r.Steps, existingRecipe.Ingredients, r.Ingredients)
+	if err := s.linkStepIngredients(ctx, &r); err != nil {
+		return nil, err
+	}
+	// Equipment left out (the app never sends it) keeps what's stored rather
+	// than being suggested over the top of it; suggestions are for new recipes.
+	if r.Equipment == nil {
+		r.Equipment = append([]string{}, existingRecipe.Equipment...)
+	}
+	recipe.FillEquipment(&r)
+	if err := s.resolveLabels(ctx, &r); err != nil {
+		return nil, err
+	}
 	result, err := s.repo.UpdateRecipe(ctx, id, r)
 	if err != nil {
 		s.probe.RecipeError("update", err)
 		return nil, err
 	}
 	s.probe.RecipeUpdated(result.Name)
+	s.attachLabelWarnings(ctx, result)
 	return result, nil
 }
 
@@ -121,8 +280,8 @@ func (s *recipeService) ListArchivedRecipes(ctx context.Context, limit, offset i
 	return s.repo.ListArchivedRecipes(ctx, limit, offset)
 }
 
-func (s *recipeService) AddToMealPlan(ctx context.Context, recipeID uuid.UUID) error {
-	if err := s.repo.AddToMealPlan(ctx, recipeID); err != nil {
+func (s *recipeService) AddToMealPlan(ctx context.Context, recipeID uuid.UUID, plannedFor *time.Time) error {
+	if err := s.repo.AddToMealPlan(ctx, recipeID, plannedFor); err != nil {
 		s.probe.RecipeError("meal_plan_add", err)
 		return err
 	}
@@ -139,18 +298,701 @@ func (s *recipeService) RemoveFromMealPlan(ctx context.Context, recipeID uuid.UU
 	return nil
 }
 
-func (s *recipeService) ListMealPlanRecipes(ctx context.Context) ([]*recipe.Recipe, error) {
-	return s.repo.ListMealPlanRecipes(ctx)
+func (s *recipeService) ListMealPlanRecipes(ctx context.Context, eaters []string) ([]*recipe.Recipe, error) {
+	people, err := s.resolvePeople(ctx, eaters)
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := s.repo.ListMealPlanRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markConflicts(ctx, recipes, people); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+func (s *recipeService) ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]recipe.Revision, error) {
+	// Resolve the recipe first so an unknown ID is a not-found rather than an
+	// empty history.
+	if _, err := s.repo.GetRecipeByID(ctx, recipeID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRevisions(ctx, recipeID)
+}
+
+func (s *recipeService) LogCook(ctx context.Context, recipeID uuid.UUID, cook recipe.Cook) (*recipe.Cook, error) {
+	if err := cook.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetRecipeByID(ctx, recipeID); err != nil {
+		return nil, err
+	}
+
+	cook.UUID = uuid.New()
+	cook.RecipeID = recipeID
+	if cook.CookedOn.IsZero() {
+		now := time.Now()
+		cook.CookedOn = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	logged, err := s.repo.LogCook(ctx, cook)
+	if err != nil {
+		s.probe.RecipeError("log_cook", err)
+		return nil, err
+	}
+	s.probe.CookLogged(recipeID.String(), logged.Rating)
+	return logged, nil
+}
+
+func (s *recipeService) ListCooks(ctx context.Context, recipeID uuid.UUID) ([]recipe.Cook, error) {
+	// As with revisions, an unknown recipe is a not-found, not an empty log.
+	if _, err := s.repo.GetRecipeByID(ctx, recipeID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListCooks(ctx, recipeID)
+}
+
+func (s *recipeService) AddAnnotation(ctx context.Context, recipeID uuid.UUID, a recipe.Annotation) (*recipe.Annotation, error) {
+	r, err := s.repo.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	a.Note = strings.TrimSpace(a.Note)
+	if err := recipe.ValidateAnnotation(*r, a); err != nil {
+		return nil, err
+	}
+	a.UUID = uuid.New()
+	return s.repo.AddAnnotation(ctx, recipeID, a)
+}
+
+func (s *recipeService) UpdateAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID, note string) (*recipe.Annotation, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, recipe.InvalidAnnotationError{Reason: "note must not be empty"}
+	}
+	return s.repo.UpdateAnnotation(ctx, recipeID, annotationID, note)
+}
+
+func (s *recipeService) DeleteAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID) error {
+	return s.repo.DeleteAnnotation(ctx, recipeID, annotationID)
+}
+
+func (s *recipeService) GetRevision(ctx context.Context, recipeID uuid.UUID, number int) (*recipe.Revision, error) {
+	return s.repo.GetRevision(ctx, recipeID, number)
+}
+
+func (s *recipeService) DiffRevisions(ctx context.Context, recipeID uuid.UUID, from, to int) (recipe.Diff, error) {
+	older, err := s.repo.GetRevision(ctx, recipeID, from)
+	if err != nil {
+		return recipe.Diff{}, err
+	}
+
+	var newer *recipe.Recipe
+	if to == 0 {
+		newer, err = s.repo.GetRecipeByID(ctx, recipeID)
+	} else {
+		var rev *recipe.Revision
+		rev, err = s.repo.GetRevision(ctx, recipeID, to)
+		if rev != nil {
+			newer = rev.Recipe
+		}
+	}
+	if err != nil {
+		return recipe.Diff{}, err
+	}
+	return recipe.Compare(*older.Recipe, *newer), nil
+}
+
+func (s *recipeService) RevertToRevision(ctx context.Context, recipeID uuid.UUID, number int) (*recipe.Recipe, error) {
+	rev, err := s.repo.GetRevision(ctx, recipeID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions from before steps were linked to lines have none; infer them
+	// as a save would.
+	if err := s.linkStepIngredients(ctx, rev.Recipe); err != nil {
+		return nil, err
+	}
+	// Revisions from before equipment was recorded have none; suggest it.
+	recipe.FillEquipment(rev.Recipe)
+	// Its labels may since have been renamed or merged; their old names
+	// resolve as aliases.
+	if err := s.resolveLabels(ctx, rev.Recipe); err != nil {
+		return nil, err
+	}
+	ctx = recipe.WithRevisionNote(ctx, fmt.Sprintf("reverted to revision %d", number))
+	result, err := s.repo.UpdateRecipe(ctx, recipeID, *rev.Recipe)
+	if err != nil {
+		s.probe.RecipeError("revert", err)
+		return nil, err
+	}
+	s.probe.RecipeUpdated(result.Name)
+	return result, nil
+}
+
+func (s *recipeService) ForkRecipe(ctx context.Context, parentID uuid.UUID, changes recipe.Recipe) (*recipe.Recipe, error) {
+	parent, err := s.repo.GetRecipeByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := recipe.Fork(*parent, changes)
+	variant.UUID = uuid.New()
+	recipe.NormalizeStepSections(variant.Steps)
+	recipe.FillStepDetails(variant.Steps)
+	if err := s.linkStepIngredients(ctx, &variant); err != nil {
+		return nil, err
+	}
+	recipe.FillEquipment(&variant)
+	if err := s.resolveLabels(ctx, &variant); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.SaveRecipe(ctx, variant)
+	if err != nil {
+		s.probe.RecipeError("fork", err)
+		return nil, err
+	}
+	s.probe.RecipeCreated(result.Name)
+	saved, err := s.GetRecipe(ctx, result.UUID)
+	if err != nil {
+		return nil, err
+	}
+	s.attachLabelWarnings(ctx, saved)
+	return saved, nil
+}
+
+func (s *recipeService) ExpandSubRecipes(ctx context.Context, r *recipe.Recipe) error {
+	return s.expandSubRecipes(ctx, r, map[uuid.UUID]bool{r.UUID: true})
+}
+
+// expandSubRecipes walks r's sub-recipe lines depth first. path holds the
+// recipes being expanded above this one; saving rejects cycles, but the guard
+// keeps a bad row from recursing forever.
+func (s *recipeService) expandSubRecipes(ctx context.Context, r *recipe.Recipe, path map[uuid.UUID]bool) error {
+	for i := range r.Ingredients {
+		line := &r.Ingredients[i]
+		if line.SubRecipe == nil || path[line.SubRecipe.UUID] {
+			continue
+		}
+		sub, err := s.repo.GetRecipeByID(ctx, line.SubRecipe.UUID)
+		if errors.Is(err, recipe.ErrRecipeNotFound) {
+			continue // archived since; the line still names it
+		}
+		if err != nil {
+			return err
+		}
+
+		// Scale before descending so nested lines are scaled too.
+		scaled := sub.Scaled(line.Batches(sub.Servings))
+		path[sub.UUID] = true
+		err = s.expandSubRecipes(ctx, &scaled, path)
+		delete(path, sub.UUID)
+		if err != nil {
+			return err
+		}
+		line.SubRecipe.Recipe = &scaled
+	}
+	return nil
 }
 
 func (s *recipeService) ListLabels(ctx context.Context) ([]recipe.LabelSummary, error) {
 	return s.repo.ListLabels(ctx)
 }
 
-func (s *recipeService) ListUnits(ctx context.Context) ([]recipe.Unit, error) {
+func (s *recipeService) ListIngredients(ctx context.Context) ([]recipe.Ingredient, error) {
+	return s.repo.ListIngredients(ctx)
+}
+
+func (s *recipeService) ListEquipment(ctx context.Context) ([]recipe.EquipmentItem, error) {
+	return s.repo.ListHouseholdEquipment(ctx)
+}
+
+func (s *recipeService) AddEquipment(ctx context.Context, name string) error {
+	name = recipe.NormalizeEquipmentName(name)
+	if name == "" {
+		return fmt.Errorf("equipment name is required")
+	}
+	return s.repo.AddHouseholdEquipment(ctx, name)
+}
+
+func (s *recipeService) RemoveEquipment(ctx context.Context, name string) error {
+	name = recipe.NormalizeEquipmentName(name)
+	if name == "" {
+		return fmt.Errorf("equipment name is required")
+	}
+	return s.repo.RemoveHouseholdEquipment(ctx, name)
+}
+
+func (s *recipeService) Nutrition(ctx context.Context, recipeID uuid.UUID) (*recipe.NutritionEstimate, error) {
+	r, err := s.repo.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ExpandSubRecipes(ctx, r); err != nil {
+		return nil, err
+	}
+	profiles, err := s.repo.ListNutrientProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	est := recipe.EstimateNutrition(*r, profiles)
+	return &est, nil
+}
+
+func (s *recipeService) CheckLabels(ctx context.Context, r recipe.Recipe) ([]recipe.LabelWarning, error) {
+	r, err := s.expandedCopy(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := recipe.CheckDietLabels(r)
+	if recipe.HasLabel(r, "diet", "low_calorie") {
+		profiles, err := s.repo.ListNutrientProfiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, recipe.CheckLowCalorieLabel(r, recipe.EstimateNutrition(r, profiles))...)
+	}
+	return warnings, nil
+}
+
+// expandedCopy returns r with its sub-recipes expanded, leaving r's own
+// sub-recipe lines as they were.
+func (s *recipeService) expandedCopy(ctx context.Context, r recipe.Recipe) (recipe.Recipe, error) {
+	r.Ingredients = append([]recipe.RecipeIngredient(nil), r.Ingredients...)
+	for i, line := range r.Ingredients {
+		if line.SubRecipe != nil {
+			sub := *line.SubRecipe
+			r.Ingredients[i].SubRecipe = &sub
+		}
+	}
+	if err := s.ExpandSubRecipes(ctx, &r); err != nil {
+		return recipe.Recipe{}, err
+	}
+	return r, nil
+}
+
+// attachLabelWarnings sets r's LabelWarnings after a save. The recipe is
+// already saved, so failing to check it is only reported to the probe.
+func (s *recipeService) attachLabelWarnings(ctx context.Context, r *recipe.Recipe) {
+	warnings, err := s.CheckLabels(ctx, *r)
+	if err != nil {
+		s.probe.RecipeError("check labels", err)
+		return
+	}
+	r.LabelWarnings = warnings
+}
+
+func (s *recipeService) ListPeople(ctx context.Context) ([]recipe.Person, error) {
+	return s.repo.ListPeople(ctx)
+}
+
+func (s *recipeService) SavePerson(ctx context.Context, p recipe.Person) (*recipe.Person, error) {
+	recipe.NormalizePerson(&p)
+	if err := recipe.ValidatePerson(p); err != nil {
+		return nil, err
+	}
+	return s.repo.SavePerson(ctx, p)
+}
+
+func (s *recipeService) DeletePerson(ctx context.Context, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return recipe.InvalidPersonError{Reason: "name is required"}
+	}
+	return s.repo.DeletePerson(ctx, name)
+}
+
+// resolvePeople looks up household members by name, recipe.Everyone standing
+// for all of them. No names resolve to no one.
+func (s *recipeService) resolvePeople(ctx context.Context, names []string) ([]recipe.Person, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	household, err := s.repo.ListPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var people []recipe.Person
+	seen := map[string]bool{}
+	add := func(p recipe.Person) {
+		if !seen[strings.ToLower(p.Name)] {
+			seen[strings.ToLower(p.Name)] = true
+			people = append(people, p)
+		}
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.EqualFold(name, recipe.Everyone) {
+			for _, p := range household {
+				add(p)
+			}
+			continue
+		}
+		found := false
+		for _, p := range household {
+			if strings.EqualFold(p.Name, name) {
+				add(p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, recipe.PersonNotFoundError{Name: name}
+		}
+	}
+	return people, nil
+}
+
+// markConflicts sets each recipe's Conflicts for people, sub-recipes
+// included.
+func (s *recipeService) markConflicts(ctx context.Context, recipes []*recipe.Recipe, people []recipe.Person) error {
+	if len(people) == 0 {
+		return nil
+	}
+	for _, r := range recipes {
+		expanded, err := s.expandedCopy(ctx, *r)
+		if err != nil {
+			return err
+		}
+		r.Conflicts = recipe.FindConflicts(expanded, people)
+	}
+	return nil
+}
+
+func (s *recipeService) Taxonomy(ctx context.Context) (recipe.Taxonomy, error) {
+	return s.repo.ListTaxonomy(ctx)
+}
+
+func (s *recipeService) SaveLabelType(ctx context.Context, lt recipe.LabelType) (*recipe.LabelType, error) {
+	recipe.NormalizeLabelType(&lt)
+	if err := recipe.ValidateLabelType(lt); err != nil {
+		return nil, err
+	}
+	return s.repo.SaveLabelType(ctx, lt)
+}
+
+func (s *recipeService) SaveLabel(ctx context.Context, d recipe.LabelDefinition) (*recipe.LabelDefinition, error) {
+	recipe.NormalizeLabelDefinition(&d)
+	taxonomy, err := s.repo.ListTaxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := taxonomy.ValidateLabelDefinition(d); err != nil {
+		return nil, err
+	}
+	return s.repo.SaveLabel(ctx, d)
+}
+
+func (s *recipeService) RenameLabel(ctx context.Context, label recipe.Label, to string, dryRun bool) (*recipe.LabelChange, error) {
+	taxonomy, err := s.repo.ListTaxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := lookupLabel(taxonomy, label)
+	if err != nil {
+		return nil, err
+	}
+	to = recipe.NormalizeLabelName(to)
+	if to == "" {
+		return nil, recipe.InvalidLabelError{Reason: "new label name is required"}
+	}
+	if other, ok := taxonomy.Lookup(current.Type, to); ok && other.Name != current.Name {
+		return nil, recipe.InvalidLabelError{Reason: fmt.Sprintf("%q already means %s:%s; merge the labels instead", to, other.Type, other.Name)}
+	}
+
+	change := &recipe.LabelChange{
+		From:   recipe.Label{Type: current.Type, Name: current.Name},
+		Label:  current,
+		DryRun: dryRun,
+	}
+	if change.Recipes, err = s.repo.ListLabelRecipes(ctx, change.From); err != nil {
+		return nil, err
+	}
+	if to == current.Name {
+		return change, nil
+	}
+
+	renamed := current
+	renamed.Name = to
+	// A display name that was only ever the default follows the new name.
+	if current.DisplayName == recipe.DefaultDisplayName(current.Name) {
+		renamed.DisplayName = ""
+	}
+	renamed.Aliases = append(append([]string{}, current.Aliases...), current.Name)
+	recipe.NormalizeLabelDefinition(&renamed)
+	change.Label = renamed
+	if dryRun {
+		return change, nil
+	}
+
+	saved, err := s.repo.RenameLabel(ctx, current.Type, current.Name, renamed)
+	if err != nil {
+		return nil, err
+	}
+	saved.Uses = current.Uses
+	change.Label = *saved
+	return change, nil
+}
+
+func (s *recipeService) MergeLabels(ctx context.Context, from, into recipe.Label, dryRun bool) (*recipe.LabelChange, error) {
+	taxonomy, err := s.repo.ListTaxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source, err := lookupLabel(taxonomy, from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := lookupLabel(taxonomy, into)
+	if err != nil {
+		return nil, err
+	}
+	if source.Type != target.Type {
+		return nil, recipe.InvalidLabelError{Reason: fmt.Sprintf("can't merge %s:%s into a %s label", source.Type, source.Name, target.Type)}
+	}
+	if source.Name == target.Name {
+		return nil, recipe.InvalidLabelError{Reason: fmt.Sprintf("can't merge %s:%s into itself", source.Type, source.Name)}
+	}
+
+	merged := target
+	merged.Aliases = append(append(append([]string{}, target.Aliases...), source.Name), source.Aliases...)
+	recipe.NormalizeLabelDefinition(&merged)
+	change := &recipe.LabelChange{
+		From:   recipe.Label{Type: source.Type, Name: source.Name},
+		DryRun: dryRun,
+	}
+	into = recipe.Label{Type: target.Type, Name: target.Name}
+
+	if dryRun {
+		if change.Recipes, err = s.repo.ListLabelRecipes(ctx, change.From); err != nil {
+			return nil, err
+		}
+		carrying, err := s.repo.ListLabelRecipes(ctx, into)
+		if err != nil {
+			return nil, err
+		}
+		merged.Uses = countRecipes(change.Recipes, carrying)
+		change.Label = merged
+		return change, nil
+	}
+
+	if change.Recipes, err = s.repo.MergeLabels(ctx, change.From, into, merged.Aliases); err != nil {
+		return nil, err
+	}
+	// Recipes carrying both count once, so re-read the uses.
+	if taxonomy, err = s.repo.ListTaxonomy(ctx); err != nil {
+		return nil, err
+	}
+	change.Label, _ = taxonomy.Lookup(target.Type, target.Name)
+	return change, nil
+}
+
+// countRecipes counts the distinct recipes across lists.
+func countRecipes(lists ...[]recipe.RecipeRef) int {
+	seen := map[uuid.UUID]bool{}
+	for _, list := range lists {
+		for _, r := range list {
+			seen[r.UUID] = true
+		}
+	}
+	return len(seen)
+}
+
+// lookupLabel finds label in taxonomy by name or alias, or returns a
+// LabelNotFoundError.
+func lookupLabel(taxonomy recipe.Taxonomy, label recipe.Label) (recipe.LabelDefinition, error) {
+	if _, ok := taxonomy.Type(label.Type); !ok {
+		return recipe.LabelDefinition{}, recipe.LabelNotFoundError{Type: label.Type}
+	}
+	d, ok := taxonomy.Lookup(label.Type, label.Name)
+	if !ok {
+		return recipe.LabelDefinition{}, recipe.LabelNotFoundError{Type: label.Type, Name: label.Name}
+	}
+	return d, nil
+}
+
+// linkStepIngredients links r's steps to its ingredient lines, finding lines
+// by their ingredients' aliases too. The catalogue is only read when a step
+// has links to infer.
+func (s *recipeService) linkStepIngredients(ctx context.Context, r *recipe.Recipe) error {
+	var catalogue recipe.IngredientCatalogue
+	for _, step := range r.Steps {
+		if step.Ingredients == nil {
+			var err error
+			if catalogue, err = s.repo.ListIngredientCatalogue(ctx); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	recipe.LinkStepIngredients(r.Steps, r.Ingredients, catalogue)
+	return nil
+}
+
+// resolveLabels replaces r's labels with their canonical names, rejecting any
+// the taxonomy doesn't allow.
+func (s *recipeService) resolveLabels(ctx context.Context, r *recipe.Recipe) error {
+	if len(r.Labels) == 0 {
+		return nil
+	}
+	taxonomy, err := s.repo.ListTaxonomy(ctx)
+	if err != nil {
+		return err
+	}
+	r.Labels, err = taxonomy.ResolveLabels(r.Labels)
+	return err
+}
+
+func (s *recipeService) IngredientCatalogue(ctx context.Context) (recipe.IngredientCatalogue, error) {
+	return s.repo.ListIngredientCatalogue(ctx)
+}
+
+func (s *recipeService) SetIngredientAliases(ctx context.Context, name string, aliases []string) (*recipe.IngredientSummary, error) {
+	catalogue, err := s.repo.ListIngredientCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, ok := catalogue.Find(name)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: name}
+	}
+	aliases = recipe.NormalizeIngredientAliases(current.Name, aliases)
+	if err := catalogue.CheckNames(current.Name, aliases...); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetIngredientAliases(ctx, current.Name, aliases); err != nil {
+		return nil, err
+	}
+	return s.catalogueEntry(ctx, current.Name)
+}
+
+func (s *recipeService) RenameIngredient(ctx context.Context, name, to string) (*recipe.IngredientSummary, error) {
+	catalogue, err := s.repo.ListIngredientCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, ok := catalogue.Find(name)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: name}
+	}
+	to = recipe.NormalizeIngredientName(to)
+	if to == "" {
+		return nil, recipe.InvalidIngredientError{Reason: "new name is required"}
+	}
+	if to == current.Name {
+		return &current, nil
+	}
+	if err := catalogue.CheckNames(current.Name, to); err != nil {
+		return nil, err
+	}
+
+	aliases := recipe.NormalizeIngredientAliases(to, append(append([]string{}, current.Aliases...), current.Name))
+	if err := s.repo.RenameIngredient(ctx, current.Name, to, aliases); err != nil {
+		return nil, err
+	}
+	return s.catalogueEntry(ctx, to)
+}
+
+func (s *recipeService) MergeIngredients(ctx context.Context, from, into string) (*recipe.IngredientSummary, error) {
+	catalogue, err := s.repo.ListIngredientCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := catalogue.Find(from)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: from}
+	}
+	target, ok := catalogue.Find(into)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: into}
+	}
+	if source.Name == target.Name {
+		return nil, recipe.InvalidIngredientError{Reason: fmt.Sprintf("can't merge %q into itself", source.Name)}
+	}
+
+	names := append(append(append([]string{}, target.Aliases...), source.Name), source.Aliases...)
+	aliases := recipe.NormalizeIngredientAliases(target.Name, names)
+	if err := s.repo.MergeIngredients(ctx, source.Name, target.Name, aliases); err != nil {
+		return nil, err
+	}
+	return s.catalogueEntry(ctx, target.Name)
+}
+
+// catalogueEntry re-reads name from the catalogue after a change, for its
+// up-to-date usage.
+func (s *recipeService) catalogueEntry(ctx context.Context, name string) (*recipe.IngredientSummary, error) {
+	catalogue, err := s.repo.ListIngredientCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := catalogue.Find(name)
+	if !ok {
+		return nil, recipe.IngredientNotFoundError{Name: name}
+	}
+	return &entry, nil
+}
+
+func (s *recipeService) ListUnits(ctx context.Context) (recipe.UnitRegistry, error) {
 	return s.repo.ListUnits(ctx)
 }
 
-func (s *recipeService) ListIngredients(ctx context.Context) ([]recipe.Ingredient, error) {
-	return s.repo.ListIngredients(ctx)
+func (s *recipeService) SaveUnit(ctx context.Context, u recipe.Unit) (*recipe.Unit, error) {
+	u = recipe.NormalizeUnit(u)
+	if err := recipe.ValidateUnit(u); err != nil {
+		return nil, err
+	}
+	registry, err := s.repo.ListUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := append([]string{u.Name, u.Abbreviation}, u.Aliases...)
+	if err := registry.CheckNames(u.Name, names...); err != nil {
+		return nil, err
+	}
+	return s.repo.SaveUnit(ctx, u)
+}
+
+func (s *recipeService) MergeUnits(ctx context.Context, from, into string) (*recipe.Unit, error) {
+	registry, err := s.repo.ListUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := registry.Find(from)
+	if !ok {
+		return nil, recipe.UnitNotFoundError{Name: from}
+	}
+	target, ok := registry.Find(into)
+	if !ok {
+		return nil, recipe.UnitNotFoundError{Name: into}
+	}
+	if source.Name == target.Name {
+		return nil, recipe.InvalidUnitError{Reason: fmt.Sprintf("can't merge %q into itself", source.Name)}
+	}
+	if source.Dimension != target.Dimension {
+		return nil, recipe.InvalidUnitError{Reason: fmt.Sprintf("%q is %s and %q is %s", source.Name, source.Dimension, target.Name, target.Dimension)}
+	}
+
+	merged := target
+	merged.Aliases = append(append(append([]string{}, target.Aliases...), source.Name, source.Abbreviation), source.Aliases...)
+	merged = recipe.NormalizeUnit(merged)
+	if err := s.repo.MergeUnits(ctx, source.Name, target.Name, merged.Aliases); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+func (s *recipeService) UnitReviews(ctx context.Context) ([]recipe.UnitReview, error) {
+	return s.repo.ListUnitReviews(ctx)
+}
+
+func (s *recipeService) DismissUnitReview(ctx context.Context, name string) error {
+	return s.repo.DismissUnitReview(ctx, name)
 }
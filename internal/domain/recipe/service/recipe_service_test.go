@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/metrics"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/repository"
+)
+
+// stubRecipeRepo implements what the tests here reach; anything else panics
+// on the nil embedded interface.
+type stubRecipeRepo struct {
+	repository.RecipeRepository
+	stored    *recipe.Recipe
+	updated   *recipe.Recipe
+	catalogue recipe.IngredientCatalogue
+	cooks     []recipe.Cook
+}
+
+func (s *stubRecipeRepo) GetRecipeByID(_ context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	if s.stored != nil {
+		stored := *s.stored
+		return &stored, nil
+	}
+	return &recipe.Recipe{UUID: id, Name: "Dal"}, nil
+}
+
+func (s *stubRecipeRepo) UpdateRecipe(_ context.Context, id uuid.UUID, r recipe.Recipe) (*recipe.Recipe, error) {
+	r.UUID = id
+	s.updated = &r
+	return &r, nil
+}
+
+func (s *stubRecipeRepo) ListIngredientCatalogue(context.Context) (recipe.IngredientCatalogue, error) {
+	return s.catalogue, nil
+}
+
+func (s *stubRecipeRepo) LogCook(_ context.Context, cook recipe.Cook) (*recipe.Cook, error) {
+	s.cooks = append(s.cooks, cook)
+	return &cook, nil
+}
+
+func TestLogCook_Validates(t *testing.T) {
+	tests := []struct {
+		name    string
+		cook    recipe.Cook
+		invalid bool
+	}{
+		{"unrated", recipe.Cook{}, false},
+		{"rated and served", recipe.Cook{Rating: 5, Servings: 4}, false},
+		{"rating too high", recipe.Cook{Rating: 6}, true},
+		{"rating negative", recipe.Cook{Rating: -1}, true},
+		{"servings negative", recipe.Cook{Servings: -2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRecipeRepo{}
+			svc := NewRecipeService(repo, metrics.NoopRecipeProbe{})
+
+			logged, err := svc.LogCook(context.Background(), uuid.New(), tt.cook)
+			if tt.invalid {
+				if !errors.Is(err, recipe.ErrInvalidCook) {
+					t.Errorf("expected ErrInvalidCook, got %v", err)
+				}
+				if len(repo.cooks) != 0 {
+					t.Errorf("an invalid cook was stored: %+v", repo.cooks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if logged.UUID == uuid.Nil || logged.CookedOn.IsZero() {
+				t.Errorf("expected an id and today's date filled in, got %+v", logged)
+			}
+		})
+	}
+}
+
+func TestUpdateRecipe_ReorderedIngredientsRelinkSteps(t *testing.T) {
+	onion := recipe.RecipeIngredient{Ingredient: recipe.Ingredient{Name: "onion"}, Quantity: 1}
+	garlic := recipe.RecipeIngredient{Ingredient: recipe.Ingredient{Name: "garlic"}, Quantity: 2}
+	repo := &stubRecipeRepo{stored: &recipe.Recipe{
+		Name:        "Dal",
+		Ingredients: []recipe.RecipeIngredient{onion, garlic},
+	}}
+	svc := NewRecipeService(repo, metrics.NoopRecipeProbe{})
+
+	// The client moved garlic to the top but sent the links it was given,
+	// which point at the stored order.
+	_, err := svc.UpdateRecipe(context.Background(), uuid.New(), recipe.Recipe{
+		Name:        "Dal",
+		Ingredients: []recipe.RecipeIngredient{garlic, onion},
+		Steps: []recipe.Step{
+			{Description: "Crush the garlic.", Ingredients: []recipe.StepIngredient{{Line: 1}}},
+			{Description: "Season.", Ingredients: []recipe.StepIngredient{}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	steps := repo.updated.Steps
+	if len(steps[0].Ingredients) != 1 || steps[0].Ingredients[0].Line != 0 || steps[0].Ingredients[0].Ingredient.Name != "garlic" {
+		t.Errorf("expected the garlic step relinked to line 0, got %+v", steps[0].Ingredients)
+	}
+	if len(steps[1].Ingredients) != 0 {
+		t.Errorf("an explicitly empty link list was changed: %+v", steps[1].Ingredients)
+	}
+}
+
+func TestUpdateRecipe_KeepsSectionsLeftOut(t *testing.T) {
+	repo := &stubRecipeRepo{stored: &recipe.Recipe{
+		Name: "Focaccia",
+		Steps: []recipe.Step{
+			{Description: "Mix the dough.", Section: "Day one"},
+			{Description: "Bake.", Section: "Day two"},
+		},
+	}}
+	svc := NewRecipeService(repo, metrics.NoopRecipeProbe{})
+
+	_, err := svc.UpdateRecipe(context.Background(), uuid.New(), recipe.Recipe{
+		Name:  "Focaccia",
+		Steps: []recipe.Step{{Description: "Mix the dough."}, {Description: "Bake."}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.updated.Steps; got[0].Section != "Day one" || got[1].Section != "Day two" {
+		t.Errorf("expected stored sections kept, got %+v", got)
+	}
+}
+
+func TestUpdateRecipe_KeepsEquipmentLeftOut(t *testing.T) {
+	repo := &stubRecipeRepo{stored: &recipe.Recipe{
+		Name:      "Stir fry",
+		Steps:     []recipe.Step{{Description: "Fry everything."}},
+		Equipment: []string{"wok"},
+	}}
+	svc := NewRecipeService(repo, metrics.NoopRecipeProbe{})
+
+	_, err := svc.UpdateRecipe(context.Background(), uuid.New(), recipe.Recipe{
+		Name:  "Stir fry",
+		Steps: []recipe.Step{{Description: "Fry everything, then finish in the oven."}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.updated.Equipment; len(got) != 1 || got[0] != "wok" {
+		t.Errorf("expected the stored equipment kept, got %v", got)
+	}
+}
@@ -0,0 +1,259 @@
+package recipe
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Timer is a timed stretch of a step: "simmer for 20–25 minutes".
+type Timer struct {
+	Label string `json:"label"`
+	// Seconds is how long the timer runs. For a range, Seconds is the lower
+	// bound and MaxSeconds the upper one; MaxSeconds is zero otherwise.
+	Seconds    int `json:"seconds"`
+	MaxSeconds int `json:"maxSeconds,omitempty"`
+	// Active is true when the cook is busy for the duration (stirring,
+	// kneading) and false when the food is left to it (baking, resting).
+	Active bool `json:"active"`
+}
+
+// Units a Temperature can be given in.
+const (
+	TemperatureCelsius    = "C"
+	TemperatureFahrenheit = "F"
+	TemperatureGasMark    = "gas"
+)
+
+// Temperature is an oven, pan or probe temperature a step calls for. "200°C /
+// gas 6" is two temperatures, one per unit, as written.
+type Temperature struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+	Fan   bool    `json:"fan,omitempty"`
+}
+
+// FillStepDetails parses timers and temperatures out of each step's
+// description where the step doesn't carry them already. nil means "none
+// supplied"; a step given an empty list keeps it empty.
+func FillStepDetails(steps []Step) {
+	for i := range steps {
+		if steps[i].Timers == nil {
+			steps[i].Timers = ParseTimers(steps[i].Description)
+		}
+		if steps[i].Temperatures == nil {
+			steps[i].Temperatures = ParseTemperatures(steps[i].Description)
+		}
+	}
+}
+
+var (
+	durationPattern = regexp.MustCompile(`(?i)(\b\d+(?:\.\d+)?[½¼¾]?|[½¼¾]|\b(?:an?|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve|half an?))` +
+		`(?:\s*(?:-|–|—|to|or)\s*(\d+(?:\.\d+)?[½¼¾]?|[½¼¾]))?` +
+		`\s*(seconds?|secs?|minutes?|mins?|hours?|hrs?)\b`)
+
+	// Only "and" or punctuation may sit between the parts of "1 hour 30 minutes".
+	compoundGap = regexp.MustCompile(`^[\s,]*(?:and\s*)?$`)
+
+	clauseEnd = regexp.MustCompile(`[.;!?](?:\s|$)|\n`)
+
+	// Cooking verbs, as stems so "baking" and "baked" match "bak". The first
+	// one in a clause before its duration labels the timer.
+	timerVerbs = regexp.MustCompile(`(?i)\b(bak|roast|simmer|boil|rest|chill|marinat|prov|proof|ris|knead|fr[iy]|saut|whisk|stir|cook|grill|steam|soak|cool|freez|leav|refrigerat|brais|toast|blend|mix|beat|reduc|poach|sear|brown|caramel|microwav|blanch|steep|stand|heat|warm|melt|dr[iy]|infus|ferment|smok|set aside|fold|toss)\w*`)
+
+	// Stems that keep the cook at the stove for the duration.
+	activeVerbs = regexp.MustCompile(`(?i)\b(knead|fr[iy]|saut|whisk|stir|beat|mix|blend|sear|brown|toss|fold|massag)\w*`)
+
+	temperaturePattern = regexp.MustCompile(`(\d{1,3})\s*(°|º|˚|degrees?)?\s*(C|F|[Cc]elsius|[Ff]ahrenheit)\b|(\d{1,3})\s*(℃|℉)`)
+	gasMarkPattern     = regexp.MustCompile(`(?i)\bgas(?:\s+mark)?\s*(\d+(?:\.\d+)?|½|¼)`)
+
+	// "fan" belongs to the temperature it directly precedes ("fan 160C") or
+	// follows ("160C fan", "160C (fan)"), so in "180C (fan 160C)" only 160 is.
+	fanBefore = regexp.MustCompile(`(?i)\bfan(?:[\s-]+(?:oven|assisted))?\s*(?:at\s*)?$`)
+	fanAfter  = regexp.MustCompile(`(?i)^\s*(?:fan\b|\(\s*fan\s*\))`)
+)
+
+// A bare "C" or "F" with no degree sign is only read as a temperature in these
+// oven ranges; "12 C of flour" is cups.
+const (
+	minBareCelsius, maxBareCelsius       = 100, 300
+	minBareFahrenheit, maxBareFahrenheit = 200, 575
+)
+
+var fractions = map[string]float64{"½": 0.5, "¼": 0.25, "¾": 0.75}
+
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	"half a": 0.5, "half an": 0.5,
+}
+
+// ParseTimers finds the durations in a step's text. It never returns nil.
+func ParseTimers(text string) []Timer {
+	timers := []Timer{}
+	lastEnd, lastHours := -1, false
+
+	for _, m := range durationPattern.FindAllStringSubmatchIndex(text, -1) {
+		word := strings.ToLower(text[m[2]:m[3]])
+		unit := strings.ToLower(text[m[6]:m[7]])
+		perUnit := unitSeconds(unit)
+
+		n, ok := parseAmount(word)
+		if !ok {
+			// "a second" is almost always "another", not one second.
+			if perUnit == 1 {
+				continue
+			}
+			n = numberWords[word]
+		}
+		seconds := int(n * float64(perUnit))
+		maxSeconds := 0
+		if m[4] >= 0 {
+			hi, _ := parseAmount(text[m[4]:m[5]])
+			maxSeconds = int(hi * float64(perUnit))
+		}
+
+		// "1 hour 30 minutes" is one timer, not two. Either part may be a
+		// range: "1 hour 20 to 30 minutes" runs 80 to 90 minutes.
+		if lastHours && perUnit < 3600 && len(timers) > 0 && compoundGap.MatchString(text[lastEnd:m[0]]) {
+			prev := &timers[len(timers)-1]
+			if prev.MaxSeconds > 0 || maxSeconds > 0 {
+				prev.MaxSeconds = max(prev.Seconds, prev.MaxSeconds) + max(seconds, maxSeconds)
+			}
+			prev.Seconds += seconds
+			lastEnd, lastHours = m[1], false
+			continue
+		}
+
+		clauseStart, clauseStop := clauseAround(text, m[0], m[1])
+		timers = append(timers, Timer{
+			Label:      timerLabel(text[clauseStart:m[0]], text[clauseStart:clauseStop]),
+			Seconds:    seconds,
+			MaxSeconds: maxSeconds,
+			Active:     isActive(text[clauseStart:clauseStop]),
+		})
+		lastEnd, lastHours = m[1], perUnit == 3600
+	}
+	return timers
+}
+
+// parseAmount reads "2", "1.5", "1½" or "½".
+func parseAmount(s string) (float64, bool) {
+	for frac, v := range fractions {
+		if whole, ok := strings.CutSuffix(s, frac); ok {
+			if whole == "" {
+				return v, true
+			}
+			n, err := strconv.ParseFloat(whole, 64)
+			return n + v, err == nil
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
+
+func unitSeconds(unit string) int {
+	switch {
+	case strings.HasPrefix(unit, "h"):
+		return 3600
+	case strings.HasPrefix(unit, "m"):
+		return 60
+	default:
+		return 1
+	}
+}
+
+// clauseAround returns the bounds of the sentence containing text[start:end].
+func clauseAround(text string, start, end int) (int, int) {
+	from := 0
+	for _, loc := range clauseEnd.FindAllStringIndex(text[:start], -1) {
+		from = loc[1]
+	}
+	to := len(text)
+	if loc := clauseEnd.FindStringIndex(text[end:]); loc != nil {
+		to = end + loc[0]
+	}
+	return from, to
+}
+
+// timerLabel names a timer after the last cooking verb before its duration,
+// or failing that the first in its clause, or failing that the clause itself.
+func timerLabel(before, clause string) string {
+	if verbs := timerVerbs.FindAllString(before, -1); len(verbs) > 0 {
+		return strings.ToLower(verbs[len(verbs)-1])
+	}
+	if verb := timerVerbs.FindString(clause); verb != "" {
+		return strings.ToLower(verb)
+	}
+	label := strings.TrimSpace(clause)
+	if r := []rune(label); len(r) > 40 {
+		label = strings.TrimSpace(string(r[:40])) + "…"
+	}
+	return label
+}
+
+// isActive reports whether a clause keeps the cook busy. "Stirring
+// occasionally" doesn't.
+func isActive(clause string) bool {
+	return activeVerbs.MatchString(clause) && !strings.Contains(strings.ToLower(clause), "occasionally")
+}
+
+// ParseTemperatures finds the temperatures in a step's text, in the order
+// written. It never returns nil.
+func ParseTemperatures(text string) []Temperature {
+	type found struct {
+		at int
+		t  Temperature
+	}
+	var all []found
+
+	for _, m := range temperaturePattern.FindAllStringSubmatchIndex(text, -1) {
+		// Either "200°C" (groups 1–3) or "200℃" (groups 4–5) matched.
+		var digits, unit string
+		bare := false
+		if m[2] >= 0 {
+			digits, unit = text[m[2]:m[3]], text[m[6]:m[7]]
+			bare = m[4] < 0 && len(unit) == 1
+		} else {
+			digits, unit = text[m[8]:m[9]], text[m[10]:m[11]]
+		}
+		value, _ := strconv.ParseFloat(digits, 64)
+		t := Temperature{Value: value, Unit: TemperatureCelsius}
+		if strings.HasPrefix(strings.ToUpper(unit), "F") || unit == "℉" {
+			t.Unit = TemperatureFahrenheit
+		}
+		if bare && !plausibleOven(t) {
+			continue
+		}
+		t.Fan = fanBefore.MatchString(text[:m[0]]) || fanAfter.MatchString(text[m[1]:])
+		all = append(all, found{at: m[0], t: t})
+	}
+
+	for _, m := range gasMarkPattern.FindAllStringSubmatchIndex(text, -1) {
+		var value float64
+		switch mark := text[m[2]:m[3]]; mark {
+		case "½":
+			value = 0.5
+		case "¼":
+			value = 0.25
+		default:
+			value, _ = strconv.ParseFloat(mark, 64)
+		}
+		all = append(all, found{at: m[0], t: Temperature{Value: value, Unit: TemperatureGasMark}})
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].at < all[j].at })
+	temps := make([]Temperature, len(all))
+	for i, f := range all {
+		temps[i] = f.t
+	}
+	return temps
+}
+
+func plausibleOven(t Temperature) bool {
+	if t.Unit == TemperatureFahrenheit {
+		return t.Value >= minBareFahrenheit && t.Value <= maxBareFahrenheit
+	}
+	return t.Value >= minBareCelsius && t.Value <= maxBareCelsius
+}
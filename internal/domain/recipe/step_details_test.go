@@ -0,0 +1,69 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTimers(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Timer
+	}{
+		{"Simmer for 20–25 minutes.", []Timer{{Label: "simmer", Seconds: 1200, MaxSeconds: 1500}}},
+		{"Knead for 10 mins until smooth.", []Timer{{Label: "knead", Seconds: 600, Active: true}}},
+		{"Cover and leave to rise for 1 hour 30 minutes.", []Timer{{Label: "rise", Seconds: 5400}}},
+		{"Fry the onions for 5 minutes, stirring. Bake for an hour.", []Timer{
+			{Label: "fry", Seconds: 300, Active: true},
+			{Label: "bake", Seconds: 3600},
+		}},
+		{"Simmer for 2 hours, stirring occasionally.", []Timer{{Label: "simmer", Seconds: 7200}}},
+		{"Bake for 1 hour 20 to 30 minutes.", []Timer{{Label: "bake", Seconds: 4800, MaxSeconds: 5400}}},
+		{"Braise for 1 to 1½ hours.", []Timer{{Label: "braise", Seconds: 3600, MaxSeconds: 5400}}},
+		{"Rest for ½ hour.", []Timer{{Label: "rest", Seconds: 1800}}},
+		{"Add a second egg.", []Timer{}},
+		{"Season to taste.", []Timer{}},
+	}
+	for _, tt := range tests {
+		if got := ParseTimers(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTimers(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseTemperatures(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Temperature
+	}{
+		{"Bake at 200°C / gas 6 for 40 minutes.", []Temperature{{Value: 200, Unit: "C"}, {Value: 6, Unit: "gas"}}},
+		{"Heat the oven to 180C fan.", []Temperature{{Value: 180, Unit: "C", Fan: true}}},
+		{"Roast at 425 degrees F.", []Temperature{{Value: 425, Unit: "F"}}},
+		{"Preheat to gas mark ½.", []Temperature{{Value: 0.5, Unit: "gas"}}},
+		{"Bake at 180C (fan 160C).", []Temperature{{Value: 180, Unit: "C"}, {Value: 160, Unit: "C", Fan: true}}},
+		{"Bake at 160C fan / 180C.", []Temperature{{Value: 160, Unit: "C", Fan: true}, {Value: 180, Unit: "C"}}},
+		{"Bake at 180C (fan) for an hour.", []Temperature{{Value: 180, Unit: "C", Fan: true}}},
+		{"Chill to 4°C.", []Temperature{{Value: 4, Unit: "C"}}},
+		{"Whisk in 12 C of flour.", []Temperature{}},
+		{"Add 200 g flour.", []Temperature{}},
+	}
+	for _, tt := range tests {
+		if got := ParseTemperatures(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTemperatures(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFillStepDetails_KeepsSupplied(t *testing.T) {
+	steps := []Step{
+		{Description: "Bake for 20 minutes.", Timers: []Timer{}},
+		{Description: "Bake for 20 minutes."},
+	}
+	FillStepDetails(steps)
+	if len(steps[0].Timers) != 0 {
+		t.Errorf("an explicitly empty timer list was overwritten: %+v", steps[0].Timers)
+	}
+	if len(steps[1].Timers) != 1 || steps[1].Temperatures == nil {
+		t.Errorf("expected parsed details, got %+v", steps[1])
+	}
+}
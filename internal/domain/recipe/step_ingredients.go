@@ -0,0 +1,262 @@
+package recipe
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StepIngredient is a recipe ingredient line used in a step. Line is its
+// index in the recipe's Ingredients; the line itself is repeated alongside so
+// a step can be shown without looking it up.
+type StepIngredient struct {
+	Line int `json:"line"`
+	RecipeIngredient
+}
+
+// LinkStepIngredients fills in which ingredient lines each step uses. Steps
+// that already carry links keep them (with the line details refreshed from
+// ingredients, and links to lines that no longer exist dropped); steps with
+// none (nil) have them inferred from their text, where a line is also found
+// by the catalogue's other names for its ingredient ("scallions" for "spring
+// onion"). catalogue may be nil. A step given an empty list keeps it empty.
+func LinkStepIngredients(steps []Step, ingredients []RecipeIngredient, catalogue IngredientCatalogue) {
+	var matchers []lineMatcher
+	for i := range steps {
+		if steps[i].Ingredients == nil {
+			if matchers == nil {
+				matchers = buildLineMatchers(ingredients, catalogue)
+			}
+			steps[i].Ingredients = inferStepIngredients(steps[i].Description, ingredients, matchers)
+			continue
+		}
+		linked := make([]StepIngredient, 0, len(steps[i].Ingredients))
+		for _, si := range steps[i].Ingredients {
+			if si.Line >= 0 && si.Line < len(ingredients) {
+				linked = append(linked, StepIngredient{Line: si.Line, RecipeIngredient: ingredients[si.Line]})
+			}
+		}
+		steps[i].Ingredients = linked
+	}
+}
+
+// DropMovedStepLinks clears the links of any step pointing at a line whose
+// ingredient isn't the one at that index in before, the lines the links were
+// made against. An update that inserts, removes or reorders lines would
+// otherwise leave those links on whatever line moved into the position; with
+// them cleared, LinkStepIngredients infers them afresh.
+func DropMovedStepLinks(steps []Step, before, after []RecipeIngredient) {
+	for i := range steps {
+		for _, si := range steps[i].Ingredients {
+			if si.Line < 0 || si.Line >= len(before) || si.Line >= len(after) || !sameLine(before[si.Line], after[si.Line]) {
+				steps[i].Ingredients = nil
+				break
+			}
+		}
+	}
+}
+
+// sameLine reports whether two lines are the same use of the same
+// ingredient, whatever their quantities.
+func sameLine(a, b RecipeIngredient) bool {
+	if (a.SubRecipe == nil) != (b.SubRecipe == nil) || (a.SubRecipe != nil && a.SubRecipe.UUID != b.SubRecipe.UUID) {
+		return false
+	}
+	return strings.EqualFold(NormalizeIngredientName(a.Ingredient.Name), NormalizeIngredientName(b.Ingredient.Name)) &&
+		strings.EqualFold(strings.TrimSpace(a.Component), strings.TrimSpace(b.Component))
+}
+
+// StepIngredientLines returns the line indexes a step links to, or nil when
+// it has none recorded.
+func StepIngredientLines(s Step) []int {
+	if s.Ingredients == nil {
+		return nil
+	}
+	lines := make([]int, len(s.Ingredients))
+	for i, si := range s.Ingredients {
+		lines[i] = si.Line
+	}
+	return lines
+}
+
+// StepIngredientsFromLines is the inverse of StepIngredientLines, ready for
+// LinkStepIngredients to fill in.
+func StepIngredientsFromLines(lines []int) []StepIngredient {
+	if lines == nil {
+		return nil
+	}
+	links := make([]StepIngredient, len(lines))
+	for i, line := range lines {
+		links[i].Line = line
+	}
+	return links
+}
+
+// lineMatcher finds mentions of one ingredient name in step text.
+type lineMatcher struct {
+	pattern *regexp.Regexp
+	lines   []int // every line with this name
+	length  int
+}
+
+// buildLineMatchers makes a matcher per distinct ingredient name, covering
+// simple plurals, the catalogue's other names for the ingredient unless
+// another line goes by them, and, for a multi-word name, its last word ("red
+// onion" is "the onion" later on) when no other line shares that word.
+// Longest names come first so "soy sauce" is matched before "soy".
+func buildLineMatchers(ingredients []RecipeIngredient, catalogue IngredientCatalogue) []lineMatcher {
+	byName := map[string][]int{}
+	var names []string
+	heads := map[string]int{}
+	for i, ri := range ingredients {
+		name := strings.ToLower(strings.TrimSpace(ri.Ingredient.Name))
+		if name == "" {
+			continue
+		}
+		if _, seen := byName[name]; !seen {
+			names = append(names, name)
+			if words := strings.Fields(name); len(words) > 1 {
+				heads[words[len(words)-1]]++
+			}
+		}
+		byName[name] = append(byName[name], i)
+	}
+
+	matchers := make([]lineMatcher, 0, len(names))
+	for _, name := range names {
+		variants := pluralVariants(name)
+		length := len(name)
+		for _, other := range otherNames(catalogue, name) {
+			if _, isName := byName[other]; !isName {
+				variants = append(variants, pluralVariants(other)...)
+				length = max(length, len(other))
+			}
+		}
+		if words := strings.Fields(name); len(words) > 1 {
+			head := words[len(words)-1]
+			if _, isName := byName[head]; heads[head] == 1 && !isName {
+				variants = append(variants, pluralVariants(head)...)
+			}
+		}
+		sort.Slice(variants, func(i, j int) bool { return len(variants[i]) > len(variants[j]) })
+		for i, v := range variants {
+			variants[i] = regexp.QuoteMeta(v)
+		}
+		matchers = append(matchers, lineMatcher{
+			pattern: regexp.MustCompile(`(?i)\b(?:` + strings.Join(variants, "|") + `)\b`),
+			lines:   byName[name],
+			length:  length,
+		})
+	}
+	sort.SliceStable(matchers, func(i, j int) bool { return matchers[i].length > matchers[j].length })
+	return matchers
+}
+
+// otherNames returns what else the catalogue calls the ingredient name
+// resolves to: its aliases, and its own name when name is one of those.
+func otherNames(catalogue IngredientCatalogue, name string) []string {
+	ing, ok := catalogue.Resolve(name)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, n := range append([]string{strings.ToLower(ing.Name)}, ing.Aliases...) {
+		if n != name && !containsString(names, n) {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// inferStepIngredients returns the lines mentioned in text, in the order they
+// are first mentioned. Text matched by a longer name isn't matched again. When
+// an ingredient has several lines, the step's mention of a component ("for the
+// pastry") picks between them; otherwise all of them are linked.
+func inferStepIngredients(text string, ingredients []RecipeIngredient, matchers []lineMatcher) []StepIngredient {
+	type mention struct {
+		at    int
+		lines []int
+	}
+	var mentions []mention
+	taken := make([]bool, len(text))
+
+	for _, m := range matchers {
+		first := -1
+		for _, loc := range m.pattern.FindAllStringIndex(text, -1) {
+			if taken[loc[0]] || taken[loc[1]-1] {
+				continue
+			}
+			for i := loc[0]; i < loc[1]; i++ {
+				taken[i] = true
+			}
+			if first < 0 {
+				first = loc[0]
+			}
+		}
+		if first >= 0 {
+			mentions = append(mentions, mention{at: first, lines: pickLines(text, m.lines, ingredients)})
+		}
+	}
+	sort.SliceStable(mentions, func(i, j int) bool { return mentions[i].at < mentions[j].at })
+
+	linked := []StepIngredient{}
+	for _, m := range mentions {
+		for _, line := range m.lines {
+			linked = append(linked, StepIngredient{Line: line, RecipeIngredient: ingredients[line]})
+		}
+	}
+	return linked
+}
+
+func pickLines(text string, lines []int, ingredients []RecipeIngredient) []int {
+	if len(lines) < 2 {
+		return lines
+	}
+	lower := strings.ToLower(text)
+	var picked []int
+	for _, line := range lines {
+		if c := strings.ToLower(strings.TrimSpace(ingredients[line].Component)); c != "" && strings.Contains(lower, c) {
+			picked = append(picked, line)
+		}
+	}
+	if len(picked) == 0 {
+		return lines
+	}
+	return picked
+}
+
+// unfoldedPlurals are names whose singular and plural are different things in
+// the kitchen, so neither is a form of the other: "pepper" is the ground
+// spice, "peppers" the vegetable.
+var unfoldedPlurals = map[string]bool{
+	"pepper": true, "peppers": true,
+	"green": true, "greens": true,
+	"chip": true, "chips": true,
+	"crisp": true, "crisps": true,
+}
+
+// pluralVariants returns name with its simple English singular/plural forms,
+// as the app's ingredient highlighter does: egg/eggs, tomato/tomatoes,
+// cherry/cherries. Names in unfoldedPlurals have no other forms.
+func pluralVariants(name string) []string {
+	variants := []string{name}
+	if unfoldedPlurals[name] {
+		return variants
+	}
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		variants = append(variants, strings.TrimSuffix(name, "ies")+"y")
+	case strings.HasSuffix(name, "oes"), strings.HasSuffix(name, "ches"),
+		strings.HasSuffix(name, "shes"), strings.HasSuffix(name, "xes"):
+		variants = append(variants, strings.TrimSuffix(name, "es"))
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		variants = append(variants, strings.TrimSuffix(name, "s"))
+	}
+	if n := len(name); n > 1 && name[n-1] == 'y' && !strings.ContainsRune("aeiou", rune(name[n-2])) {
+		variants = append(variants, name[:n-1]+"ies")
+	}
+	if !strings.HasSuffix(name, "s") {
+		variants = append(variants, name+"s", name+"es")
+	}
+	return variants
+}
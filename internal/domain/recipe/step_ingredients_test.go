@@ -0,0 +1,120 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func lines(links []StepIngredient) []int {
+	out := []int{}
+	for _, l := range links {
+		out = append(out, l.Line)
+	}
+	return out
+}
+
+func TestLinkStepIngredients(t *testing.T) {
+	ingredients := []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "soy sauce"}, Quantity: 2},
+		{Ingredient: Ingredient{Name: "soy"}, Quantity: 1},
+		{Ingredient: Ingredient{Name: "red onion"}, Quantity: 1},
+		{Ingredient: Ingredient{Name: "tomatoes"}, Quantity: 4},
+		{Ingredient: Ingredient{Name: "butter"}, Quantity: 100, Component: "pastry"},
+		{Ingredient: Ingredient{Name: "butter"}, Quantity: 30, Component: "filling"},
+	}
+	steps := []Step{
+		{Description: "Fry the onion, then add a tomato and the soy sauce."},
+		{Description: "Rub the butter into the flour for the pastry."},
+		{Description: "Melt the butter."},
+		{Description: "Season with soy.", Ingredients: []StepIngredient{{Line: 0}, {Line: 9}}},
+		{Description: "Serve.", Ingredients: []StepIngredient{}},
+	}
+
+	LinkStepIngredients(steps, ingredients, nil)
+
+	want := [][]int{{2, 3, 0}, {4}, {4, 5}, {0}, {}}
+	for i, w := range want {
+		if got := lines(steps[i].Ingredients); !reflect.DeepEqual(got, w) {
+			t.Errorf("step %d lines = %v, want %v", i, got, w)
+		}
+	}
+	if steps[0].Ingredients[2].Quantity != 2 {
+		t.Errorf("expected the line's quantity alongside the link, got %+v", steps[0].Ingredients[2])
+	}
+}
+
+func TestLinkStepIngredients_CatalogueNames(t *testing.T) {
+	ingredients := []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "spring onion"}, Quantity: 4},
+		{Ingredient: Ingredient{Name: "courgette"}, Quantity: 1},
+		{Ingredient: Ingredient{Name: "zucchini"}, Quantity: 1, Component: "garnish"},
+	}
+	catalogue := IngredientCatalogue{
+		{Name: "spring onion", Aliases: []string{"green onion", "scallion"}},
+		{Name: "courgette", Aliases: []string{"zucchini"}},
+		{Name: "zucchini"},
+	}
+	steps := []Step{
+		{Description: "Scatter over the scallions."},
+		{Description: "Slice the green onions and the zucchini."},
+	}
+
+	LinkStepIngredients(steps, ingredients, catalogue)
+
+	// "zucchini" is a line of its own, so it isn't also the courgette.
+	want := [][]int{{0}, {0, 2}}
+	for i, w := range want {
+		if got := lines(steps[i].Ingredients); !reflect.DeepEqual(got, w) {
+			t.Errorf("step %d lines = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestDropMovedStepLinks(t *testing.T) {
+	before := []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "butter"}, Component: "pastry"},
+		{Ingredient: Ingredient{Name: "butter"}, Component: "filling"},
+		{Ingredient: Ingredient{Name: "flour"}},
+	}
+	after := []RecipeIngredient{
+		{Ingredient: Ingredient{Name: "Butter"}, Component: "pastry", Quantity: 120},
+		{Ingredient: Ingredient{Name: "flour"}},
+		{Ingredient: Ingredient{Name: "butter"}, Component: "filling"},
+	}
+	steps := []Step{
+		{Ingredients: []StepIngredient{{Line: 0}}},
+		{Ingredients: []StepIngredient{{Line: 0}, {Line: 2}}},
+		{Ingredients: []StepIngredient{{Line: 3}}},
+		{Ingredients: []StepIngredient{}},
+	}
+
+	DropMovedStepLinks(steps, before, after)
+
+	if len(steps[0].Ingredients) != 1 {
+		t.Errorf("a link to an unmoved line was dropped: %+v", steps[0].Ingredients)
+	}
+	if steps[1].Ingredients != nil || steps[2].Ingredients != nil {
+		t.Errorf("expected links to moved or missing lines cleared, got %+v and %+v", steps[1].Ingredients, steps[2].Ingredients)
+	}
+	if steps[3].Ingredients == nil {
+		t.Error("an explicitly empty link list was cleared")
+	}
+}
+
+func TestPluralVariants(t *testing.T) {
+	for name, want := range map[string]string{
+		"cherry":   "cherries",
+		"cherries": "cherry",
+		"tomatoes": "tomato",
+		"peaches":  "peach",
+		"egg":      "eggs",
+	} {
+		found := false
+		for _, v := range pluralVariants(name) {
+			found = found || v == want
+		}
+		if !found {
+			t.Errorf("pluralVariants(%q) = %v, missing %q", name, pluralVariants(name), want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package recipe
+
+import "strings"
+
+// StepSection is a run of consecutive steps under one heading ("Make the
+// dough", "Next day: shape and bake"). Steps without a section form runs with
+// an empty Title.
+type StepSection struct {
+	Title string `json:"title"`
+	Steps []Step `json:"steps"`
+}
+
+// NormalizeStepSections trims section titles, so " Dough" and "Dough" are the
+// same section.
+func NormalizeStepSections(steps []Step) {
+	for i := range steps {
+		steps[i].Section = strings.TrimSpace(steps[i].Section)
+	}
+}
+
+// GroupSteps splits steps into runs by section, keeping step order. A section
+// that comes back after another one starts a new run, as it does on the page.
+func GroupSteps(steps []Step) []StepSection {
+	var sections []StepSection
+	for _, s := range steps {
+		if len(sections) == 0 || sections[len(sections)-1].Title != s.Section {
+			sections = append(sections, StepSection{Title: s.Section})
+		}
+		sections[len(sections)-1].Steps = append(sections[len(sections)-1].Steps, s)
+	}
+	return sections
+}
+
+// KeepStepSections gives steps the sections stored had, for an update from a
+// client that doesn't know about sections and so sends none: when no step in
+// steps has a section, each takes the section of the stored step with the
+// same description, and a new or reworded step the section of the step
+// before it. An update that sends any section is taken as written.
+func KeepStepSections(steps, stored []Step) {
+	if HasStepSections(steps) || !HasStepSections(stored) {
+		return
+	}
+	used := make([]bool, len(stored))
+	section := ""
+	for i := range steps {
+		desc := strings.TrimSpace(steps[i].Description)
+		for j, s := range stored {
+			if !used[j] && strings.TrimSpace(s.Description) == desc {
+				used[j] = true
+				section = s.Section
+				break
+			}
+		}
+		steps[i].Section = section
+	}
+}
+
+// HasStepSections reports whether any step has a section.
+func HasStepSections(steps []Step) bool {
+	for _, s := range steps {
+		if s.Section != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package recipe
+
+import "testing"
+
+func TestGroupSteps(t *testing.T) {
+	steps := []Step{
+		{Order: 1, Description: "Mix the dough.", Section: "Dough"},
+		{Order: 2, Description: "Prove overnight.", Section: "Dough"},
+		{Order: 3, Description: "Shape.", Section: "Next day"},
+		{Order: 4, Description: "Bake.", Section: "Next day"},
+		{Order: 5, Description: "Serve warm."},
+	}
+
+	sections := GroupSteps(steps)
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %+v", sections)
+	}
+	if sections[0].Title != "Dough" || len(sections[0].Steps) != 2 {
+		t.Errorf("first section = %+v", sections[0])
+	}
+	if sections[1].Title != "Next day" || sections[1].Steps[0].Order != 3 {
+		t.Errorf("second section = %+v", sections[1])
+	}
+	if sections[2].Title != "" || len(sections[2].Steps) != 1 {
+		t.Errorf("trailing steps without a section = %+v", sections[2])
+	}
+}
+
+func TestNormalizeStepSections(t *testing.T) {
+	steps := []Step{{Section: "  Dough "}, {Section: "Dough"}}
+	NormalizeStepSections(steps)
+	if sections := GroupSteps(steps); len(sections) != 1 {
+		t.Errorf("expected whitespace-only differences to share a section, got %+v", sections)
+	}
+	if HasStepSections([]Step{{Description: "Stir."}}) {
+		t.Error("expected no sections")
+	}
+}
+
+func TestKeepStepSections(t *testing.T) {
+	stored := []Step{
+		{Description: "Mix the dough.", Section: "Dough"},
+		{Description: "Prove overnight.", Section: "Dough"},
+		{Description: "Shape.", Section: "Next day"},
+		{Description: "Bake.", Section: "Next day"},
+	}
+	steps := []Step{
+		{Description: "Mix the dough."},
+		{Description: "Prove overnight."},
+		{Description: "Knock back."},
+		{Description: "Shape."},
+		{Description: "Bake until golden."},
+	}
+
+	KeepStepSections(steps, stored)
+
+	want := []string{"Dough", "Dough", "Dough", "Next day", "Next day"}
+	for i, w := range want {
+		if steps[i].Section != w {
+			t.Errorf("step %d section = %q, want %q", i, steps[i].Section, w)
+		}
+	}
+
+	given := []Step{{Description: "Mix the dough.", Section: "Start"}, {Description: "Shape."}}
+	KeepStepSections(given, stored)
+	if given[0].Section != "Start" || given[1].Section != "" {
+		t.Errorf("sections sent with the update were overridden: %+v", given)
+	}
+}
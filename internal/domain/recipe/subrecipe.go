@@ -0,0 +1,56 @@
+package recipe
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Units a sub-recipe line can be measured in.
+const (
+	SubRecipeUnitServings = "servings"
+	SubRecipeUnitBatches  = "batches"
+)
+
+// SubRecipe is the recipe a RecipeIngredient line refers to.
+type SubRecipe struct {
+	UUID uuid.UUID `json:"uuid"`
+
+	// Recipe is the sub-recipe in full, with its ingredient quantities scaled
+	// to the amount this line calls for. Only set when a read asks for
+	// sub-recipes to be expanded.
+	Recipe *Recipe `json:"recipe,omitempty"`
+}
+
+// NormalizeSubRecipeUnit maps the accepted spellings of a sub-recipe unit to
+// its canonical form, and reports whether it was one at all. An empty unit
+// means batches.
+func NormalizeSubRecipeUnit(unit string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "", "batch", "batches":
+		return SubRecipeUnitBatches, true
+	case "serving", "servings", "portion", "portions":
+		return SubRecipeUnitServings, true
+	}
+	return "", false
+}
+
+// Batches is how many times over the sub-recipe, which makes servings
+// portions, has to be made for this line. A recipe with no recorded servings
+// is treated as one serving per batch.
+func (ri RecipeIngredient) Batches(servings int16) float64 {
+	if ri.Unit.Name == SubRecipeUnitServings && servings > 0 {
+		return ri.Quantity / float64(servings)
+	}
+	return ri.Quantity
+}
+
+// Scaled returns a copy of r with every ingredient quantity multiplied by
+// factor. Sub-recipe lines scale too, since they are quantities of their own.
+func (r Recipe) Scaled(factor float64) Recipe {
+	r.Ingredients = append([]RecipeIngredient(nil), r.Ingredients...)
+	for i := range r.Ingredients {
+		r.Ingredients[i].Quantity *= factor
+	}
+	return r
+}
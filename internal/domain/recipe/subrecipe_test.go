@@ -0,0 +1,44 @@
+package recipe
+
+import "testing"
+
+func TestNormalizeSubRecipeUnit(t *testing.T) {
+	for in, want := range map[string]string{
+		"":         SubRecipeUnitBatches,
+		"Batch":    SubRecipeUnitBatches,
+		"servings": SubRecipeUnitServings,
+		"portion":  SubRecipeUnitServings,
+	} {
+		if got, ok := NormalizeSubRecipeUnit(in); !ok || got != want {
+			t.Errorf("NormalizeSubRecipeUnit(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := NormalizeSubRecipeUnit("grams"); ok {
+		t.Error("grams should not be accepted for a sub-recipe")
+	}
+}
+
+func TestBatches(t *testing.T) {
+	servings := RecipeIngredient{Quantity: 2, Unit: Unit{Name: SubRecipeUnitServings}}
+	if got := servings.Batches(4); got != 0.5 {
+		t.Errorf("2 servings of a 4-serving recipe = %v batches, want 0.5", got)
+	}
+	if got := servings.Batches(0); got != 2 {
+		t.Errorf("without servings, expected one serving per batch, got %v", got)
+	}
+	batches := RecipeIngredient{Quantity: 1.5, Unit: Unit{Name: SubRecipeUnitBatches}}
+	if got := batches.Batches(4); got != 1.5 {
+		t.Errorf("batches = %v, want 1.5", got)
+	}
+}
+
+func TestScaled(t *testing.T) {
+	r := Recipe{Ingredients: []RecipeIngredient{{Ingredient: Ingredient{Name: "flour"}, Quantity: 500}}}
+	s := r.Scaled(0.5)
+	if s.Ingredients[0].Quantity != 250 {
+		t.Errorf("scaled quantity = %v, want 250", s.Ingredients[0].Quantity)
+	}
+	if r.Ingredients[0].Quantity != 500 {
+		t.Error("scaling modified the original")
+	}
+}
@@ -0,0 +1,240 @@
+package recipe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LabelType is a kind of label ("course", "cuisine"), with the names allowed
+// under it. Multiple says whether a recipe usually carries more than one: it
+// is one course, but can be several cuisines.
+type LabelType struct {
+	Name        string            `json:"name"`
+	DisplayName string            `json:"displayName"`
+	Description string            `json:"description"`
+	Multiple    bool              `json:"multiple"`
+	Position    int               `json:"position"`
+	Labels      []LabelDefinition `json:"labels"`
+	CreatedAt   time.Time         `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time         `json:"updatedAt,omitempty"`
+}
+
+// LabelDefinition is a label the taxonomy allows. Aliases are other
+// spellings that resolve to it ("veggie" for vegetarian). Uses counts the
+// recipes carrying it, on reads.
+type LabelDefinition struct {
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+	Description string    `json:"description"`
+	Aliases     []string  `json:"aliases"`
+	Uses        int       `json:"uses"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+}
+
+// LabelChange is what renaming or merging a label did: From is the label as
+// it was, Label what recipes carry now, and Recipes those that carried From.
+// On a DryRun nothing was changed, and Label is what it would become.
+type LabelChange struct {
+	From    Label           `json:"from"`
+	Label   LabelDefinition `json:"label"`
+	Recipes []RecipeRef     `json:"recipes"`
+	DryRun  bool            `json:"dryRun"`
+}
+
+// Taxonomy is every label type, in display order, with its labels.
+type Taxonomy []LabelType
+
+// NormalizeLabelName puts a label, type or alias name into the stored
+// lowercase snake_case form: "Gluten-free" becomes "gluten_free".
+func NormalizeLabelName(name string) string {
+	return snakeCase(name)
+}
+
+// DefaultDisplayName is the display name a label gets when none is given:
+// "gluten_free" becomes "Gluten Free".
+func DefaultDisplayName(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// ParseLabel reads a label written "type:name", as the admin endpoints take
+// them.
+func ParseLabel(s string) (Label, error) {
+	typ, name, ok := strings.Cut(s, ":")
+	typ, name = NormalizeLabelName(typ), NormalizeLabelName(name)
+	if !ok || typ == "" || name == "" {
+		return Label{}, InvalidLabelError{Reason: fmt.Sprintf("label %q should be written type:name", s)}
+	}
+	return Label{Type: typ, Name: name}, nil
+}
+
+// Type finds a label type by name.
+func (t Taxonomy) Type(name string) (LabelType, bool) {
+	name = NormalizeLabelName(name)
+	for _, lt := range t {
+		if lt.Name == name {
+			return lt, true
+		}
+	}
+	return LabelType{}, false
+}
+
+// TypeNames lists the label types in display order.
+func (t Taxonomy) TypeNames() []string {
+	names := make([]string, len(t))
+	for i, lt := range t {
+		names[i] = lt.Name
+	}
+	return names
+}
+
+// Names lists the labels allowed under typ, sorted.
+func (t Taxonomy) Names(typ string) []string {
+	lt, _ := t.Type(typ)
+	names := make([]string, len(lt.Labels))
+	for i, d := range lt.Labels {
+		names[i] = d.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup finds the label typ:name, by its name or one of its aliases.
+func (t Taxonomy) Lookup(typ, name string) (LabelDefinition, bool) {
+	lt, ok := t.Type(typ)
+	if !ok {
+		return LabelDefinition{}, false
+	}
+	name = NormalizeLabelName(name)
+	for _, d := range lt.Labels {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	for _, d := range lt.Labels {
+		for _, a := range d.Aliases {
+			if a == name {
+				return d, true
+			}
+		}
+	}
+	return LabelDefinition{}, false
+}
+
+// ResolveLabels maps each of labels to its canonical name, following
+// aliases, and drops repeats. A type or name the taxonomy doesn't have is an
+// InvalidLabelError listing what it does.
+func (t Taxonomy) ResolveLabels(labels []Label) ([]Label, error) {
+	if labels == nil {
+		return nil, nil
+	}
+	out := []Label{}
+	seen := map[string]bool{}
+	for _, l := range labels {
+		if _, ok := t.Type(l.Type); !ok {
+			return nil, InvalidLabelError{Reason: fmt.Sprintf("unknown label type %q (one of %s)", l.Type, strings.Join(t.TypeNames(), ", "))}
+		}
+		d, ok := t.Lookup(l.Type, l.Name)
+		if !ok {
+			return nil, InvalidLabelError{Reason: fmt.Sprintf("unknown %s label %q (one of %s)", NormalizeLabelName(l.Type), l.Name, strings.Join(t.Names(l.Type), ", "))}
+		}
+		key := d.Type + ":" + d.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, Label{Type: d.Type, Name: d.Name})
+	}
+	return out, nil
+}
+
+// NormalizeLabelType puts lt's name into the stored form and fills in a
+// missing display name.
+func NormalizeLabelType(lt *LabelType) {
+	lt.Name = NormalizeLabelName(lt.Name)
+	lt.DisplayName = strings.TrimSpace(lt.DisplayName)
+	if lt.DisplayName == "" {
+		lt.DisplayName = DefaultDisplayName(lt.Name)
+	}
+	lt.Description = strings.TrimSpace(lt.Description)
+}
+
+// ValidateLabelType checks a normalised label type about to be saved.
+func ValidateLabelType(lt LabelType) error {
+	if lt.Name == "" {
+		return InvalidLabelError{Reason: "label type name is required"}
+	}
+	if lt.Name[0] >= '0' && lt.Name[0] <= '9' {
+		return InvalidLabelError{Reason: "label type name must start with a letter"}
+	}
+	return nil
+}
+
+// NormalizeLabelDefinition puts d's type, name and aliases into the stored
+// form, dropping blank, repeated and self-referring aliases, and fills in a
+// missing display name. Aliases is never nil afterwards.
+func NormalizeLabelDefinition(d *LabelDefinition) {
+	d.Type = NormalizeLabelName(d.Type)
+	d.Name = NormalizeLabelName(d.Name)
+	d.DisplayName = strings.TrimSpace(d.DisplayName)
+	if d.DisplayName == "" {
+		d.DisplayName = DefaultDisplayName(d.Name)
+	}
+	d.Description = strings.TrimSpace(d.Description)
+
+	aliases := []string{}
+	seen := map[string]bool{d.Name: true}
+	for _, a := range d.Aliases {
+		a = NormalizeLabelName(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		aliases = append(aliases, a)
+	}
+	sort.Strings(aliases)
+	d.Aliases = aliases
+}
+
+// ValidateLabelDefinition checks a normalised label about to be saved into
+// t: its type must exist, and none of its aliases may already be another
+// label's name or alias within the type, or saving recipes would be
+// ambiguous.
+func (t Taxonomy) ValidateLabelDefinition(d LabelDefinition) error {
+	if d.Name == "" {
+		return InvalidLabelError{Reason: "label name is required"}
+	}
+	lt, ok := t.Type(d.Type)
+	if !ok {
+		return InvalidLabelError{Reason: fmt.Sprintf("unknown label type %q (one of %s)", d.Type, strings.Join(t.TypeNames(), ", "))}
+	}
+	for _, other := range lt.Labels {
+		if other.Name == d.Name {
+			continue
+		}
+		for _, spelling := range append([]string{d.Name}, d.Aliases...) {
+			if spelling == other.Name || containsString(other.Aliases, spelling) {
+				return InvalidLabelError{Reason: fmt.Sprintf("%q already means %s:%s", spelling, other.Type, other.Name)}
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
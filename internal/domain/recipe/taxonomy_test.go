@@ -0,0 +1,87 @@
+package recipe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var testTaxonomy = Taxonomy{
+	{Name: "course", Labels: []LabelDefinition{
+		{Type: "course", Name: "main", Aliases: []string{"dinner", "mains"}},
+		{Type: "course", Name: "side"},
+	}},
+	{Name: "diet", Labels: []LabelDefinition{
+		{Type: "diet", Name: "vegetarian", Aliases: []string{"veggie"}},
+	}},
+}
+
+func TestTaxonomy_ResolveLabels(t *testing.T) {
+	got, err := testTaxonomy.ResolveLabels([]Label{
+		{Type: "Course", Name: "Dinner"},
+		{Type: "course", Name: "main"},
+		{Type: "diet", Name: "veggie"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveLabels() error = %v", err)
+	}
+	want := []Label{{Type: "course", Name: "main"}, {Type: "diet", Name: "vegetarian"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestTaxonomy_ResolveLabels_Unknown(t *testing.T) {
+	for _, l := range []Label{{Type: "season", Name: "winter"}, {Type: "course", Name: "elevenses"}} {
+		if _, err := testTaxonomy.ResolveLabels([]Label{l}); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("ResolveLabels(%v) = %v, want ErrInvalidLabel", l, err)
+		}
+	}
+}
+
+func TestNormalizeLabelDefinition(t *testing.T) {
+	d := LabelDefinition{Type: " Course ", Name: "Slow-cooked", Aliases: []string{"Slow Cooker", "", "slow_cooked", "slow_cooker"}}
+	NormalizeLabelDefinition(&d)
+	want := LabelDefinition{Type: "course", Name: "slow_cooked", DisplayName: "Slow Cooked", Aliases: []string{"slow_cooker"}}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("NormalizeLabelDefinition() = %+v, want %+v", d, want)
+	}
+}
+
+func TestTaxonomy_ValidateLabelDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		d    LabelDefinition
+		ok   bool
+	}{
+		{"new label", LabelDefinition{Type: "course", Name: "dessert"}, true},
+		{"existing label, own aliases", LabelDefinition{Type: "course", Name: "main", Aliases: []string{"dinner", "supper"}}, true},
+		{"unknown type", LabelDefinition{Type: "season", Name: "winter"}, false},
+		{"alias taken", LabelDefinition{Type: "course", Name: "side", Aliases: []string{"mains"}}, false},
+		{"name is an alias", LabelDefinition{Type: "course", Name: "dinner"}, false},
+		{"same alias in another type", LabelDefinition{Type: "diet", Name: "vegan", Aliases: []string{"dinner"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testTaxonomy.ValidateLabelDefinition(tt.d)
+			if tt.ok && err != nil {
+				t.Errorf("ValidateLabelDefinition() = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidLabel) {
+				t.Errorf("ValidateLabelDefinition() = %v, want ErrInvalidLabel", err)
+			}
+		})
+	}
+}
+
+func TestParseLabel(t *testing.T) {
+	got, err := ParseLabel("Course:Side Dish")
+	if err != nil || got != (Label{Type: "course", Name: "side_dish"}) {
+		t.Errorf("ParseLabel() = %v, %v", got, err)
+	}
+	for _, s := range []string{"main", "course:", ":main"} {
+		if _, err := ParseLabel(s); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("ParseLabel(%q) = %v, want ErrInvalidLabel", s, err)
+		}
+	}
+}
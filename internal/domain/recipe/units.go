@@ -0,0 +1,161 @@
+package recipe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UnitDimension is what a unit measures, so quantities in units of the same
+// dimension can be converted and added up.
+type UnitDimension string
+
+const (
+	DimensionMass   UnitDimension = "mass"
+	DimensionVolume UnitDimension = "volume"
+	DimensionCount  UnitDimension = "count"
+)
+
+// UnitRegistry is every canonical unit, ordered by name. Recipe lines resolve
+// their unit text through it; text that resolves to nothing is kept on the
+// line and queued for review rather than becoming a unit of its own.
+type UnitRegistry []Unit
+
+// UnitReview is unit text that resolved to no unit, with how many recipe lines
+// and recipes use it.
+type UnitReview struct {
+	Name      string    `json:"name"`
+	Lines     int       `json:"lines"`
+	Recipes   int       `json:"recipes"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// NormalizeUnitName trims name, collapses the whitespace inside it and drops
+// a trailing full stop, so "Tbsp." reads as "Tbsp". Case is kept: "T" is a
+// tablespoon and "t" a teaspoon.
+func NormalizeUnitName(name string) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(name), " "), ".")
+}
+
+// UnitNameForms lists the lowercase spellings name matches: itself and its
+// singular and plural forms, so "Tablespoons" finds "tablespoon".
+func UnitNameForms(name string) []string {
+	key := strings.ToLower(NormalizeUnitName(name))
+	if key == "" {
+		return nil
+	}
+	var forms []string
+	for _, f := range pluralVariants(key) {
+		if !containsString(forms, f) {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
+
+// UnitSpellings lists the lowercase forms of u's name, abbreviation and
+// aliases: the unit text u is written as.
+func UnitSpellings(u Unit) []string {
+	var forms []string
+	for _, n := range append([]string{u.Name, u.Abbreviation}, u.Aliases...) {
+		for _, f := range UnitNameForms(n) {
+			if !containsString(forms, f) {
+				forms = append(forms, f)
+			}
+		}
+	}
+	return forms
+}
+
+// NormalizeUnit tidies a unit before it's saved: its name lowercase, its
+// aliases trimmed with blanks, repeats and the unit's own name dropped. Aliases
+// keep their case, since an exact match is what tells "T" from "t".
+func NormalizeUnit(u Unit) Unit {
+	u.Name = strings.ToLower(NormalizeUnitName(u.Name))
+	u.Abbreviation = NormalizeUnitName(u.Abbreviation)
+	u.Dimension = UnitDimension(strings.ToLower(strings.TrimSpace(string(u.Dimension))))
+	aliases := []string{}
+	for _, a := range u.Aliases {
+		a = NormalizeUnitName(a)
+		if a == "" || a == u.Name || containsString(aliases, a) {
+			continue
+		}
+		aliases = append(aliases, a)
+	}
+	sort.Strings(aliases)
+	u.Aliases = aliases
+	return u
+}
+
+// ValidateUnit checks a normalized unit has a name and a known dimension.
+func ValidateUnit(u Unit) error {
+	if u.Name == "" {
+		return InvalidUnitError{Reason: "name is required"}
+	}
+	switch u.Dimension {
+	case DimensionMass, DimensionVolume, DimensionCount:
+		return nil
+	}
+	return InvalidUnitError{Reason: fmt.Sprintf("dimension must be mass, volume or count, not %q", u.Dimension)}
+}
+
+// Resolve finds the unit text refers to, as saves do: a unit whose name or
+// alias is written exactly as text first, then one whose name is a form of
+// it, then one with an alias that is. Ties go to the oldest unit.
+func (reg UnitRegistry) Resolve(text string) (Unit, bool) {
+	text = NormalizeUnitName(text)
+	forms := UnitNameForms(text)
+	if len(forms) == 0 {
+		return Unit{}, false
+	}
+
+	const noMatch = 3
+	best, bestRank := -1, noMatch
+	for i, u := range reg {
+		rank := noMatch
+		switch {
+		case u.Name == text || containsString(u.Aliases, text):
+			rank = 0
+		case containsString(forms, strings.ToLower(u.Name)):
+			rank = 1
+		default:
+			for _, a := range u.Aliases {
+				if containsString(forms, strings.ToLower(a)) {
+					rank = 2
+					break
+				}
+			}
+		}
+		if rank < bestRank || (rank == bestRank && rank < noMatch && u.CreatedAt.Before(reg[best].CreatedAt)) {
+			best, bestRank = i, rank
+		}
+	}
+	if best < 0 {
+		return Unit{}, false
+	}
+	return reg[best], true
+}
+
+// Find returns the unit named exactly name.
+func (reg UnitRegistry) Find(name string) (Unit, bool) {
+	name = strings.ToLower(NormalizeUnitName(name))
+	for _, u := range reg {
+		if u.Name == name {
+			return u, true
+		}
+	}
+	return Unit{}, false
+}
+
+// CheckNames makes sure none of names already means a unit other than self,
+// so giving them to self leaves every spelling meaning one unit.
+func (reg UnitRegistry) CheckNames(self string, names ...string) error {
+	for _, n := range names {
+		if other, ok := reg.Resolve(n); ok && other.Name != self {
+			return InvalidUnitError{Reason: fmt.Sprintf("%q already means %q; merge the units instead", n, other.Name)}
+		}
+	}
+	return nil
+}
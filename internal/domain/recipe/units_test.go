@@ -0,0 +1,45 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitRegistry_Resolve(t *testing.T) {
+	now := time.Now()
+	reg := UnitRegistry{
+		{Name: "gram", Dimension: DimensionMass, Aliases: []string{"g"}, CreatedAt: now},
+		{Name: "tablespoon", Dimension: DimensionVolume, Aliases: []string{"T", "tbs", "tbsp"}, CreatedAt: now},
+		{Name: "teaspoon", Dimension: DimensionVolume, Aliases: []string{"t", "tsp"}, CreatedAt: now},
+	}
+
+	for text, want := range map[string]string{
+		"tablespoons": "tablespoon",
+		"Tbsp.":       "tablespoon",
+		"T":           "tablespoon",
+		"t":           "teaspoon",
+		"TSP":         "teaspoon",
+		"grams":       "gram",
+		" G ":         "gram",
+		"glug":        "",
+		"":            "",
+	} {
+		got, _ := reg.Resolve(text)
+		if got.Name != want {
+			t.Errorf("Resolve(%q) = %q, want %q", text, got.Name, want)
+		}
+	}
+}
+
+func TestNormalizeUnit(t *testing.T) {
+	u := NormalizeUnit(Unit{Name: " Tablespoon ", Dimension: "Volume", Aliases: []string{"T", "tbsp.", "tablespoon", "T", " "}})
+	if u.Name != "tablespoon" || u.Dimension != DimensionVolume {
+		t.Errorf("got %+v", u)
+	}
+	if len(u.Aliases) != 2 || u.Aliases[0] != "T" || u.Aliases[1] != "tbsp" {
+		t.Errorf("aliases = %q", u.Aliases)
+	}
+	if err := ValidateUnit(Unit{Name: "glug", Dimension: "weight"}); err == nil {
+		t.Error("expected an unknown dimension to be rejected")
+	}
+}
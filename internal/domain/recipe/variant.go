@@ -0,0 +1,73 @@
+package recipe
+
+import "github.com/google/uuid"
+
+// RecipeRef points at another recipe by ID and name, enough to link to it.
+type RecipeRef struct {
+	UUID uuid.UUID `json:"uuid"`
+	Name string    `json:"name"`
+}
+
+// Fork derives a variant from parent. Anything set in changes replaces the
+// parent's value; anything left empty is copied. Ingredients, steps, labels
+// and equipment are replaced wholesale when given, as with an update. The result is a new,
+// unsaved recipe linked back to parent.
+func Fork(parent, changes Recipe) Recipe {
+	v := Recipe{
+		Name:        parent.Name,
+		Description: parent.Description,
+		CookTime:    parent.CookTime,
+		PrepTime:    parent.PrepTime,
+		Servings:    parent.Servings,
+		MainPhoto:   parent.MainPhoto,
+		Url:         parent.Url,
+		Steps:       append([]Step(nil), parent.Steps...),
+		Ingredients: append([]RecipeIngredient(nil), parent.Ingredients...),
+		Labels:      append([]Label(nil), parent.Labels...),
+		Equipment:   append([]string(nil), parent.Equipment...),
+		Parent:      &RecipeRef{UUID: parent.UUID, Name: parent.Name},
+	}
+
+	if changes.Name != "" {
+		v.Name = changes.Name
+	}
+	if changes.Description != "" {
+		v.Description = changes.Description
+	}
+	if changes.CookTime > 0 {
+		v.CookTime = changes.CookTime
+	}
+	if changes.PrepTime > 0 {
+		v.PrepTime = changes.PrepTime
+	}
+	if changes.Servings > 0 {
+		v.Servings = changes.Servings
+	}
+	if changes.MainPhoto != nil {
+		v.MainPhoto = changes.MainPhoto
+	}
+	if changes.Url != "" {
+		v.Url = changes.Url
+	}
+	if len(changes.Steps) > 0 {
+		v.Steps = changes.Steps
+		// New steps may need different kit; suggest afresh unless given.
+		v.Equipment = nil
+	}
+	if len(changes.Ingredients) > 0 {
+		v.Ingredients = changes.Ingredients
+		// The parent's steps link to the parent's lines; infer afresh.
+		if len(changes.Steps) == 0 {
+			for i := range v.Steps {
+				v.Steps[i].Ingredients = nil
+			}
+		}
+	}
+	if changes.Labels != nil {
+		v.Labels = changes.Labels
+	}
+	if changes.Equipment != nil {
+		v.Equipment = changes.Equipment
+	}
+	return v
+}
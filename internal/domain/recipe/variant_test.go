@@ -0,0 +1,88 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFork(t *testing.T) {
+	parent := Recipe{
+		UUID:     uuid.MustParse("aaaaaaaa-0000-0000-0000-000000000001"),
+		Name:     "Dhal",
+		CookTime: 30,
+		Servings: 4,
+		Ingredients: []RecipeIngredient{
+			{Ingredient: Ingredient{Name: "lentils"}, Quantity: 250},
+			{Ingredient: Ingredient{Name: "ghee"}, Quantity: 2},
+		},
+		Steps:  []Step{{Order: 1, Description: "Simmer."}},
+		Labels: []Label{{Type: "cuisine", Name: "indian"}},
+	}
+
+	v := Fork(parent, Recipe{
+		Name: "Vegan dhal",
+		Ingredients: []RecipeIngredient{
+			{Ingredient: Ingredient{Name: "lentils"}, Quantity: 250},
+			{Ingredient: Ingredient{Name: "coconut oil"}, Quantity: 2},
+		},
+	})
+
+	if v.Name != "Vegan dhal" || v.CookTime != 30 || v.Servings != 4 {
+		t.Errorf("unexpected fields: %+v", v)
+	}
+	if v.Parent == nil || v.Parent.UUID != parent.UUID || v.Parent.Name != "Dhal" {
+		t.Errorf("parent = %+v", v.Parent)
+	}
+	if len(v.Steps) != 1 || len(v.Labels) != 1 {
+		t.Errorf("expected steps and labels copied, got %+v / %+v", v.Steps, v.Labels)
+	}
+	if v.UUID != uuid.Nil {
+		t.Errorf("variant should not reuse the parent's ID")
+	}
+
+	d := Compare(parent, v)
+	if len(d.IngredientsAdded) != 1 || d.IngredientsAdded[0].Ingredient.Name != "coconut oil" {
+		t.Errorf("ingredientsAdded = %+v", d.IngredientsAdded)
+	}
+	if len(d.IngredientsRemoved) != 1 || d.IngredientsRemoved[0].Ingredient.Name != "ghee" {
+		t.Errorf("ingredientsRemoved = %+v", d.IngredientsRemoved)
+	}
+}
+
+func TestFork_DoesNotShareSlices(t *testing.T) {
+	parent := Recipe{Name: "Dhal", Steps: []Step{{Order: 1, Description: "Simmer."}}}
+	v := Fork(parent, Recipe{Name: "Dhal II"})
+	v.Steps[0].Description = "Boil."
+	if parent.Steps[0].Description != "Simmer." {
+		t.Error("editing the variant changed the parent")
+	}
+}
+
+func TestFork_NewIngredientsDropStepLinks(t *testing.T) {
+	parent := Recipe{
+		Ingredients: []RecipeIngredient{{Ingredient: Ingredient{Name: "lentils"}}},
+		Steps:       []Step{{Order: 1, Description: "Simmer.", Ingredients: []StepIngredient{{Line: 0}}}},
+	}
+	v := Fork(parent, Recipe{Ingredients: []RecipeIngredient{{Ingredient: Ingredient{Name: "split peas"}}}})
+	if v.Steps[0].Ingredients != nil {
+		t.Errorf("expected the parent's step links to be cleared, got %+v", v.Steps[0].Ingredients)
+	}
+	if parent.Steps[0].Ingredients == nil {
+		t.Error("forking changed the parent's steps")
+	}
+}
+
+func TestFork_Equipment(t *testing.T) {
+	parent := Recipe{
+		Steps:     []Step{{Order: 1, Description: "Cook in the slow cooker."}},
+		Equipment: []string{"slow_cooker"},
+	}
+
+	if v := Fork(parent, Recipe{Name: "Dhal II"}); len(v.Equipment) != 1 || v.Equipment[0] != "slow_cooker" {
+		t.Errorf("expected the parent's equipment to be copied, got %v", v.Equipment)
+	}
+	if v := Fork(parent, Recipe{Steps: []Step{{Order: 1, Description: "Simmer on the hob."}}}); v.Equipment != nil {
+		t.Errorf("expected new steps to leave equipment to be suggested, got %v", v.Equipment)
+	}
+}
@@ -25,7 +25,12 @@ var (
 
 var uuidPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
 
+// sharePattern matches public share links. Their tokens are credentials as
+// well as unbounded, so they must never end up in a label.
+var sharePattern = regexp.MustCompile(`^/share/[^/]+`)
+
 func normalizePath(path string) string {
+	path = sharePattern.ReplaceAllString(path, "/share/{token}")
 	return uuidPattern.ReplaceAllString(path, "{id}")
 }
 
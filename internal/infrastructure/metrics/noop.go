@@ -10,6 +10,7 @@ func (NoopRecipeProbe) RecipeUpdated(string)           {}
 func (NoopRecipeProbe) RecipeArchived(string)          {}
 func (NoopRecipeProbe) RecipeRestored(string)          {}
 func (NoopRecipeProbe) MealPlanChanged(string, string) {}
+func (NoopRecipeProbe) CookLogged(string, int16)       {}
 func (NoopRecipeProbe) RecipeSearched(int)             {}
 func (NoopRecipeProbe) RecipeError(string, error)      {}
 
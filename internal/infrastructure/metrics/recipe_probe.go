@@ -17,6 +17,11 @@ var (
 		Help: "Total meal plan changes by action.",
 	}, []string{"action"})
 
+	recipeCooks = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bluerbook_recipe_cooks_total",
+		Help: "Total cooks recorded in the cook log.",
+	})
+
 	recipeSearchResults = promauto.NewHistogram(prometheus.HistogramOpts{
 		Name:    "bluerbook_recipe_search_results",
 		Help:    "Distribution of recipe search result counts.",
@@ -63,6 +68,11 @@ func (p *RecipeProbe) MealPlanChanged(action string, recipeID string) {
 	p.logger.Info().Str("probe", "recipe").Str("action", action).Str("recipe_id", recipeID).Msg("meal plan changed")
 }
 
+func (p *RecipeProbe) CookLogged(recipeID string, rating int16) {
+	recipeCooks.Inc()
+	p.logger.Info().Str("probe", "recipe").Str("recipe_id", recipeID).Int16("rating", rating).Msg("cook logged")
+}
+
 func (p *RecipeProbe) RecipeSearched(resultCount int) {
 	recipeSearchResults.Observe(float64(resultCount))
 	p.logger.Debug().Str("probe", "recipe").Int("result_count", resultCount).Msg("recipe search performed")
@@ -0,0 +1,88 @@
+// Package nutrition reads nutrient tables in CSV form, including the one
+// bundled with the app that seed-nutrition loads by default.
+package nutrition
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+)
+
+// Bundled is a starter table of common ingredients, per 100 g, from UK
+// composition data rounded to a tenth of a gram.
+//
+//go:embed nutrients.csv
+var Bundled []byte
+
+// columns are the CSV's header, in order. density (g/ml) and piece_weight
+// (g) may be left blank.
+var columns = []string{"ingredient", "calories", "protein", "fat", "carbs", "fibre", "salt", "density", "piece_weight"}
+
+// ParseCSV reads a nutrient table. The header must name the columns above, in
+// that order; a bad row is reported by line number.
+func ParseCSV(r io.Reader) ([]recipe.NutrientProfile, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(columns)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	for i, col := range columns {
+		if strings.ToLower(strings.TrimSpace(header[i])) != col {
+			return nil, fmt.Errorf("header column %d is %q, want %q", i+1, header[i], col)
+		}
+	}
+
+	var profiles []recipe.NutrientProfile
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return profiles, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		values := make([]float64, len(columns)-1)
+		for i, field := range record[1:] {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				if i < 6 {
+					return nil, fmt.Errorf("line %d: %s is required", line, columns[i+1])
+				}
+				continue
+			}
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("line %d: %s must be a non-negative number, not %q", line, columns[i+1], field)
+			}
+			values[i] = v
+		}
+
+		name := strings.ToLower(strings.TrimSpace(record[0]))
+		if name == "" {
+			return nil, fmt.Errorf("line %d: ingredient is required", line)
+		}
+		profiles = append(profiles, recipe.NutrientProfile{
+			Ingredient: name,
+			Per100g: recipe.Nutrients{
+				Calories: values[0],
+				Protein:  values[1],
+				Fat:      values[2],
+				Carbs:    values[3],
+				Fibre:    values[4],
+				Salt:     values[5],
+			},
+			Density:     values[6],
+			PieceWeight: values[7],
+		})
+	}
+}
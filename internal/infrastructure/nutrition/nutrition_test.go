@@ -0,0 +1,46 @@
+package nutrition
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV_Bundled(t *testing.T) {
+	profiles, err := ParseCSV(bytes.NewReader(Bundled))
+	if err != nil {
+		t.Fatalf("bundled table doesn't parse: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, p := range profiles {
+		if seen[p.Ingredient] {
+			t.Errorf("%q appears twice", p.Ingredient)
+		}
+		seen[p.Ingredient] = true
+	}
+	if !seen["red lentils"] {
+		t.Error("expected red lentils in the bundled table")
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	const header = "ingredient,calories,protein,fat,carbs,fibre,salt,density,piece_weight\n"
+
+	profiles, err := ParseCSV(strings.NewReader(header + "Onion,40,1.1,0.1,9.3,1.7,0,,150\n"))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Ingredient != "onion" || profiles[0].PieceWeight != 150 || profiles[0].Density != 0 {
+		t.Errorf("unexpected profiles: %+v", profiles)
+	}
+
+	for name, body := range map[string]string{
+		"missing nutrient": header + "onion,40,,0.1,9.3,1.7,0,,\n",
+		"negative":         header + "onion,-40,1.1,0.1,9.3,1.7,0,,\n",
+		"wrong header":     "name,kcal,protein,fat,carbs,fibre,salt,density,piece_weight\n",
+	} {
+		if _, err := ParseCSV(strings.NewReader(body)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
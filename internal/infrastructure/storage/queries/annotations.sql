@@ -0,0 +1,25 @@
+-- name: CreateRecipeAnnotation :one
+INSERT INTO recipe_annotations (uuid, recipe_id, step_order, note)
+VALUES (@uuid, @recipe_id, sqlc.narg('step_order'), @note)
+RETURNING *;
+
+-- name: ListRecipeAnnotations :many
+-- Recipe-wide notes first, then by step; oldest first within each.
+SELECT * FROM recipe_annotations
+WHERE recipe_id = @recipe_id
+ORDER BY step_order ASC NULLS FIRST, created_at ASC;
+
+-- name: UpdateRecipeAnnotation :one
+UPDATE recipe_annotations SET
+    note = @note,
+    updated_at = now()
+WHERE uuid = @uuid AND recipe_id = @recipe_id
+RETURNING *;
+
+-- name: MoveRecipeAnnotation :exec
+-- Follows a step note to where its step went; NULL puts it on the recipe.
+UPDATE recipe_annotations SET step_order = sqlc.narg('step_order')
+WHERE uuid = @uuid;
+
+-- name: DeleteRecipeAnnotation :execrows
+DELETE FROM recipe_annotations WHERE uuid = @uuid AND recipe_id = @recipe_id;
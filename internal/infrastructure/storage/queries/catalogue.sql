@@ -0,0 +1,79 @@
+-- name: ListIngredientCatalogue :many
+SELECT i.uuid, i.name, i.aliases, i.pantry_only, i.created_at, i.updated_at,
+       (SELECT COUNT(DISTINCT ri.recipe_id) FROM recipe_ingredient ri WHERE ri.ingredient_id = i.uuid) AS uses,
+       EXISTS (SELECT 1 FROM pantry_items p WHERE p.ingredient_id = i.uuid) AS in_pantry
+FROM ingredients i
+ORDER BY lower(i.name), i.created_at, i.uuid;
+
+-- name: GetIngredientByExactName :one
+SELECT * FROM ingredients WHERE name = $1;
+
+-- name: SetIngredientAliases :execrows
+UPDATE ingredients SET aliases = $2, updated_at = now() WHERE name = $1;
+
+-- name: RenameIngredient :execrows
+UPDATE ingredients
+SET name = sqlc.arg(to_name)::varchar, aliases = sqlc.arg(aliases)::text[], updated_at = now()
+WHERE name = sqlc.arg(from_name)::varchar;
+
+-- name: RepointRecipeIngredients :exec
+-- Lines are keyed by position, so moving them to another ingredient can't
+-- collide, even in a recipe that already uses both. Into stops being
+-- pantry-only if it gains any.
+WITH linked AS (
+  UPDATE ingredients SET pantry_only = false
+  WHERE uuid = sqlc.arg(into_id)::uuid AND pantry_only
+    AND EXISTS (SELECT 1 FROM recipe_ingredient WHERE ingredient_id = sqlc.arg(from_id)::uuid)
+)
+UPDATE recipe_ingredient SET ingredient_id = sqlc.arg(into_id)::uuid, updated_at = now()
+WHERE ingredient_id = sqlc.arg(from_id)::uuid;
+
+-- name: RepointPantryItem :exec
+-- The pantry is presence-only: if into is already there it stays, keeping the
+-- earlier of the two added_at dates.
+INSERT INTO pantry_items (ingredient_id, added_at)
+SELECT sqlc.arg(into_id)::uuid, p.added_at FROM pantry_items p WHERE p.ingredient_id = sqlc.arg(from_id)::uuid
+ON CONFLICT (ingredient_id) DO UPDATE SET added_at = LEAST(pantry_items.added_at, EXCLUDED.added_at);
+
+-- name: DropDuplicateShoppingListItem :exec
+-- Merging: from's shopping list item goes if into is already on the list,
+-- by ingredient or by name, so the list keeps one line for it.
+DELETE FROM shopping_list_items s
+WHERE s.ingredient_id = sqlc.arg(from_id)::uuid
+  AND EXISTS (
+    SELECT 1 FROM shopping_list_items o
+    WHERE o.uuid <> s.uuid
+      AND (o.ingredient_id = sqlc.arg(into_id)::uuid OR lower(o.name) = lower(sqlc.arg(into_name)::varchar))
+  );
+
+-- name: RepointShoppingListItems :exec
+-- Then any left become into's, under its name.
+UPDATE shopping_list_items
+SET ingredient_id = sqlc.arg(into_id)::uuid, name = sqlc.arg(into_name)::varchar
+WHERE ingredient_id = sqlc.arg(from_id)::uuid;
+
+-- name: RepointShoppingListCheck :exec
+-- Checks are kept by lowercased line name, so a ticked from is a ticked into.
+-- Merging casing variants leaves the check where it is.
+WITH moved AS (
+  DELETE FROM shopping_list_checks
+  WHERE name = lower(sqlc.arg(from_name)::varchar)
+    AND lower(sqlc.arg(from_name)::varchar) <> lower(sqlc.arg(into_name)::varchar)
+  RETURNING checked_at
+)
+INSERT INTO shopping_list_checks (name, checked_at)
+SELECT lower(sqlc.arg(into_name)::varchar), checked_at FROM moved
+ON CONFLICT (name) DO NOTHING;
+
+-- name: RepointStaple :exec
+-- A staple merged into another ingredient makes it one; into keeps its own
+-- "run out" if it was already a staple.
+UPDATE ingredients i
+SET staple = true,
+    staple_out_at = CASE WHEN i.staple THEN i.staple_out_at ELSE f.staple_out_at END
+FROM ingredients f
+WHERE i.uuid = sqlc.arg(into_id)::uuid AND f.uuid = sqlc.arg(from_id)::uuid AND f.staple;
+
+-- name: DeleteIngredient :exec
+-- pantry_items rows go with it (ON DELETE CASCADE).
+DELETE FROM ingredients WHERE uuid = $1;
@@ -0,0 +1,17 @@
+-- name: CreateRecipeCook :one
+INSERT INTO recipe_cooks (uuid, recipe_id, cooked_on, rating, notes, servings, photos)
+VALUES (@uuid, @recipe_id, @cooked_on, sqlc.narg('rating'), sqlc.narg('notes'), sqlc.narg('servings'), @photos::text[])
+RETURNING *;
+
+-- name: ListRecipeCooks :many
+-- Most recent first.
+SELECT * FROM recipe_cooks
+WHERE recipe_id = @recipe_id
+ORDER BY cooked_on DESC, created_at DESC;
+
+-- name: GetRecipeCookStats :one
+-- Joined from recipes so a recipe never cooked gets a row of NULLs.
+SELECT cs.times_cooked, cs.last_cooked, cs.average_rating
+FROM recipes r
+LEFT JOIN recipe_cook_stats cs ON cs.recipe_id = r.uuid
+WHERE r.uuid = @recipe_id;
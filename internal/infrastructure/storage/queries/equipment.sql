@@ -0,0 +1,22 @@
+-- name: CreateRecipeEquipment :exec
+INSERT INTO recipe_equipment (recipe_id, name) VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetEquipmentByRecipeID :many
+SELECT name FROM recipe_equipment
+WHERE recipe_id = $1
+ORDER BY name ASC;
+
+-- name: DeleteRecipeEquipmentByRecipeID :exec
+DELETE FROM recipe_equipment WHERE recipe_id = $1;
+
+-- name: AddHouseholdEquipment :exec
+INSERT INTO household_equipment (name) VALUES ($1)
+ON CONFLICT (name) DO NOTHING;
+
+-- name: RemoveHouseholdEquipment :exec
+DELETE FROM household_equipment WHERE name = $1;
+
+-- name: ListHouseholdEquipment :many
+SELECT name, added_at FROM household_equipment
+ORDER BY name ASC;
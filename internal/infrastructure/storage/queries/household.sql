@@ -0,0 +1,16 @@
+-- name: ListHouseholdMembers :many
+SELECT * FROM household_members
+ORDER BY LOWER(name) ASC;
+
+-- name: UpsertHouseholdMember :one
+INSERT INTO household_members (name, allergens, diets)
+VALUES ($1, $2, $3)
+ON CONFLICT (LOWER(name)) DO UPDATE
+SET name = EXCLUDED.name,
+    allergens = EXCLUDED.allergens,
+    diets = EXCLUDED.diets,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteHouseholdMember :execrows
+DELETE FROM household_members WHERE LOWER(name) = LOWER($1);
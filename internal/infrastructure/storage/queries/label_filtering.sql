@@ -4,10 +4,12 @@ SELECT
     r.created_at, r.updated_at,
     CASE WHEN mp.recipe_id IS NOT NULL THEN true ELSE false END as is_in_meal_plan,
     p.uuid as main_photo_uuid,
-    p.url as main_photo_url
+    p.url as main_photo_url,
+    cs.times_cooked, cs.last_cooked, cs.average_rating
 FROM recipes r
 LEFT JOIN meal_plan_recipes mp ON r.uuid = mp.recipe_id
 LEFT JOIN photos p ON r.main_photo_id = p.uuid
+LEFT JOIN recipe_cook_stats cs ON cs.recipe_id = r.uuid
 WHERE r.archived_at IS NULL
     AND (sqlc.narg('search')::text IS NULL OR r.name ILIKE '%' || sqlc.narg('search')::text || '%')
     AND (
@@ -21,7 +23,21 @@ WHERE r.archived_at IS NULL
             HAVING COUNT(DISTINCT l2.type || ':' || l2.name) = array_length(sqlc.arg('label_keys')::text[], 1)
         )
     )
-ORDER BY r.created_at DESC
+    AND (NOT sqlc.arg('owned_equipment_only')::bool OR NOT EXISTS (
+        SELECT 1 FROM recipe_equipment re
+        WHERE re.recipe_id = r.uuid
+          AND re.name NOT IN (SELECT he.name FROM household_equipment he)
+    ))
+    AND (sqlc.narg('avoid_ingredients')::text[] IS NULL OR r.uuid NOT IN (
+        SELECT rai.recipe_id FROM recipe_all_ingredients rai
+        JOIN ingredients i ON i.uuid = rai.ingredient_id
+        WHERE i.name = ANY(sqlc.narg('avoid_ingredients')::text[])
+    ))
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::text = 'name' THEN LOWER(r.name) END ASC NULLS LAST,
+    CASE WHEN sqlc.arg('sort')::text = 'time' THEN COALESCE(r.prep_time, 0) + COALESCE(r.cook_time, 0) END ASC NULLS LAST,
+    CASE WHEN sqlc.arg('sort')::text = 'not_cooked_recently' THEN COALESCE(cs.last_cooked, '-infinity'::date) END ASC NULLS LAST,
+    r.created_at DESC
 LIMIT sqlc.arg('recipe_limit')
 OFFSET sqlc.arg('recipe_offset');
 
@@ -40,4 +56,14 @@ WHERE r.archived_at IS NULL
             GROUP BY rl2.recipe_id
             HAVING COUNT(DISTINCT l2.type || ':' || l2.name) = array_length(sqlc.arg('label_keys')::text[], 1)
         )
-    );
+    )
+    AND (NOT sqlc.arg('owned_equipment_only')::bool OR NOT EXISTS (
+        SELECT 1 FROM recipe_equipment re
+        WHERE re.recipe_id = r.uuid
+          AND re.name NOT IN (SELECT he.name FROM household_equipment he)
+    ))
+    AND (sqlc.narg('avoid_ingredients')::text[] IS NULL OR r.uuid NOT IN (
+        SELECT rai.recipe_id FROM recipe_all_ingredients rai
+        JOIN ingredients i ON i.uuid = rai.ingredient_id
+        WHERE i.name = ANY(sqlc.narg('avoid_ingredients')::text[])
+    ));
@@ -1,7 +1,10 @@
 -- name: AddToMealPlan :exec
-INSERT INTO meal_plan_recipes (recipe_id)
-VALUES ($1)
-ON CONFLICT (recipe_id) DO NOTHING;
+-- Re-adding a planned recipe with a date reschedules it; re-adding without one
+-- keeps whatever date it already had.
+INSERT INTO meal_plan_recipes (recipe_id, planned_for)
+VALUES (@recipe_id, sqlc.narg('planned_for')::date)
+ON CONFLICT (recipe_id) DO UPDATE
+SET planned_for = COALESCE(EXCLUDED.planned_for, meal_plan_recipes.planned_for);
 
 -- name: RemoveFromMealPlan :exec
 DELETE FROM meal_plan_recipes
@@ -21,9 +24,15 @@ SELECT
   r.created_at,
   r.updated_at,
   r.archived_at,
-  TRUE as is_in_meal_plan
+  TRUE as is_in_meal_plan,
+  mp.planned_for,
+  mp.added_at,
+  cs.times_cooked,
+  cs.last_cooked,
+  cs.average_rating
 FROM recipes r
 INNER JOIN meal_plan_recipes mp ON r.uuid = mp.recipe_id
 LEFT JOIN photos p ON r.main_photo_id = p.uuid
+LEFT JOIN recipe_cook_stats cs ON cs.recipe_id = r.uuid
 WHERE r.archived_at IS NULL
 ORDER BY mp.added_at DESC;
@@ -0,0 +1,27 @@
+-- name: ListNutrientProfiles :many
+SELECT * FROM ingredient_nutrition
+ORDER BY ingredient ASC;
+
+-- name: RenameNutrientProfile :exec
+-- Follows an ingredient rename. A profile already kept under the new name
+-- wins; the old one is then left for anything else still called that.
+UPDATE ingredient_nutrition SET ingredient = lower(sqlc.arg(to_name)::varchar), updated_at = now()
+WHERE ingredient = lower(sqlc.arg(from_name)::varchar)
+  AND NOT EXISTS (SELECT 1 FROM ingredient_nutrition WHERE ingredient = lower(sqlc.arg(to_name)::varchar));
+
+-- name: UpsertNutrientProfile :exec
+INSERT INTO ingredient_nutrition (
+    ingredient, calories, protein, fat, carbs, fibre, salt, density, piece_weight, updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, now()
+)
+ON CONFLICT (ingredient) DO UPDATE SET
+    calories     = EXCLUDED.calories,
+    protein      = EXCLUDED.protein,
+    fat          = EXCLUDED.fat,
+    carbs        = EXCLUDED.carbs,
+    fibre        = EXCLUDED.fibre,
+    salt         = EXCLUDED.salt,
+    density      = EXCLUDED.density,
+    piece_weight = EXCLUDED.piece_weight,
+    updated_at   = now();
@@ -1,23 +1,61 @@
 -- name: FindIngredientByName :one
--- Resolve a free-text ingredient name to a known ingredient. Matching is
--- trimmed and case-insensitive: MCP callers and the chat agent type names by
--- hand ("Olive Oil") and rarely match the casing a recipe happened to store
--- ("olive oil"). Ingredient names are only unique verbatim, so the same thing
--- can exist under several casings; an exact-casing match wins, then the oldest
--- row, so resolution is deterministic and collation-independent.
+-- Resolve a free-text ingredient name to a known ingredient. MCP callers and
+-- the chat agent type names by hand ("Olive Oil", "tomatoes") and rarely match
+-- what a recipe happened to store ("olive oil", "tomato"), so this resolves
+-- exactly as GetIngredientByName does: through case, singular and plural
+-- forms (@forms, from recipe.IngredientNameForms) and aliases, an exact match
+-- first and then the oldest row, so resolution is deterministic and
+-- collation-independent.
 SELECT uuid, name FROM ingredients
-WHERE lower(name) = lower(btrim(@name::varchar))
-ORDER BY (name = btrim(@name::varchar)) DESC, created_at ASC, uuid ASC
+WHERE lower(name) = ANY(@forms::text[]) OR aliases && @forms::text[]
+ORDER BY (name = btrim(@name::varchar)) DESC,
+         (lower(name) = lower(btrim(@name::varchar))) DESC,
+         (lower(name) = ANY(@forms::text[])) DESC,
+         created_at ASC, uuid ASC
 LIMIT 1;
 
 -- name: AddToPantry :exec
 -- Takes an ingredient UUID, not a name: the caller resolves the name first via
--- FindIngredientByName so an unknown ingredient is a reported error rather than
--- an INSERT ... SELECT that quietly matches nothing.
+-- FindIngredientByName and stocks a name that resolves to none with
+-- AddNewIngredientToPantry, rather than an INSERT ... SELECT that quietly
+-- matches nothing. Stocking an ingredient
+-- takes it off the shopping list if it was added there by hand, clears its
+-- check so it isn't ticked when it's next needed, and brings a staple that
+-- had run out back in.
+WITH bought AS (
+  DELETE FROM shopping_list_items WHERE ingredient_id = @ingredient_id
+), unchecked AS (
+  DELETE FROM shopping_list_checks
+  WHERE name IN (SELECT lower(name) FROM ingredients WHERE uuid = @ingredient_id)
+), restocked AS (
+  UPDATE ingredients SET staple_out_at = NULL
+  WHERE uuid = @ingredient_id AND staple_out_at IS NOT NULL
+)
 INSERT INTO pantry_items (ingredient_id)
 VALUES (@ingredient_id)
 ON CONFLICT (ingredient_id) DO NOTHING;
 
+-- name: AddNewIngredientToPantry :one
+-- Stocking a name that resolves to no ingredient: create it, flagged
+-- pantry_only until a recipe first uses it, and stock it. The flag only ever
+-- clears; an ingredient every recipe has since dropped isn't flagged again. A free-text shopping list
+-- item of the same name has been bought, so it comes off the list.
+WITH created AS (
+  INSERT INTO ingredients (name, pantry_only)
+  VALUES (@name::varchar, true)
+  ON CONFLICT (name) DO UPDATE SET updated_at = now()
+  RETURNING uuid, name, pantry_only
+), stocked AS (
+  INSERT INTO pantry_items (ingredient_id)
+  SELECT uuid FROM created
+  ON CONFLICT (ingredient_id) DO NOTHING
+), bought AS (
+  DELETE FROM shopping_list_items WHERE lower(name) = lower(@name::varchar)
+), unchecked AS (
+  DELETE FROM shopping_list_checks WHERE name = lower(@name::varchar)
+)
+SELECT uuid, name, pantry_only FROM created;
+
 -- name: RemoveFromPantry :exec
 -- Clears every casing variant, so a pantry that predates case-insensitive
 -- resolution ("Salt" and "salt" as separate rows) empties in one go.
@@ -29,11 +67,17 @@ WHERE ingredient_id IN (
 -- name: ListPantry :many
 -- Casing variants of one ingredient collapse to a single line. The pantry is
 -- presence-only, so listing "Salt" and "salt" separately would just read as a
--- bug.
-SELECT DISTINCT ON (lower(i.name)) i.name, p.added_at
-FROM pantry_items p
-INNER JOIN ingredients i ON i.uuid = p.ingredient_id
-ORDER BY lower(i.name) ASC, p.added_at ASC;
+-- bug. Staples that haven't run out are listed whether or not anyone stocked
+-- them, so whatever reads the pantry — the shopping list, cookability — takes
+-- them as given.
+SELECT DISTINCT ON (lower(i.name)) i.name,
+       COALESCE(p.added_at, i.updated_at)::timestamp AS added_at,
+       (i.staple AND i.staple_out_at IS NULL)::boolean AS staple,
+       i.pantry_only
+FROM ingredients i
+LEFT JOIN pantry_items p ON p.ingredient_id = i.uuid
+WHERE p.ingredient_id IS NOT NULL OR (i.staple AND i.staple_out_at IS NULL)
+ORDER BY lower(i.name) ASC, (i.staple AND i.staple_out_at IS NULL) DESC, COALESCE(p.added_at, i.updated_at) ASC;
 
 -- name: AddCustomShoppingItem :exec
 -- Add a free-text item to the shopping list (e.g. "washing-up liquid"). These
@@ -45,21 +89,62 @@ WHERE NOT EXISTS (
   SELECT 1 FROM shopping_list_items WHERE lower(name) = lower(@name::varchar)
 );
 
+-- name: AddIngredientShoppingItem :exec
+-- Put a known ingredient on the shopping list. A free-text item with the same
+-- name becomes the ingredient.
+INSERT INTO shopping_list_items (name, ingredient_id)
+SELECT i.name, i.uuid FROM ingredients i WHERE i.uuid = @ingredient_id
+ON CONFLICT ((lower(name))) DO UPDATE SET ingredient_id = EXCLUDED.ingredient_id;
+
 -- name: RemoveCustomShoppingItem :exec
-DELETE FROM shopping_list_items WHERE lower(name) = lower(@name::varchar);
+-- By the name it was added under or, for an ingredient, its current name.
+WITH unchecked AS (
+  DELETE FROM shopping_list_checks WHERE name = lower(@name::varchar)
+)
+DELETE FROM shopping_list_items s
+WHERE lower(s.name) = lower(@name::varchar)
+   OR s.ingredient_id IN (SELECT uuid FROM ingredients WHERE lower(name) = lower(@name::varchar));
 
 -- name: ListCustomShoppingItems :many
-SELECT name FROM shopping_list_items ORDER BY name ASC;
+-- Everything added by hand or from a scan, ingredients under their current
+-- name and, unless the item has its own, their category.
+SELECT COALESCE(i.name, s.name)::varchar AS name,
+       COALESCE(s.category, i.category) AS category,
+       (s.ingredient_id IS NOT NULL)::boolean AS is_ingredient,
+       EXISTS (
+         SELECT 1 FROM shopping_list_checks c WHERE c.name = lower(COALESCE(i.name, s.name))
+       )::boolean AS checked
+FROM shopping_list_items s
+LEFT JOIN ingredients i ON i.uuid = s.ingredient_id
+ORDER BY lower(COALESCE(i.name, s.name)) ASC;
+
+-- name: SetCustomShoppingItemCategory :execrows
+UPDATE shopping_list_items SET category = sqlc.narg(category)
+WHERE lower(name) = lower(@name::varchar)
+   OR ingredient_id IN (SELECT uuid FROM ingredients WHERE lower(name) = lower(@name::varchar));
 
 -- name: ListMealPlanShortfall :many
 -- Ingredients needed across the (non-archived) meal plan that are NOT already
 -- in the pantry. This is the shopping list. Pantry coverage is matched on the
 -- ingredient name case-insensitively rather than on ingredient_id, so a pantry
--- stocked with "Salt" still covers a recipe that calls for "salt".
-SELECT DISTINCT i.name
-FROM meal_plan_recipes mp
-INNER JOIN recipes r ON r.uuid = mp.recipe_id AND r.archived_at IS NULL
-INNER JOIN recipe_ingredient ri ON ri.recipe_id = mp.recipe_id
+-- stocked with "Salt" still covers a recipe that calls for "salt". Planned
+-- recipes are expanded through their sub-recipes, at any depth, so the dough
+-- a pizza calls for puts flour on the list. An archived sub-recipe is skipped,
+-- as expanding a recipe skips it.
+WITH RECURSIVE needed(recipe_id) AS (
+  SELECT mp.recipe_id
+  FROM meal_plan_recipes mp
+  INNER JOIN recipes r ON r.uuid = mp.recipe_id AND r.archived_at IS NULL
+  UNION
+  SELECT s.sub_recipe_id
+  FROM recipe_sub_recipes s
+  INNER JOIN needed n ON n.recipe_id = s.recipe_id
+  INNER JOIN recipes sr ON sr.uuid = s.sub_recipe_id AND sr.archived_at IS NULL
+)
+SELECT DISTINCT i.name, i.category,
+       EXISTS (SELECT 1 FROM shopping_list_checks c WHERE c.name = lower(i.name))::boolean AS checked
+FROM needed n
+INNER JOIN recipe_ingredient ri ON ri.recipe_id = n.recipe_id
 INNER JOIN ingredients i ON i.uuid = ri.ingredient_id
 WHERE NOT EXISTS (
   SELECT 1
@@ -67,4 +152,114 @@ WHERE NOT EXISTS (
   INNER JOIN ingredients pi_i ON pi_i.uuid = pi.ingredient_id
   WHERE lower(pi_i.name) = lower(i.name)
 )
+AND NOT EXISTS (
+  SELECT 1 FROM ingredients st
+  WHERE st.staple AND st.staple_out_at IS NULL AND lower(st.name) = lower(i.name)
+)
 ORDER BY i.name ASC;
+
+-- name: CreateListShare :one
+-- Expiry is computed here rather than in Go so it's on the same clock that
+-- GetListShare checks against. Expired shares are swept on the way in; there's
+-- no reason to keep dead credentials around.
+WITH swept AS (
+  DELETE FROM list_shares WHERE expires_at <= now()
+)
+INSERT INTO list_shares (token, list, expires_at)
+VALUES (@token, @list, now() + make_interval(secs => @ttl_seconds::float8))
+RETURNING token, list, expires_at;
+
+-- name: GetListShare :one
+-- Only live shares resolve; an expired token reads exactly like an unknown one.
+SELECT token, list, expires_at FROM list_shares
+WHERE token = @token AND expires_at > now();
+
+-- name: SetIngredientCategory :exec
+UPDATE ingredients SET category = sqlc.narg(category), updated_at = now()
+WHERE uuid = @ingredient_id;
+
+-- name: ListIngredientCategories :many
+SELECT name, category FROM ingredients ORDER BY name ASC;
+
+-- name: GetStoreLayout :one
+SELECT categories FROM store_layout;
+
+-- name: SaveStoreLayout :exec
+INSERT INTO store_layout (id, categories) VALUES (true, @categories::text[])
+ON CONFLICT (id) DO UPDATE SET categories = EXCLUDED.categories, updated_at = now();
+
+-- name: CheckShoppingItem :exec
+-- Checks are kept by lowercased line name; see shopping_list_checks.
+INSERT INTO shopping_list_checks (name) VALUES (lower(@name::varchar))
+ON CONFLICT (name) DO NOTHING;
+
+-- name: UncheckShoppingItem :exec
+DELETE FROM shopping_list_checks WHERE name = lower(@name::varchar);
+
+-- name: StockShoppingIngredients :exec
+-- Completing a trip: stock the pantry with the ingredients named in @names
+-- (lowercased), one row per name as pantry coverage is by name anyway, and
+-- bring any of them that are staples that had run out back in.
+WITH restocked AS (
+  UPDATE ingredients SET staple_out_at = NULL
+  WHERE lower(name) = ANY(@names::text[]) AND staple_out_at IS NOT NULL
+)
+INSERT INTO pantry_items (ingredient_id)
+SELECT DISTINCT ON (lower(i.name)) i.uuid
+FROM ingredients i
+WHERE lower(i.name) = ANY(@names::text[])
+ORDER BY lower(i.name), i.created_at, i.uuid
+ON CONFLICT (ingredient_id) DO NOTHING;
+
+-- name: RemoveShoppingItems :exec
+-- Completing a trip: delete the items added by hand that @names (lowercased)
+-- name, by the name they were added under or their ingredient's.
+DELETE FROM shopping_list_items s
+WHERE lower(s.name) = ANY(@names::text[])
+   OR s.ingredient_id IN (SELECT uuid FROM ingredients WHERE lower(name) = ANY(@names::text[]));
+
+-- name: ClaimShoppingChecks :many
+-- Completing a trip: clear the checks on @names (lowercased) and return the
+-- ones that were there. A concurrent completion that got to them first holds
+-- their row locks until it commits, after which they're gone, so each check
+-- goes into exactly one trip.
+DELETE FROM shopping_list_checks WHERE name = ANY(@names::text[])
+RETURNING name;
+
+-- name: ClearUnlistedShoppingChecks :exec
+-- Completing a trip: clear ticks on lines no longer on the list (@names,
+-- lowercased), e.g. a meal-plan line the plan has since dropped, so they
+-- aren't ticked already if the line comes back.
+DELETE FROM shopping_list_checks WHERE NOT (name = ANY(@names::text[]));
+
+-- name: RecordShoppingTrip :one
+INSERT INTO shopping_trips (items, stocked)
+VALUES (@items::text[], @stocked::text[])
+RETURNING uuid, items, stocked, completed_at;
+
+-- name: ListShoppingTrips :many
+SELECT uuid, items, stocked, completed_at FROM shopping_trips
+ORDER BY completed_at DESC
+LIMIT sqlc.arg('trip_limit');
+
+-- name: SetStaple :exec
+-- Clearing the flag clears any "run out" with it.
+UPDATE ingredients
+SET staple = @staple::boolean,
+    staple_out_at = CASE WHEN @staple::boolean THEN staple_out_at END,
+    updated_at = now()
+WHERE uuid = @ingredient_id;
+
+-- name: MarkOut :execrows
+-- "We're out of olive oil": the pantry entry goes and, for a staple, it's
+-- recorded as out until restocked. Returns 1 for a staple, 0 otherwise.
+WITH emptied AS (
+  DELETE FROM pantry_items WHERE ingredient_id = @ingredient_id
+)
+UPDATE ingredients SET staple_out_at = COALESCE(staple_out_at, now())
+WHERE uuid = @ingredient_id AND staple;
+
+-- name: ListStaples :many
+SELECT name, staple_out_at FROM ingredients
+WHERE staple
+ORDER BY lower(name) ASC;
@@ -9,9 +9,10 @@ INSERT INTO recipes (
     main_photo_id,
     url,
     created_at,
-    updated_at
+    updated_at,
+    parent_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
 -- name: CreateStep :one
@@ -21,9 +22,13 @@ INSERT INTO steps (
     step_order,
     description,
     created_at,
-    updated_at
+    updated_at,
+    timers,
+    temperatures,
+    ingredient_lines,
+    section
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: CreateIngredient :one
@@ -38,41 +43,41 @@ INSERT INTO ingredients (
 RETURNING *;
 
 -- name: GetIngredientByName :one
-SELECT * FROM ingredients WHERE name = $1;
+-- Resolves a name to the ingredient it means, so a recipe saying "Spring
+-- Onions" or "scallions" uses the existing spring onion rather than adding a
+-- near-duplicate. forms are the name's lowercase singular and plural forms
+-- (recipe.IngredientNameForms). An exact match wins, then one differing only
+-- in case, then a form of the name, then an alias; ties go to the oldest row.
+SELECT * FROM ingredients
+WHERE lower(name) = ANY(@forms::text[]) OR aliases && @forms::text[]
+ORDER BY (name = @name::varchar) DESC,
+         (lower(name) = lower(@name::varchar)) DESC,
+         (lower(name) = ANY(@forms::text[])) DESC,
+         created_at ASC, uuid ASC
+LIMIT 1;
 
 -- name: ListIngredients :many
 SELECT * FROM ingredients ORDER BY name ASC;
 
--- name: CreateUnit :one
-INSERT INTO units (
-    uuid,
-    name,
-    abbreviation,
-    created_at,
-    updated_at
-) VALUES (
-    $1, $2, $3, $4, $5
-) ON CONFLICT (name) DO UPDATE SET abbreviation = EXCLUDED.abbreviation, updated_at = EXCLUDED.updated_at
-RETURNING *;
-
--- name: GetUnitByName :one
-SELECT * FROM units WHERE name = $1;
-
--- name: ListUnits :many
-SELECT * FROM units ORDER BY name ASC;
-
 -- name: CreateRecipeIngredient :one
+-- A recipe using an ingredient that was only in the pantry makes it a recipe
+-- ingredient like any other.
+WITH linked AS (
+  UPDATE ingredients SET pantry_only = false WHERE uuid = $2 AND pantry_only
+)
 INSERT INTO recipe_ingredient (
     recipe_id,
     ingredient_id,
     unit_id,
+    unit_text,
     quantity,
     preparation,
     component,
     created_at,
-    updated_at
+    updated_at,
+    position
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: CreateLabel :one
@@ -91,10 +96,10 @@ RETURNING *;
 SELECT * FROM labels WHERE type = $1 AND name = $2;
 
 -- name: ListLabels :many
-SELECT l.type, l.name, COUNT(rl.recipe_id) AS uses
+SELECT l.type, l.name, l.display_name, COUNT(rl.recipe_id) AS uses
 FROM labels l
 LEFT JOIN recipe_label rl ON rl.label_id = l.uuid
-GROUP BY l.type, l.name
+GROUP BY l.type, l.name, l.display_name
 ORDER BY l.type, l.name;
 
 -- name: CreateRecipeLabel :one
@@ -125,11 +130,18 @@ SELECT * FROM photos WHERE url = $1 AND entity_type = $2 AND entity_id = $3;
 
 -- name: GetRecipeByID :one
 SELECT r.*,
-       p.uuid as main_photo_uuid, p.url as main_photo_url
+       p.uuid as main_photo_uuid, p.url as main_photo_url,
+       pr.uuid as parent_uuid, pr.name as parent_name
 FROM recipes r
 LEFT JOIN photos p ON r.main_photo_id = p.uuid
+LEFT JOIN recipes pr ON r.parent_id = pr.uuid AND pr.archived_at IS NULL
 WHERE r.uuid = $1 AND r.archived_at IS NULL;
 
+-- name: ListRecipeVariants :many
+SELECT uuid, name FROM recipes
+WHERE parent_id = $1 AND archived_at IS NULL
+ORDER BY created_at ASC;
+
 -- name: GetRecipeByName :one
 SELECT r.*,
        p.uuid as main_photo_uuid, p.url as main_photo_url
@@ -140,15 +152,31 @@ WHERE r.name = $1 AND r.archived_at IS NULL;
 -- name: ListRecipes :many
 SELECT r.*,
        p.uuid as main_photo_uuid, p.url as main_photo_url,
-       CASE WHEN mp.recipe_id IS NOT NULL THEN TRUE ELSE FALSE END as is_in_meal_plan
+       CASE WHEN mp.recipe_id IS NOT NULL THEN TRUE ELSE FALSE END as is_in_meal_plan,
+       cs.times_cooked, cs.last_cooked, cs.average_rating
 FROM recipes r
 LEFT JOIN photos p ON r.main_photo_id = p.uuid
 LEFT JOIN meal_plan_recipes mp ON r.uuid = mp.recipe_id
+LEFT JOIN recipe_cook_stats cs ON cs.recipe_id = r.uuid
 WHERE r.archived_at IS NULL
   AND ($3::text = '' OR r.name ILIKE '%' || $3 || '%' OR r.description ILIKE '%' || $3 || '%')
+  -- Owned equipment only: nothing the recipe needs is missing from the household.
+  AND (NOT $5::bool OR NOT EXISTS (
+    SELECT 1 FROM recipe_equipment re
+    WHERE re.recipe_id = r.uuid
+      AND re.name NOT IN (SELECT he.name FROM household_equipment he)
+  ))
+  -- Nothing anyone eating can't have, sub-recipes included.
+  AND ($6::text[] IS NULL OR r.uuid NOT IN (
+    SELECT rai.recipe_id FROM recipe_all_ingredients rai
+    JOIN ingredients i ON i.uuid = rai.ingredient_id
+    WHERE i.name = ANY($6::text[])
+  ))
 ORDER BY
   CASE WHEN $4::text = 'name' THEN LOWER(r.name) END ASC NULLS LAST,
   CASE WHEN $4::text = 'time' THEN COALESCE(r.prep_time, 0) + COALESCE(r.cook_time, 0) END ASC NULLS LAST,
+  -- Never cooked first, then longest since.
+  CASE WHEN $4::text = 'not_cooked_recently' THEN COALESCE(cs.last_cooked, '-infinity'::date) END ASC NULLS LAST,
   r.created_at DESC
 LIMIT $1 OFFSET $2;
 
@@ -156,7 +184,17 @@ LIMIT $1 OFFSET $2;
 SELECT COUNT(*)
 FROM recipes r
 WHERE r.archived_at IS NULL
-  AND ($1::text = '' OR r.name ILIKE '%' || $1 || '%' OR r.description ILIKE '%' || $1 || '%');
+  AND ($1::text = '' OR r.name ILIKE '%' || $1 || '%' OR r.description ILIKE '%' || $1 || '%')
+  AND (NOT $2::bool OR NOT EXISTS (
+    SELECT 1 FROM recipe_equipment re
+    WHERE re.recipe_id = r.uuid
+      AND re.name NOT IN (SELECT he.name FROM household_equipment he)
+  ))
+  AND ($3::text[] IS NULL OR r.uuid NOT IN (
+    SELECT rai.recipe_id FROM recipe_all_ingredients rai
+    JOIN ingredients i ON i.uuid = rai.ingredient_id
+    WHERE i.name = ANY($3::text[])
+  ));
 
 -- name: GetStepsByRecipeID :many
 SELECT s.* FROM steps s
@@ -168,14 +206,14 @@ ORDER BY s.step_order ASC;
 SELECT
     ri.*,
     i.name as ingredient_name,
-    u.name as unit_name,
+    COALESCE(u.name, ri.unit_text, '')::varchar as unit_name,
     u.abbreviation as unit_abbreviation
 FROM recipe_ingredient ri
 JOIN ingredients i ON ri.ingredient_id = i.uuid
 LEFT JOIN units u ON ri.unit_id = u.uuid
 INNER JOIN recipes r ON ri.recipe_id = r.uuid
 WHERE ri.recipe_id = $1 AND r.archived_at IS NULL
-ORDER BY ri.component NULLS FIRST, ri.created_at ASC;
+ORDER BY ri.position ASC;
 
 -- name: GetLabelsByRecipeID :many
 SELECT l.*
@@ -0,0 +1,29 @@
+-- name: LockRecipeForRevision :exec
+-- Taken at the start of an edit, so concurrent edits of one recipe queue up
+-- here rather than both reading the same MAX(revision) and the second failing
+-- on the primary key.
+SELECT uuid FROM recipes WHERE uuid = @recipe_id FOR UPDATE;
+
+-- name: CreateRecipeRevision :one
+-- Revision numbers are per recipe and gapless. Callers run this inside the
+-- same transaction as the change it records, after LockRecipeForRevision.
+INSERT INTO recipe_revisions (recipe_id, revision, source, note, snapshot)
+SELECT @recipe_id, COALESCE(MAX(revision), 0) + 1, @source, sqlc.narg('note'), @snapshot
+FROM recipe_revisions
+WHERE recipe_id = @recipe_id
+RETURNING revision, created_at;
+
+-- name: CountRecipeRevisions :one
+SELECT COUNT(*) FROM recipe_revisions WHERE recipe_id = @recipe_id;
+
+-- name: ListRecipeRevisions :many
+-- Newest first. Snapshots are left out; they're fetched one at a time.
+SELECT revision, source, note, created_at
+FROM recipe_revisions
+WHERE recipe_id = @recipe_id
+ORDER BY revision DESC;
+
+-- name: GetRecipeRevision :one
+SELECT revision, source, note, snapshot, created_at
+FROM recipe_revisions
+WHERE recipe_id = @recipe_id AND revision = @revision;
@@ -0,0 +1,35 @@
+-- name: CreateRecipeSubRecipe :exec
+INSERT INTO recipe_sub_recipes (
+    recipe_id,
+    position,
+    sub_recipe_id,
+    quantity,
+    unit,
+    component,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: GetSubRecipesByRecipeID :many
+SELECT s.position, s.sub_recipe_id, s.quantity, s.unit, s.component, r.name AS sub_recipe_name
+FROM recipe_sub_recipes s
+INNER JOIN recipes r ON r.uuid = s.sub_recipe_id
+WHERE s.recipe_id = $1
+ORDER BY s.position ASC;
+
+-- name: DeleteRecipeSubRecipesByRecipeID :exec
+DELETE FROM recipe_sub_recipes WHERE recipe_id = $1;
+
+-- name: SubRecipeReaches :one
+-- Whether target is sub_recipe_id itself or used, at any depth, by it. Saving
+-- target with sub_recipe_id as a line would then close a cycle. UNION (not
+-- UNION ALL) makes the walk terminate even if a cycle already exists.
+WITH RECURSIVE reach(recipe_id) AS (
+    SELECT @sub_recipe_id::uuid
+    UNION
+    SELECT s.sub_recipe_id
+    FROM recipe_sub_recipes s
+    INNER JOIN reach ON s.recipe_id = reach.recipe_id
+)
+SELECT EXISTS (SELECT 1 FROM reach WHERE reach.recipe_id = @target::uuid);
@@ -0,0 +1,65 @@
+-- name: ListLabelTypes :many
+SELECT * FROM label_types
+ORDER BY position ASC, name ASC;
+
+-- name: ListLabelDefinitions :many
+SELECT l.uuid, l.type, l.name, l.display_name, l.description, l.aliases,
+       l.created_at, l.updated_at, COUNT(rl.recipe_id) AS uses
+FROM labels l
+LEFT JOIN recipe_label rl ON rl.label_id = l.uuid
+GROUP BY l.uuid
+ORDER BY l.type, l.name;
+
+-- name: UpsertLabelType :one
+INSERT INTO label_types (name, display_name, description, multiple, position)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (name) DO UPDATE
+SET display_name = EXCLUDED.display_name,
+    description = EXCLUDED.description,
+    multiple = EXCLUDED.multiple,
+    position = EXCLUDED.position,
+    updated_at = now()
+RETURNING *;
+
+-- name: UpsertLabel :one
+INSERT INTO labels (uuid, type, name, display_name, description, aliases, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, now(), now())
+ON CONFLICT (type, name) DO UPDATE
+SET display_name = EXCLUDED.display_name,
+    description = EXCLUDED.description,
+    aliases = EXCLUDED.aliases,
+    updated_at = now()
+RETURNING *;
+
+-- name: RenameLabel :one
+UPDATE labels
+SET name = $3, display_name = $4, aliases = $5, updated_at = now()
+WHERE type = $1 AND name = $2
+RETURNING *;
+
+-- name: SetLabelAliases :exec
+UPDATE labels SET aliases = $2, updated_at = now() WHERE uuid = $1;
+
+-- name: CopyRecipeLabels :exec
+-- Gives every recipe labelled $1 the label $2 too; a recipe that already has
+-- $2 keeps its row.
+INSERT INTO recipe_label (recipe_id, label_id, created_at, updated_at)
+SELECT rl.recipe_id, sqlc.arg(into_id)::uuid, now(), now()
+FROM recipe_label rl
+WHERE rl.label_id = sqlc.arg(from_id)::uuid
+ON CONFLICT (recipe_id, label_id) DO NOTHING;
+
+-- name: DeleteRecipeLabelsByLabelID :exec
+DELETE FROM recipe_label WHERE label_id = $1;
+
+-- name: DeleteLabel :exec
+DELETE FROM labels WHERE uuid = $1;
+
+-- name: ListLabelRecipes :many
+-- Every recipe carrying the label, archived ones included, since renames and
+-- merges re-point those too.
+SELECT r.uuid, r.name
+FROM recipes r
+JOIN recipe_label rl ON rl.recipe_id = r.uuid
+WHERE rl.label_id = $1
+ORDER BY r.name, r.uuid;
@@ -0,0 +1,67 @@
+-- name: ListUnits :many
+SELECT * FROM units ORDER BY name ASC;
+
+-- name: ResolveUnit :one
+-- Find the unit a recipe line's unit text means: written exactly as a unit's
+-- name or alias first (which tells "T" from "t"), then its name, then an
+-- alias, in any case and singular or plural. Ties go to the oldest unit.
+SELECT * FROM units
+WHERE name = @name::varchar
+   OR @name::varchar = ANY(aliases)
+   OR lower(name) = ANY(@forms::text[])
+   OR EXISTS (SELECT 1 FROM unnest(aliases) a WHERE lower(a) = ANY(@forms::text[]))
+ORDER BY (name = @name::varchar OR @name::varchar = ANY(aliases)) DESC,
+         (lower(name) = ANY(@forms::text[])) DESC,
+         created_at ASC, uuid ASC
+LIMIT 1;
+
+-- name: GetUnitByName :one
+SELECT * FROM units WHERE name = $1;
+
+-- name: UpsertUnit :one
+INSERT INTO units (name, abbreviation, dimension, aliases)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (name) DO UPDATE
+SET abbreviation = EXCLUDED.abbreviation,
+    dimension = EXCLUDED.dimension,
+    aliases = EXCLUDED.aliases,
+    updated_at = now()
+RETURNING *;
+
+-- name: SetUnitAliases :execrows
+UPDATE units SET aliases = $2, updated_at = now() WHERE name = $1;
+
+-- name: AdoptUnitText :execrows
+-- Give lines queued with unit text the unit it now resolves to.
+UPDATE recipe_ingredient
+SET unit_id = sqlc.arg(unit_id)::uuid, unit_text = NULL, updated_at = now()
+WHERE unit_id IS NULL AND lower(unit_text) = ANY(sqlc.arg(forms)::text[]);
+
+-- name: RepointUnit :exec
+UPDATE recipe_ingredient SET unit_id = sqlc.arg(into_id)::uuid, updated_at = now()
+WHERE unit_id = sqlc.arg(from_id)::uuid;
+
+-- name: DeleteUnit :exec
+DELETE FROM units WHERE uuid = $1;
+
+-- name: QueueUnitReview :exec
+-- A dismissed text stays dismissed; last_seen still moves on.
+INSERT INTO unit_reviews (name) VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET last_seen = now();
+
+-- name: ListUnitReviews :many
+SELECT ur.name, ur.first_seen, ur.last_seen,
+       COUNT(ri.recipe_id) AS lines,
+       COUNT(DISTINCT ri.recipe_id) AS recipes
+FROM unit_reviews ur
+LEFT JOIN recipe_ingredient ri ON ri.unit_id IS NULL AND lower(ri.unit_text) = ur.name
+WHERE ur.dismissed_at IS NULL
+GROUP BY ur.name, ur.first_seen, ur.last_seen
+ORDER BY ur.last_seen DESC, ur.name ASC;
+
+-- name: DismissUnitReview :execrows
+UPDATE unit_reviews SET dismissed_at = now()
+WHERE name = $1 AND dismissed_at IS NULL;
+
+-- name: DeleteUnitReviews :execrows
+DELETE FROM unit_reviews WHERE name = ANY(sqlc.arg(names)::text[]);
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+func (r *recipeRepository) AddAnnotation(ctx context.Context, recipeID uuid.UUID, a recipe.Annotation) (*recipe.Annotation, error) {
+	row, err := r.db.CreateRecipeAnnotation(ctx, db.CreateRecipeAnnotationParams{
+		Uuid:      a.UUID,
+		RecipeID:  recipeID,
+		StepOrder: sql.NullInt16{Int16: a.Step, Valid: a.Step != 0},
+		Note:      a.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	added := annotationFromRow(row)
+	return &added, nil
+}
+
+func (r *recipeRepository) UpdateAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID, note string) (*recipe.Annotation, error) {
+	row, err := r.db.UpdateRecipeAnnotation(ctx, db.UpdateRecipeAnnotationParams{
+		Uuid:     annotationID,
+		RecipeID: recipeID,
+		Note:     note,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, recipe.AnnotationNotFoundError{RecipeID: recipeID, AnnotationID: annotationID}
+	}
+	if err != nil {
+		return nil, err
+	}
+	updated := annotationFromRow(row)
+	return &updated, nil
+}
+
+func (r *recipeRepository) DeleteAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID) error {
+	n, err := r.db.DeleteRecipeAnnotation(ctx, db.DeleteRecipeAnnotationParams{
+		Uuid:     annotationID,
+		RecipeID: recipeID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return recipe.AnnotationNotFoundError{RecipeID: recipeID, AnnotationID: annotationID}
+	}
+	return nil
+}
+
+func (r *recipeRepository) listAnnotations(ctx context.Context, q *db.Queries, recipeID uuid.UUID) ([]recipe.Annotation, error) {
+	rows, err := q.ListRecipeAnnotations(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([]recipe.Annotation, len(rows))
+	for i, row := range rows {
+		annotations[i] = annotationFromRow(row)
+	}
+	return annotations, nil
+}
+
+// reanchorAnnotations moves step notes to follow their steps from before to
+// the steps about to be saved.
+func reanchorAnnotations(ctx context.Context, q *db.Queries, recipeID uuid.UUID, before, after []recipe.Step) error {
+	rows, err := q.ListRecipeAnnotations(ctx, recipeID)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	annotations := make([]recipe.Annotation, len(rows))
+	for i, row := range rows {
+		annotations[i] = annotationFromRow(row)
+	}
+	for _, a := range recipe.ReanchorAnnotations(before, after, annotations) {
+		if err := q.MoveRecipeAnnotation(ctx, db.MoveRecipeAnnotationParams{
+			Uuid:      a.UUID,
+			StepOrder: sql.NullInt16{Int16: a.Step, Valid: a.Step > 0},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func annotationFromRow(row db.RecipeAnnotation) recipe.Annotation {
+	return recipe.Annotation{
+		UUID:      row.Uuid,
+		Step:      row.StepOrder.Int16,
+		Note:      row.Note,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+func (r *recipeRepository) ListIngredientCatalogue(ctx context.Context) (recipe.IngredientCatalogue, error) {
+	return listIngredientCatalogue(ctx, r.db)
+}
+
+// listIngredientCatalogue is shared with the pantry, which resolves shopping
+// list text against the catalogue.
+func listIngredientCatalogue(ctx context.Context, q *db.Queries) (recipe.IngredientCatalogue, error) {
+	rows, err := q.ListIngredientCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalogue := make(recipe.IngredientCatalogue, len(rows))
+	for i, row := range rows {
+		catalogue[i] = recipe.IngredientSummary{
+			Name:       row.Name,
+			Aliases:    nonNilStrings(row.Aliases),
+			Uses:       int(row.Uses),
+			InPantry:   row.InPantry,
+			PantryOnly: row.PantryOnly,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		}
+	}
+	return catalogue, nil
+}
+
+func (r *recipeRepository) SetIngredientAliases(ctx context.Context, name string, aliases []string) error {
+	n, err := r.db.SetIngredientAliases(ctx, db.SetIngredientAliasesParams{Name: name, Aliases: nonNilStrings(aliases)})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return recipe.IngredientNotFoundError{Name: name}
+	}
+	return nil
+}
+
+// RenameIngredient renames the ingredient stored as from in place, so the
+// recipes and pantry using it keep it, and sets its aliases. What is kept by
+// name rather than by ingredient, its shopping list tick and its nutrient
+// profile, moves to the new name with it, all or nothing.
+func (r *recipeRepository) RenameIngredient(ctx context.Context, from, to string, aliases []string) error {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	var n int64
+	n, err = q.RenameIngredient(ctx, db.RenameIngredientParams{FromName: from, ToName: to, Aliases: nonNilStrings(aliases)})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		err = recipe.IngredientNotFoundError{Name: from}
+		return err
+	}
+	if err = q.RepointShoppingListCheck(ctx, db.RepointShoppingListCheckParams{FromName: from, IntoName: to}); err != nil {
+		return err
+	}
+	err = q.RenameNutrientProfile(ctx, db.RenameNutrientProfileParams{FromName: from, ToName: to})
+	return err
+}
+
+// MergeIngredients moves every recipe line, any pantry entry, staple flag and
+// shopping list item or check using from onto into, deletes from, and sets
+// into's aliases, all or nothing.
+func (r *recipeRepository) MergeIngredients(ctx context.Context, from, into string, aliases []string) error {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	var fromRow, intoRow db.Ingredient
+	fromRow, err = q.GetIngredientByExactName(ctx, from)
+	if err == sql.ErrNoRows {
+		err = recipe.IngredientNotFoundError{Name: from}
+	}
+	if err != nil {
+		return err
+	}
+	intoRow, err = q.GetIngredientByExactName(ctx, into)
+	if err == sql.ErrNoRows {
+		err = recipe.IngredientNotFoundError{Name: into}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = q.RepointRecipeIngredients(ctx, db.RepointRecipeIngredientsParams{IntoID: intoRow.Uuid, FromID: fromRow.Uuid}); err != nil {
+		return err
+	}
+	if err = q.RepointPantryItem(ctx, db.RepointPantryItemParams{IntoID: intoRow.Uuid, FromID: fromRow.Uuid}); err != nil {
+		return err
+	}
+	// Shopping list items reference the ingredient ON DELETE CASCADE, so they
+	// have to move before from is deleted or they'd go with it.
+	if err = q.DropDuplicateShoppingListItem(ctx, db.DropDuplicateShoppingListItemParams{FromID: fromRow.Uuid, IntoID: intoRow.Uuid, IntoName: intoRow.Name}); err != nil {
+		return err
+	}
+	if err = q.RepointShoppingListItems(ctx, db.RepointShoppingListItemsParams{IntoID: intoRow.Uuid, IntoName: intoRow.Name, FromID: fromRow.Uuid}); err != nil {
+		return err
+	}
+	if err = q.RepointShoppingListCheck(ctx, db.RepointShoppingListCheckParams{FromName: fromRow.Name, IntoName: intoRow.Name}); err != nil {
+		return err
+	}
+	if err = q.RepointStaple(ctx, db.RepointStapleParams{IntoID: intoRow.Uuid, FromID: fromRow.Uuid}); err != nil {
+		return err
+	}
+	if err = q.DeleteIngredient(ctx, fromRow.Uuid); err != nil {
+		return err
+	}
+	_, err = q.SetIngredientAliases(ctx, db.SetIngredientAliasesParams{Name: into, Aliases: nonNilStrings(aliases)})
+	return err
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+func (r *recipeRepository) LogCook(ctx context.Context, cook recipe.Cook) (*recipe.Cook, error) {
+	photos := make([]string, len(cook.Photos))
+	for i, p := range cook.Photos {
+		photos[i] = p.URL
+	}
+
+	row, err := r.db.CreateRecipeCook(ctx, db.CreateRecipeCookParams{
+		Uuid:     cook.UUID,
+		RecipeID: cook.RecipeID,
+		CookedOn: cook.CookedOn,
+		Rating:   sql.NullInt16{Int16: cook.Rating, Valid: cook.Rating != 0},
+		Notes:    sql.NullString{String: cook.Notes, Valid: cook.Notes != ""},
+		Servings: sql.NullInt16{Int16: cook.Servings, Valid: cook.Servings != 0},
+		Photos:   photos,
+	})
+	if err != nil {
+		return nil, err
+	}
+	logged := cookFromRow(row)
+	return &logged, nil
+}
+
+func (r *recipeRepository) ListCooks(ctx context.Context, recipeID uuid.UUID) ([]recipe.Cook, error) {
+	rows, err := r.db.ListRecipeCooks(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	cooks := make([]recipe.Cook, len(rows))
+	for i, row := range rows {
+		cooks[i] = cookFromRow(row)
+	}
+	return cooks, nil
+}
+
+func cookFromRow(row db.RecipeCook) recipe.Cook {
+	photos := make([]recipe.Photo, len(row.Photos))
+	for i, url := range row.Photos {
+		photos[i] = recipe.Photo{URL: url}
+	}
+	return recipe.Cook{
+		UUID:      row.Uuid,
+		RecipeID:  row.RecipeID,
+		CookedOn:  row.CookedOn,
+		Rating:    row.Rating.Int16,
+		Notes:     row.Notes.String,
+		Servings:  row.Servings.Int16,
+		Photos:    photos,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// cookStats builds a recipe's cook summary from the recipe_cook_stats
+// columns, which are all NULL for a recipe never cooked.
+func cookStats(timesCooked sql.NullInt32, lastCooked sql.NullTime, averageRating sql.NullFloat64) *recipe.CookStats {
+	stats := &recipe.CookStats{TimesCooked: int(timesCooked.Int32)}
+	if lastCooked.Valid {
+		stats.LastCooked = &lastCooked.Time
+	}
+	if averageRating.Valid {
+		stats.AverageRating = &averageRating.Float64
+	}
+	return stats
+}
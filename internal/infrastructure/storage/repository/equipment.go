@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+// saveRecipeEquipment stores the equipment a recipe needs. Names are expected
+// already normalised (recipe.FillEquipment).
+func saveRecipeEquipment(ctx context.Context, q *db.Queries, recipeID uuid.UUID, names []string) error {
+	for _, name := range names {
+		if err := q.CreateRecipeEquipment(ctx, db.CreateRecipeEquipmentParams{RecipeID: recipeID, Name: name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recipeEquipment(ctx context.Context, q *db.Queries, recipeID uuid.UUID) ([]string, error) {
+	names, err := q.GetEquipmentByRecipeID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if names == nil {
+		names = []string{}
+	}
+	return names, nil
+}
+
+func (r *recipeRepository) ListHouseholdEquipment(ctx context.Context) ([]recipe.EquipmentItem, error) {
+	rows, err := r.db.ListHouseholdEquipment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]recipe.EquipmentItem, len(rows))
+	for i, row := range rows {
+		items[i] = recipe.EquipmentItem{Name: row.Name, AddedAt: row.AddedAt}
+	}
+	return items, nil
+}
+
+func (r *recipeRepository) AddHouseholdEquipment(ctx context.Context, name string) error {
+	return r.db.AddHouseholdEquipment(ctx, name)
+}
+
+func (r *recipeRepository) RemoveHouseholdEquipment(ctx context.Context, name string) error {
+	return r.db.RemoveHouseholdEquipment(ctx, name)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+func (r *recipeRepository) ListPeople(ctx context.Context) ([]recipe.Person, error) {
+	rows, err := r.db.ListHouseholdMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	people := make([]recipe.Person, len(rows))
+	for i, row := range rows {
+		people[i] = personFromRow(row)
+	}
+	return people, nil
+}
+
+func (r *recipeRepository) SavePerson(ctx context.Context, p recipe.Person) (*recipe.Person, error) {
+	allergens := make([]string, len(p.Allergens))
+	for i, f := range p.Allergens {
+		allergens[i] = string(f)
+	}
+	diets := p.Diets
+	if diets == nil {
+		diets = []string{}
+	}
+	row, err := r.db.UpsertHouseholdMember(ctx, db.UpsertHouseholdMemberParams{
+		Name:      p.Name,
+		Allergens: allergens,
+		Diets:     diets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	saved := personFromRow(row)
+	return &saved, nil
+}
+
+func (r *recipeRepository) DeletePerson(ctx context.Context, name string) error {
+	n, err := r.db.DeleteHouseholdMember(ctx, name)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return recipe.PersonNotFoundError{Name: name}
+	}
+	return nil
+}
+
+func personFromRow(row db.HouseholdMember) recipe.Person {
+	allergens := make([]recipe.DietFlag, len(row.Allergens))
+	for i, f := range row.Allergens {
+		allergens[i] = recipe.DietFlag(f)
+	}
+	diets := row.Diets
+	if diets == nil {
+		diets = []string{}
+	}
+	return recipe.Person{
+		Name:      row.Name,
+		Allergens: allergens,
+		Diets:     diets,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+// ListNutrientProfiles returns every ingredient's nutrition, keyed by
+// lowercase name. The table is reference data of a few hundred rows, so it's
+// read whole rather than per recipe.
+func (r *recipeRepository) ListNutrientProfiles(ctx context.Context) (map[string]recipe.NutrientProfile, error) {
+	rows, err := r.db.ListNutrientProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[string]recipe.NutrientProfile, len(rows))
+	for _, row := range rows {
+		profiles[row.Ingredient] = recipe.NutrientProfile{
+			Ingredient: row.Ingredient,
+			Per100g: recipe.Nutrients{
+				Calories: row.Calories,
+				Protein:  row.Protein,
+				Fat:      row.Fat,
+				Carbs:    row.Carbs,
+				Fibre:    row.Fibre,
+				Salt:     row.Salt,
+			},
+			Density:     row.Density.Float64,
+			PieceWeight: row.PieceWeight.Float64,
+		}
+	}
+	return profiles, nil
+}
+
+// SaveNutrientProfiles inserts or replaces profiles, all or nothing.
+func (r *recipeRepository) SaveNutrientProfiles(ctx context.Context, profiles []recipe.NutrientProfile) error {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	for _, p := range profiles {
+		err = q.UpsertNutrientProfile(ctx, db.UpsertNutrientProfileParams{
+			Ingredient:  strings.ToLower(strings.TrimSpace(p.Ingredient)),
+			Calories:    p.Per100g.Calories,
+			Protein:     p.Per100g.Protein,
+			Fat:         p.Per100g.Fat,
+			Carbs:       p.Per100g.Carbs,
+			Fibre:       p.Per100g.Fibre,
+			Salt:        p.Per100g.Salt,
+			Density:     sql.NullFloat64{Float64: p.Density, Valid: p.Density > 0},
+			PieceWeight: sql.NullFloat64{Float64: p.PieceWeight, Valid: p.PieceWeight > 0},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,67 +4,155 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/kieranajp/the-bluer-book/internal/domain/pantry"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
 )
 
 type PantryRepository interface {
-	AddToPantry(ctx context.Context, ingredient string) error
+	// AddToPantry resolves the name as recipe saves do and stocks that
+	// ingredient, or creates a pantry-only one under the name when it
+	// resolves to none. It returns the ingredient's name and whether it was
+	// created.
+	AddToPantry(ctx context.Context, ingredient string) (string, bool, error)
 	RemoveFromPantry(ctx context.Context, ingredient string) error
+	// ListPantry includes staples that haven't run out.
 	ListPantry(ctx context.Context) ([]pantry.PantryItem, error)
-	ShoppingList(ctx context.Context) ([]string, error)
 
-	// Custom (free-text) shopping list items, kept separate from the
-	// meal-plan-derived shortfall.
+	// Staples. Each resolves the name as the pantry does and returns the
+	// ingredient's name. MarkOut empties the pantry of the ingredient and, for
+	// a staple, records it as out until it's stocked again, reporting whether
+	// it was one.
+	SetStaple(ctx context.Context, ingredient string, staple bool) (string, error)
+	MarkOut(ctx context.Context, ingredient string) (name string, staple bool, err error)
+	ListStaples(ctx context.Context) ([]pantry.Staple, error)
+	// ShoppingList returns the meal-plan shortfall: name and stored category
+	// only, the category empty when none is stored.
+	ShoppingList(ctx context.Context) ([]pantry.ShoppingListItem, error)
+
+	// Shopping list items added by hand or from a scan, kept separate from
+	// the meal-plan-derived shortfall: free text, or a known ingredient.
+	// AddIngredientShoppingItem resolves the name as the pantry does and
+	// returns the ingredient's name. ListCustomShoppingItems sets Source.
 	AddCustomShoppingItem(ctx context.Context, name string) error
+	AddIngredientShoppingItem(ctx context.Context, ingredient string) (string, error)
 	RemoveCustomShoppingItem(ctx context.Context, name string) error
-	ListCustomShoppingItems(ctx context.Context) ([]string, error)
+	ListCustomShoppingItems(ctx context.Context) ([]pantry.ShoppingListItem, error)
+	ListIngredientCatalogue(ctx context.Context) (recipe.IngredientCatalogue, error)
+
+	// Shopping trips. Checks are kept by line name, whatever the line's
+	// source. CompleteShoppingTrip, in one transaction, clears the checks on
+	// items, keeping only the items still checked; stocks the pantry with
+	// those named in stock; deletes the items added by hand they name; clears
+	// checks on names not in listed, the lines on the list; and records the
+	// trip. pantry.ErrNothingChecked if none of items was still checked, as
+	// when another completion took them first.
+	CheckShoppingItem(ctx context.Context, name string) error
+	UncheckShoppingItem(ctx context.Context, name string) error
+	CompleteShoppingTrip(ctx context.Context, items, stock, listed []string) (pantry.ShoppingTrip, error)
+	ListShoppingTrips(ctx context.Context, limit int) ([]pantry.ShoppingTrip, error)
+
+	// Grocery categories. An empty category clears the stored one.
+	// SetIngredientCategory resolves the name as the pantry does;
+	// SetCustomShoppingItemCategory returns pantry.ErrShoppingItemNotFound
+	// for an item that isn't on the list. GetStoreLayout is empty until one
+	// is saved.
+	SetIngredientCategory(ctx context.Context, ingredient, category string) error
+	SetCustomShoppingItemCategory(ctx context.Context, name, category string) error
+	ListIngredientCategories(ctx context.Context) ([]pantry.IngredientCategory, error)
+	GetStoreLayout(ctx context.Context) (pantry.StoreLayout, error)
+	SaveStoreLayout(ctx context.Context, layout pantry.StoreLayout) error
+
+	// Public share links. GetListShare returns pantry.ErrShareNotFound for
+	// tokens that are unknown or expired.
+	CreateListShare(ctx context.Context, token, list string, ttl time.Duration) (pantry.ListShare, error)
+	GetListShare(ctx context.Context, token string) (pantry.ListShare, error)
 }
 
 type pantryRepository struct {
 	db     *db.Queries
+	sqlDB  *sql.DB
 	logger logger.Logger
 }
 
-func NewPantryRepository(db *db.Queries, logger logger.Logger) PantryRepository {
-	return &pantryRepository{db: db, logger: logger}
+func NewPantryRepository(db *db.Queries, sqlDB *sql.DB, logger logger.Logger) PantryRepository {
+	return &pantryRepository{db: db, sqlDB: sqlDB, logger: logger}
 }
 
-func (r *pantryRepository) AddToPantry(ctx context.Context, ingredient string) error {
-	id, err := r.resolveIngredient(ctx, ingredient)
+func (r *pantryRepository) AddToPantry(ctx context.Context, ingredient string) (string, bool, error) {
+	row, err := r.resolveIngredient(ctx, ingredient)
+	if errors.Is(err, pantry.ErrIngredientNotFound) {
+		created, err := r.db.AddNewIngredientToPantry(ctx, recipe.NormalizeIngredientName(ingredient))
+		if err != nil {
+			return "", false, err
+		}
+		r.logger.Info().Msgf("Inserted new pantry-only ingredient: %s (UUID: %s)", created.Name, created.Uuid)
+		return created.Name, true, nil
+	}
 	if err != nil {
-		return err
+		return "", false, err
 	}
-	return r.db.AddToPantry(ctx, id)
+	return row.Name, false, r.db.AddToPantry(ctx, row.Uuid)
 }
 
 func (r *pantryRepository) RemoveFromPantry(ctx context.Context, ingredient string) error {
-	// Resolve first purely to validate the name: an unknown ingredient is worth
+	// Resolve first to validate the name: an unknown ingredient is worth
 	// reporting rather than passing off as a successful removal. The delete
-	// itself works by name so it clears every casing variant.
-	if _, err := r.resolveIngredient(ctx, ingredient); err != nil {
+	// itself works by the resolved name so it clears every casing variant.
+	row, err := r.resolveIngredient(ctx, ingredient)
+	if err != nil {
 		return err
 	}
-	return r.db.RemoveFromPantry(ctx, ingredient)
+	return r.db.RemoveFromPantry(ctx, row.Name)
 }
 
 // resolveIngredient maps a free-text ingredient name onto a known ingredient,
-// tolerating casing and surrounding whitespace. A name that matches nothing is
-// an error: pantry entries are foreign keys into the ingredients table, so
-// there is no row to create, and reporting success would leave the caller
-// believing the pantry changed when it didn't.
-func (r *pantryRepository) resolveIngredient(ctx context.Context, name string) (uuid.UUID, error) {
-	row, err := r.db.FindIngredientByName(ctx, name)
+// tolerating casing, surrounding whitespace, plurals and aliases. A name that
+// matches nothing is pantry.IngredientNotFoundError. AddToPantry creates the
+// ingredient then; everything else reports it, since success would leave the
+// caller believing something changed when it didn't.
+func (r *pantryRepository) resolveIngredient(ctx context.Context, name string) (db.FindIngredientByNameRow, error) {
+	row, err := r.db.FindIngredientByName(ctx, db.FindIngredientByNameParams{
+		Name:  name,
+		Forms: recipe.IngredientNameForms(name),
+	})
 	if errors.Is(err, sql.ErrNoRows) {
-		return uuid.Nil, pantry.IngredientNotFoundError{Name: name}
+		return row, pantry.IngredientNotFoundError{Name: name}
 	}
+	return row, err
+}
+
+func (r *pantryRepository) SetStaple(ctx context.Context, ingredient string, staple bool) (string, error) {
+	row, err := r.resolveIngredient(ctx, ingredient)
 	if err != nil {
-		return uuid.Nil, err
+		return "", err
 	}
-	return row.Uuid, nil
+	return row.Name, r.db.SetStaple(ctx, db.SetStapleParams{Staple: staple, IngredientID: row.Uuid})
+}
+
+func (r *pantryRepository) MarkOut(ctx context.Context, ingredient string) (string, bool, error) {
+	row, err := r.resolveIngredient(ctx, ingredient)
+	if err != nil {
+		return "", false, err
+	}
+	n, err := r.db.MarkOut(ctx, row.Uuid)
+	return row.Name, n > 0, err
+}
+
+func (r *pantryRepository) ListStaples(ctx context.Context) ([]pantry.Staple, error) {
+	rows, err := r.db.ListStaples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	staples := make([]pantry.Staple, len(rows))
+	for i, row := range rows {
+		staples[i] = pantry.Staple{Ingredient: row.Name, Out: row.StapleOutAt.Valid, OutSince: row.StapleOutAt.Time}
+	}
+	return staples, nil
 }
 
 func (r *pantryRepository) ListPantry(ctx context.Context) ([]pantry.PantryItem, error) {
@@ -78,13 +166,23 @@ func (r *pantryRepository) ListPantry(ctx context.Context) ([]pantry.PantryItem,
 		items[i] = pantry.PantryItem{
 			Ingredient: row.Name,
 			AddedAt:    row.AddedAt,
+			Staple:     row.Staple,
+			PantryOnly: row.PantryOnly,
 		}
 	}
 	return items, nil
 }
 
-func (r *pantryRepository) ShoppingList(ctx context.Context) ([]string, error) {
-	return r.db.ListMealPlanShortfall(ctx)
+func (r *pantryRepository) ShoppingList(ctx context.Context) ([]pantry.ShoppingListItem, error) {
+	rows, err := r.db.ListMealPlanShortfall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]pantry.ShoppingListItem, len(rows))
+	for i, row := range rows {
+		items[i] = pantry.ShoppingListItem{Name: row.Name, Category: row.Category.String, Checked: row.Checked}
+	}
+	return items, nil
 }
 
 func (r *pantryRepository) AddCustomShoppingItem(ctx context.Context, name string) error {
@@ -95,6 +193,201 @@ func (r *pantryRepository) RemoveCustomShoppingItem(ctx context.Context, name st
 	return r.db.RemoveCustomShoppingItem(ctx, name)
 }
 
-func (r *pantryRepository) ListCustomShoppingItems(ctx context.Context) ([]string, error) {
-	return r.db.ListCustomShoppingItems(ctx)
+func (r *pantryRepository) ListCustomShoppingItems(ctx context.Context) ([]pantry.ShoppingListItem, error) {
+	rows, err := r.db.ListCustomShoppingItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]pantry.ShoppingListItem, len(rows))
+	for i, row := range rows {
+		items[i] = pantry.ShoppingListItem{Name: row.Name, Source: pantry.ShoppingSourceCustom, Category: row.Category.String, Checked: row.Checked}
+		if row.IsIngredient {
+			items[i].Source = pantry.ShoppingSourceIngredient
+		}
+	}
+	return items, nil
+}
+
+func (r *pantryRepository) AddIngredientShoppingItem(ctx context.Context, ingredient string) (string, error) {
+	row, err := r.resolveIngredient(ctx, ingredient)
+	if err != nil {
+		return "", err
+	}
+	return row.Name, r.db.AddIngredientShoppingItem(ctx, row.Uuid)
+}
+
+func (r *pantryRepository) CheckShoppingItem(ctx context.Context, name string) error {
+	return r.db.CheckShoppingItem(ctx, name)
+}
+
+func (r *pantryRepository) UncheckShoppingItem(ctx context.Context, name string) error {
+	return r.db.UncheckShoppingItem(ctx, name)
+}
+
+func (r *pantryRepository) CompleteShoppingTrip(ctx context.Context, items, stock, listed []string) (pantry.ShoppingTrip, error) {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	var claimed []string
+	claimed, err = q.ClaimShoppingChecks(ctx, lowerAll(items))
+	if err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+	checked := make(map[string]bool, len(claimed))
+	for _, name := range claimed {
+		checked[name] = true
+	}
+	items, stock = stillChecked(items, checked), stillChecked(stock, checked)
+	if len(items) == 0 {
+		err = pantry.ErrNothingChecked
+		return pantry.ShoppingTrip{}, err
+	}
+
+	if err = q.StockShoppingIngredients(ctx, lowerAll(stock)); err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+	if err = q.RemoveShoppingItems(ctx, lowerAll(items)); err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+	if err = q.ClearUnlistedShoppingChecks(ctx, lowerAll(listed)); err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+	var row db.ShoppingTrip
+	row, err = q.RecordShoppingTrip(ctx, db.RecordShoppingTripParams{
+		Items:   nonNilStrings(items),
+		Stocked: nonNilStrings(stock),
+	})
+	if err != nil {
+		return pantry.ShoppingTrip{}, err
+	}
+	return toShoppingTrip(row), nil
+}
+
+func (r *pantryRepository) ListShoppingTrips(ctx context.Context, limit int) ([]pantry.ShoppingTrip, error) {
+	rows, err := r.db.ListShoppingTrips(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+	trips := make([]pantry.ShoppingTrip, len(rows))
+	for i, row := range rows {
+		trips[i] = toShoppingTrip(row)
+	}
+	return trips, nil
+}
+
+func toShoppingTrip(row db.ShoppingTrip) pantry.ShoppingTrip {
+	return pantry.ShoppingTrip{
+		UUID:        row.Uuid,
+		Items:       nonNilStrings(row.Items),
+		Stocked:     nonNilStrings(row.Stocked),
+		CompletedAt: row.CompletedAt,
+	}
+}
+
+// stillChecked keeps the names whose lowercased form is in checked.
+func stillChecked(names []string, checked map[string]bool) []string {
+	var out []string
+	for _, n := range names {
+		if checked[strings.ToLower(n)] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func lowerAll(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = strings.ToLower(n)
+	}
+	return out
+}
+
+func (r *pantryRepository) ListIngredientCatalogue(ctx context.Context) (recipe.IngredientCatalogue, error) {
+	return listIngredientCatalogue(ctx, r.db)
+}
+
+func (r *pantryRepository) SetIngredientCategory(ctx context.Context, ingredient, category string) error {
+	row, err := r.resolveIngredient(ctx, ingredient)
+	if err != nil {
+		return err
+	}
+	return r.db.SetIngredientCategory(ctx, db.SetIngredientCategoryParams{
+		IngredientID: row.Uuid,
+		Category:     sql.NullString{String: category, Valid: category != ""},
+	})
+}
+
+func (r *pantryRepository) SetCustomShoppingItemCategory(ctx context.Context, name, category string) error {
+	n, err := r.db.SetCustomShoppingItemCategory(ctx, db.SetCustomShoppingItemCategoryParams{
+		Name:     name,
+		Category: sql.NullString{String: category, Valid: category != ""},
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return pantry.ShoppingItemNotFoundError{Name: name}
+	}
+	return nil
+}
+
+func (r *pantryRepository) ListIngredientCategories(ctx context.Context) ([]pantry.IngredientCategory, error) {
+	rows, err := r.db.ListIngredientCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]pantry.IngredientCategory, len(rows))
+	for i, row := range rows {
+		out[i] = pantry.IngredientCategory{Ingredient: row.Name, Category: row.Category.String}
+	}
+	return out, nil
+}
+
+func (r *pantryRepository) GetStoreLayout(ctx context.Context) (pantry.StoreLayout, error) {
+	categories, err := r.db.GetStoreLayout(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pantry.StoreLayout{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pantry.StoreLayout(categories), nil
+}
+
+func (r *pantryRepository) SaveStoreLayout(ctx context.Context, layout pantry.StoreLayout) error {
+	return r.db.SaveStoreLayout(ctx, nonNilStrings(layout))
+}
+
+func (r *pantryRepository) CreateListShare(ctx context.Context, token, list string, ttl time.Duration) (pantry.ListShare, error) {
+	row, err := r.db.CreateListShare(ctx, db.CreateListShareParams{
+		Token:      token,
+		List:       list,
+		TtlSeconds: ttl.Seconds(),
+	})
+	if err != nil {
+		return pantry.ListShare{}, err
+	}
+	return pantry.ListShare{Token: row.Token, List: row.List, ExpiresAt: row.ExpiresAt}, nil
+}
+
+func (r *pantryRepository) GetListShare(ctx context.Context, token string) (pantry.ListShare, error) {
+	row, err := r.db.GetListShare(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pantry.ListShare{}, pantry.ErrShareNotFound
+	}
+	if err != nil {
+		return pantry.ListShare{}, err
+	}
+	return pantry.ListShare{Token: row.Token, List: row.List, ExpiresAt: row.ExpiresAt}, nil
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -15,23 +16,84 @@ import (
 type RecipeRepository interface {
 	SaveRecipe(ctx context.Context, recipe recipe.Recipe) (*recipe.Recipe, error)
 	GetRecipeByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error)
-	ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string) ([]*recipe.Recipe, int, error)
+	// ListRecipes pages through live recipes. avoidIngredients, when not
+	// nil, leaves out recipes using any of those ingredients, in sub-recipes
+	// too.
+	ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string, ownedEquipmentOnly bool, avoidIngredients []string) ([]*recipe.Recipe, int, error)
 	UpdateRecipe(ctx context.Context, id uuid.UUID, recipe recipe.Recipe) (*recipe.Recipe, error)
 	ArchiveRecipe(ctx context.Context, id uuid.UUID) error
 	RestoreRecipe(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error)
 	ListArchivedRecipes(ctx context.Context, limit, offset int) ([]*recipe.Recipe, int, error)
 
 	// Meal planning methods
-	AddToMealPlan(ctx context.Context, recipeID uuid.UUID) error
+	AddToMealPlan(ctx context.Context, recipeID uuid.UUID, plannedFor *time.Time) error
 	RemoveFromMealPlan(ctx context.Context, recipeID uuid.UUID) error
 	ListMealPlanRecipes(ctx context.Context) ([]*recipe.Recipe, error)
 
+	// Revision history. SaveRecipe and UpdateRecipe record a revision for
+	// every change, attributed via recipe.WithRevisionSource.
+	ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]recipe.Revision, error)
+	GetRevision(ctx context.Context, recipeID uuid.UUID, number int) (*recipe.Revision, error)
+
+	// Cook log. Reads of recipes carry the log's CookStats.
+	LogCook(ctx context.Context, cook recipe.Cook) (*recipe.Cook, error)
+	ListCooks(ctx context.Context, recipeID uuid.UUID) ([]recipe.Cook, error)
+
+	// Annotations. Reads of a single recipe carry its annotations; saving a
+	// recipe never touches them.
+	AddAnnotation(ctx context.Context, recipeID uuid.UUID, a recipe.Annotation) (*recipe.Annotation, error)
+	UpdateAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID, note string) (*recipe.Annotation, error)
+	DeleteAnnotation(ctx context.Context, recipeID, annotationID uuid.UUID) error
+
+	// Household equipment. ListRecipes can leave out recipes that need
+	// anything not on this list.
+	ListHouseholdEquipment(ctx context.Context) ([]recipe.EquipmentItem, error)
+	AddHouseholdEquipment(ctx context.Context, name string) error
+	RemoveHouseholdEquipment(ctx context.Context, name string) error
+
+	// Nutrition reference data, keyed by lowercase ingredient name.
+	ListNutrientProfiles(ctx context.Context) (map[string]recipe.NutrientProfile, error)
+	SaveNutrientProfiles(ctx context.Context, profiles []recipe.NutrientProfile) error
+
+	// Household members, matched by name case-insensitively. SavePerson
+	// replaces any member of the same name.
+	ListPeople(ctx context.Context) ([]recipe.Person, error)
+	SavePerson(ctx context.Context, p recipe.Person) (*recipe.Person, error)
+	DeletePerson(ctx context.Context, name string) error
+
 	// Label browsing
 	ListLabels(ctx context.Context) ([]recipe.LabelSummary, error)
 
+	// Label taxonomy: the types and names recipes may be labelled with.
+	// ListLabelRecipes, RenameLabel and MergeLabels return a
+	// LabelNotFoundError for a label that doesn't exist; MergeLabels returns
+	// the recipes it re-pointed.
+	ListTaxonomy(ctx context.Context) (recipe.Taxonomy, error)
+	SaveLabelType(ctx context.Context, lt recipe.LabelType) (*recipe.LabelType, error)
+	SaveLabel(ctx context.Context, d recipe.LabelDefinition) (*recipe.LabelDefinition, error)
+	RenameLabel(ctx context.Context, typ, from string, to recipe.LabelDefinition) (*recipe.LabelDefinition, error)
+	ListLabelRecipes(ctx context.Context, label recipe.Label) ([]recipe.RecipeRef, error)
+	MergeLabels(ctx context.Context, from, into recipe.Label, aliases []string) ([]recipe.RecipeRef, error)
+
 	// Lookup methods
-	ListUnits(ctx context.Context) ([]recipe.Unit, error)
 	ListIngredients(ctx context.Context) ([]recipe.Ingredient, error)
+
+	// Ingredient catalogue admin. Ingredients are addressed by their exact
+	// stored name; an unknown one is an IngredientNotFoundError.
+	ListIngredientCatalogue(ctx context.Context) (recipe.IngredientCatalogue, error)
+	SetIngredientAliases(ctx context.Context, name string, aliases []string) error
+	RenameIngredient(ctx context.Context, from, to string, aliases []string) error
+	MergeIngredients(ctx context.Context, from, into string, aliases []string) error
+
+	// Unit registry admin. Units are addressed by name; an unknown one is a
+	// UnitNotFoundError. SaveUnit also gives lines queued with unit text
+	// matching the unit's name or aliases that unit, and clears their
+	// reviews.
+	ListUnits(ctx context.Context) (recipe.UnitRegistry, error)
+	SaveUnit(ctx context.Context, u recipe.Unit) (*recipe.Unit, error)
+	MergeUnits(ctx context.Context, from, into string, aliases []string) error
+	ListUnitReviews(ctx context.Context) ([]recipe.UnitReview, error)
+	DismissUnitReview(ctx context.Context, name string) error
 }
 
 type recipeRepository struct {
@@ -94,6 +156,7 @@ func (r *recipeRepository) SaveRecipe(ctx context.Context, rec recipe.Recipe) (*
 		Url:         sql.NullString{String: rec.Url, Valid: rec.Url != ""},
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		ParentID:    parentID(rec.Parent),
 	})
 	if err != nil {
 		return nil, err
@@ -105,12 +168,16 @@ func (r *recipeRepository) SaveRecipe(ctx context.Context, rec recipe.Recipe) (*
 	for _, step := range rec.Steps {
 		stepUUID := uuid.New()
 		stepRow, err := q.CreateStep(ctx, db.CreateStepParams{
-			Uuid:        stepUUID,
-			RecipeID:    uuidToNullUUID(&recipeID),
-			StepOrder:   step.Order,
-			Description: sql.NullString{String: step.Description, Valid: step.Description != ""},
-			CreatedAt:   now,
-			UpdatedAt:   now,
+			Uuid:            stepUUID,
+			RecipeID:        uuidToNullUUID(&recipeID),
+			StepOrder:       step.Order,
+			Description:     sql.NullString{String: step.Description, Valid: step.Description != ""},
+			Section:         sql.NullString{String: step.Section, Valid: step.Section != ""},
+			CreatedAt:       now,
+			UpdatedAt:       now,
+			Timers:          marshalStepDetail(step.Timers),
+			Temperatures:    marshalStepDetail(step.Temperatures),
+			IngredientLines: marshalStepDetail(recipe.StepIngredientLines(step)),
 		})
 		if err != nil {
 			return nil, err
@@ -134,11 +201,19 @@ func (r *recipeRepository) SaveRecipe(ctx context.Context, rec recipe.Recipe) (*
 	}
 
 	// Insert ingredients and recipe_ingredient
-	ingredientSet := make(map[uuid.UUID]bool)
-	for _, ri := range rec.Ingredients {
+	for i, ri := range rec.Ingredients {
+		if ri.SubRecipe != nil {
+			if err = r.saveSubRecipeLine(ctx, q, recipeID, i, ri, now); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		// Ingredient
 		var ingRow db.Ingredient
-		ingRow, err = q.GetIngredientByName(ctx, ri.Ingredient.Name)
+		ingRow, err = q.GetIngredientByName(ctx, db.GetIngredientByNameParams{
+			Name:  ri.Ingredient.Name,
+			Forms: recipe.IngredientNameForms(ri.Ingredient.Name),
+		})
 		if err == sql.ErrNoRows {
 			ingRow, err = q.CreateIngredient(ctx, db.CreateIngredientParams{
 				Uuid:      uuid.New(),
@@ -153,43 +228,25 @@ func (r *recipeRepository) SaveRecipe(ctx context.Context, rec recipe.Recipe) (*
 		} else if err != nil {
 			return nil, err
 		}
-		if ingredientSet[ingRow.Uuid] {
-			continue // already inserted for this recipe
-		}
-		ingredientSet[ingRow.Uuid] = true
 		// Unit
-		unitName := normalizeUnitName(ri.Unit.Name)
 		var unitID uuid.NullUUID
-		if unitName != "" {
-			var unitRow db.Unit
-			unitRow, err = q.GetUnitByName(ctx, unitName)
-			if err == sql.ErrNoRows {
-				unitRow, err = q.CreateUnit(ctx, db.CreateUnitParams{
-					Uuid:         uuid.New(),
-					Name:         unitName,
-					Abbreviation: sql.NullString{String: ri.Unit.Abbreviation, Valid: ri.Unit.Abbreviation != ""},
-					CreatedAt:    now,
-					UpdatedAt:    now,
-				})
-				if err != nil {
-					return nil, err
-				}
-				r.logger.Info().Msgf("Inserted new unit: %s (UUID: %s)", unitName, unitRow.Uuid)
-			} else if err != nil {
-				return nil, err
-			}
-			unitID = uuidToNullUUID(&unitRow.Uuid)
+		var unitText sql.NullString
+		unitID, unitText, err = r.resolveUnit(ctx, q, ri.Unit.Name)
+		if err != nil {
+			return nil, err
 		}
 		// RecipeIngredient
 		_, err = q.CreateRecipeIngredient(ctx, db.CreateRecipeIngredientParams{
 			RecipeID:     recipeID,
 			IngredientID: ingRow.Uuid,
 			UnitID:       unitID,
+			UnitText:     unitText,
 			Quantity:     sql.NullFloat64{Float64: ri.Quantity, Valid: true},
 			Preparation:  sql.NullString{String: ri.Preparation, Valid: ri.Preparation != ""},
 			Component:    sql.NullString{String: ri.Component, Valid: ri.Component != ""},
 			CreatedAt:    now,
 			UpdatedAt:    now,
+			Position:     int32(i),
 		})
 		if err != nil {
 			return nil, err
@@ -231,6 +288,10 @@ func (r *recipeRepository) SaveRecipe(ctx context.Context, rec recipe.Recipe) (*
 		r.logger.Info().Msgf("Linked label %s:%s to recipe %s", label.Type, label.Name, rec.Name)
 	}
 
+	if err = saveRecipeEquipment(ctx, q, recipeID, rec.Equipment); err != nil {
+		return nil, err
+	}
+
 	// Insert recipe photos (not main photo)
 	for _, photo := range rec.Photos {
 		if rec.MainPhoto != nil && photo.URL == rec.MainPhoto.URL {
@@ -250,6 +311,10 @@ func (r *recipeRepository) SaveRecipe(ctx context.Context, rec recipe.Recipe) (*
 		r.logger.Info().Msgf("Inserted recipe photo for recipe %s: %s", rec.Name, photo.URL)
 	}
 
+	if err = r.recordRevision(ctx, q, recipeID, recipe.RevisionSourceFrom(ctx), recipe.RevisionNoteFrom(ctx)); err != nil {
+		return nil, err
+	}
+
 	r.logger.Info().Msgf("Successfully saved recipe: %s (UUID: %s)", rec.Name, recipeID)
 
 	// Update the recipe with the saved UUID and timestamps
@@ -267,8 +332,17 @@ func uuidToNullUUID(id *uuid.UUID) uuid.NullUUID {
 	return uuid.NullUUID{UUID: *id, Valid: true}
 }
 
+// marshalStepDetail encodes a step's timers, temperatures or ingredient lines
+// for storage. A nil slice is stored as JSON null, "not supplied".
+func marshalStepDetail(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// normalizeUnitName is the key unit text is queued for review under, so
+// "Glug" and "glug " are one entry.
 func normalizeUnitName(name string) string {
-	return strings.ToLower(strings.TrimSpace(name))
+	return strings.ToLower(recipe.NormalizeUnitName(name))
 }
 
 func (r *recipeRepository) GetRecipeByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
@@ -283,12 +357,33 @@ func (r *recipeRepository) GetRecipeByID(ctx context.Context, id uuid.UUID) (*re
 		return nil, err
 	}
 
-	return r.buildRecipeFromRows(ctx, q, recipeRow.Uuid, recipeRow.Name, recipeRow.Description,
+	rec, err := r.buildRecipeFromRows(ctx, q, recipeRow.Uuid, recipeRow.Name, recipeRow.Description,
 		recipeRow.CookTime, recipeRow.PrepTime, recipeRow.Servings, recipeRow.Url,
 		recipeRow.CreatedAt, recipeRow.UpdatedAt, recipeRow.MainPhotoUuid, recipeRow.MainPhotoUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipeRow.ParentUuid.Valid {
+		rec.Parent = &recipe.RecipeRef{UUID: recipeRow.ParentUuid.UUID, Name: recipeRow.ParentName.String}
+	}
+	if rec.Variants, err = r.listVariants(ctx, q, id); err != nil {
+		return nil, err
+	}
+	stats, err := q.GetRecipeCookStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rec.CookStats = cookStats(stats.TimesCooked, stats.LastCooked, stats.AverageRating)
+	annotations, err := r.listAnnotations(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	recipe.AttachAnnotations(rec, annotations)
+	return rec, nil
 }
 
-func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string) ([]*recipe.Recipe, int, error) {
+func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, search string, labels []string, sort string, ownedEquipmentOnly bool, avoidIngredients []string) ([]*recipe.Recipe, int, error) {
 	q := r.db
 
 	// Prepare search parameter
@@ -300,7 +395,11 @@ func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, s
 	// If no labels filter is provided, use standard query
 	if len(labels) == 0 {
 		// Get count first
-		count, err := q.CountRecipes(ctx, search)
+		count, err := q.CountRecipes(ctx, db.CountRecipesParams{
+			Column1: search,
+			Column2: ownedEquipmentOnly,
+			Column3: avoidIngredients,
+		})
 		if err != nil {
 			return nil, 0, err
 		}
@@ -311,6 +410,8 @@ func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, s
 			Offset:  int32(offset),
 			Column3: search,
 			Column4: sort,
+			Column5: ownedEquipmentOnly,
+			Column6: avoidIngredients,
 		})
 		if err != nil {
 			return nil, 0, err
@@ -325,6 +426,7 @@ func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, s
 				return nil, 0, err
 			}
 			rec.IsInMealPlan = row.IsInMealPlan
+			rec.CookStats = cookStats(row.TimesCooked, row.LastCooked, row.AverageRating)
 			recipes[i] = rec
 		}
 
@@ -334,8 +436,10 @@ func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, s
 	// Use label filtering query
 	// Get count first
 	count, err := q.CountRecipesWithLabels(ctx, db.CountRecipesWithLabelsParams{
-		Search:     searchParam,
-		LabelKeys: labels,
+		Search:             searchParam,
+		LabelKeys:          labels,
+		OwnedEquipmentOnly: ownedEquipmentOnly,
+		AvoidIngredients:   avoidIngredients,
 	})
 	if err != nil {
 		return nil, 0, err
@@ -343,10 +447,13 @@ func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, s
 
 	// Get recipes with meal plan status and label filtering
 	recipeRows, err := q.ListRecipesWithMealPlanStatusAndLabels(ctx, db.ListRecipesWithMealPlanStatusAndLabelsParams{
-		Search:       searchParam,
-		LabelKeys:    labels,
-		RecipeLimit:  int32(limit),
-		RecipeOffset: int32(offset),
+		Search:             searchParam,
+		LabelKeys:          labels,
+		Sort:               sort,
+		OwnedEquipmentOnly: ownedEquipmentOnly,
+		AvoidIngredients:   avoidIngredients,
+		RecipeLimit:        int32(limit),
+		RecipeOffset:       int32(offset),
 	})
 	if err != nil {
 		return nil, 0, err
@@ -361,6 +468,7 @@ func (r *recipeRepository) ListRecipes(ctx context.Context, limit, offset int, s
 			return nil, 0, err
 		}
 		rec.IsInMealPlan = row.IsInMealPlan
+		rec.CookStats = cookStats(row.TimesCooked, row.LastCooked, row.AverageRating)
 		recipes[i] = rec
 	}
 
@@ -403,8 +511,23 @@ func (r *recipeRepository) buildRecipeFromRows(ctx context.Context, q *db.Querie
 		steps[i] = recipe.Step{
 			Order:       stepRow.StepOrder,
 			Description: stepRow.Description.String,
+			Section:     stepRow.Section.String,
+		}
+		// JSON null (steps saved before details existed) leaves these nil,
+		// and FillStepDetails parses them below.
+		if err := json.Unmarshal(stepRow.Timers, &steps[i].Timers); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(stepRow.Temperatures, &steps[i].Temperatures); err != nil {
+			return nil, err
 		}
+		var lines []int
+		if err := json.Unmarshal(stepRow.IngredientLines, &lines); err != nil {
+			return nil, err
+		}
+		steps[i].Ingredients = recipe.StepIngredientsFromLines(lines)
 	}
+	recipe.FillStepDetails(steps)
 	rec.Steps = steps
 
 	// Get ingredients
@@ -420,7 +543,7 @@ func (r *recipeRepository) buildRecipeFromRows(ctx context.Context, q *db.Querie
 				Name: ingRow.IngredientName,
 			},
 			Unit: recipe.Unit{
-				Name:         ingRow.UnitName.String,
+				Name:         ingRow.UnitName,
 				Abbreviation: ingRow.UnitAbbreviation.String,
 			},
 			Quantity:    ingRow.Quantity.Float64,
@@ -428,7 +551,14 @@ func (r *recipeRepository) buildRecipeFromRows(ctx context.Context, q *db.Querie
 			Component:   ingRow.Component.String,
 		}
 	}
-	rec.Ingredients = ingredients
+	rec.Ingredients, err = spliceSubRecipeLines(ctx, q, recipeUUID, ingredients)
+	if err != nil {
+		return nil, err
+	}
+	// Steps are read before ingredients, so their links are filled in (or,
+	// for steps saved before links existed, inferred) now. Saves link with
+	// the catalogue's aliases; this only catches steps that predate links.
+	recipe.LinkStepIngredients(rec.Steps, rec.Ingredients, nil)
 
 	// Get labels
 	labelRows, err := q.GetLabelsByRecipeID(ctx, recipeUUID)
@@ -445,6 +575,11 @@ func (r *recipeRepository) buildRecipeFromRows(ctx context.Context, q *db.Querie
 	}
 	rec.Labels = labels
 
+	rec.Equipment, err = recipeEquipment(ctx, q, recipeUUID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get photos
 	photoRows, err := q.GetPhotosByRecipeID(ctx, recipeUUID)
 	if err != nil {
@@ -478,6 +613,13 @@ func (r *recipeRepository) UpdateRecipe(ctx context.Context, id uuid.UUID, rec r
 		}
 	}()
 
+	if err = q.LockRecipeForRevision(ctx, id); err != nil {
+		return nil, err
+	}
+	if err = r.recordBaselineIfMissing(ctx, q, id); err != nil {
+		return nil, err
+	}
+
 	// Resolve the main photo. We reuse the existing photo row when the URL is
 	// unchanged (the common case — editing a recipe must not drop or duplicate
 	// its photo) and only create a new row for a genuinely new URL. A nil
@@ -534,6 +676,20 @@ func (r *recipeRepository) UpdateRecipe(ctx context.Context, id uuid.UUID, rec r
 	recipeID := updatedRecipe.Uuid
 	recipeNullUUID := uuid.NullUUID{UUID: recipeID, Valid: true}
 
+	// Step notes point at step positions; move them with their steps before
+	// the old steps go.
+	oldSteps, err := q.GetStepsByRecipeID(ctx, recipeNullUUID)
+	if err != nil {
+		return nil, err
+	}
+	before := make([]recipe.Step, len(oldSteps))
+	for i, row := range oldSteps {
+		before[i] = recipe.Step{Order: row.StepOrder, Description: row.Description.String}
+	}
+	if err = reanchorAnnotations(ctx, q, recipeID, before, rec.Steps); err != nil {
+		return nil, err
+	}
+
 	// Delete existing step photos, steps, ingredients, and labels
 	err = q.DeleteStepPhotosByRecipeID(ctx, recipeNullUUID)
 	if err != nil {
@@ -551,17 +707,29 @@ func (r *recipeRepository) UpdateRecipe(ctx context.Context, id uuid.UUID, rec r
 	if err != nil {
 		return nil, err
 	}
+	err = q.DeleteRecipeSubRecipesByRecipeID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	err = q.DeleteRecipeEquipmentByRecipeID(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Re-insert steps
 	for _, step := range rec.Steps {
 		stepUUID := uuid.New()
 		stepRow, err := q.CreateStep(ctx, db.CreateStepParams{
-			Uuid:        stepUUID,
-			RecipeID:    uuidToNullUUID(&recipeID),
-			StepOrder:   step.Order,
-			Description: sql.NullString{String: step.Description, Valid: step.Description != ""},
-			CreatedAt:   now,
-			UpdatedAt:   now,
+			Uuid:            stepUUID,
+			RecipeID:        uuidToNullUUID(&recipeID),
+			StepOrder:       step.Order,
+			Description:     sql.NullString{String: step.Description, Valid: step.Description != ""},
+			Section:         sql.NullString{String: step.Section, Valid: step.Section != ""},
+			CreatedAt:       now,
+			UpdatedAt:       now,
+			Timers:          marshalStepDetail(step.Timers),
+			Temperatures:    marshalStepDetail(step.Temperatures),
+			IngredientLines: marshalStepDetail(recipe.StepIngredientLines(step)),
 		})
 		if err != nil {
 			return nil, err
@@ -584,10 +752,18 @@ func (r *recipeRepository) UpdateRecipe(ctx context.Context, id uuid.UUID, rec r
 	}
 
 	// Re-insert ingredients and recipe_ingredient
-	ingredientSet := make(map[uuid.UUID]bool)
-	for _, ri := range rec.Ingredients {
+	for i, ri := range rec.Ingredients {
+		if ri.SubRecipe != nil {
+			if err = r.saveSubRecipeLine(ctx, q, recipeID, i, ri, now); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		var ingRow db.Ingredient
-		ingRow, err = q.GetIngredientByName(ctx, ri.Ingredient.Name)
+		ingRow, err = q.GetIngredientByName(ctx, db.GetIngredientByNameParams{
+			Name:  ri.Ingredient.Name,
+			Forms: recipe.IngredientNameForms(ri.Ingredient.Name),
+		})
 		if err == sql.ErrNoRows {
 			ingRow, err = q.CreateIngredient(ctx, db.CreateIngredientParams{
 				Uuid:      uuid.New(),
@@ -601,42 +777,25 @@ func (r *recipeRepository) UpdateRecipe(ctx context.Context, id uuid.UUID, rec r
 		} else if err != nil {
 			return nil, err
 		}
-		if ingredientSet[ingRow.Uuid] {
-			continue
-		}
-		ingredientSet[ingRow.Uuid] = true
 
-		unitName := normalizeUnitName(ri.Unit.Name)
 		var unitID uuid.NullUUID
-		if unitName != "" {
-			var unitRow db.Unit
-			unitRow, err = q.GetUnitByName(ctx, unitName)
-			if err == sql.ErrNoRows {
-				unitRow, err = q.CreateUnit(ctx, db.CreateUnitParams{
-					Uuid:         uuid.New(),
-					Name:         unitName,
-					Abbreviation: sql.NullString{String: ri.Unit.Abbreviation, Valid: ri.Unit.Abbreviation != ""},
-					CreatedAt:    now,
-					UpdatedAt:    now,
-				})
-				if err != nil {
-					return nil, err
-				}
-			} else if err != nil {
-				return nil, err
-			}
-			unitID = uuidToNullUUID(&unitRow.Uuid)
+		var unitText sql.NullString
+		unitID, unitText, err = r.resolveUnit(ctx, q, ri.Unit.Name)
+		if err != nil {
+			return nil, err
 		}
 
 		_, err = q.CreateRecipeIngredient(ctx, db.CreateRecipeIngredientParams{
 			RecipeID:     recipeID,
 			IngredientID: ingRow.Uuid,
 			UnitID:       unitID,
+			UnitText:     unitText,
 			Quantity:     sql.NullFloat64{Float64: ri.Quantity, Valid: true},
 			Preparation:  sql.NullString{String: ri.Preparation, Valid: ri.Preparation != ""},
 			Component:    sql.NullString{String: ri.Component, Valid: ri.Component != ""},
 			CreatedAt:    now,
 			UpdatedAt:    now,
+			Position:     int32(i),
 		})
 		if err != nil {
 			return nil, err
@@ -675,6 +834,14 @@ func (r *recipeRepository) UpdateRecipe(ctx context.Context, id uuid.UUID, rec r
 		}
 	}
 
+	if err = saveRecipeEquipment(ctx, q, recipeID, rec.Equipment); err != nil {
+		return nil, err
+	}
+
+	if err = r.recordRevision(ctx, q, recipeID, recipe.RevisionSourceFrom(ctx), recipe.RevisionNoteFrom(ctx)); err != nil {
+		return nil, err
+	}
+
 	r.logger.Info().Str("recipe_id", id.String()).Msg("Recipe updated successfully")
 
 	rec.UUID = recipeID
@@ -764,8 +931,15 @@ func (r *recipeRepository) ListArchivedRecipes(ctx context.Context, limit, offse
 	return recipes, int(count), nil
 }
 
-func (r *recipeRepository) AddToMealPlan(ctx context.Context, recipeID uuid.UUID) error {
-	return r.db.AddToMealPlan(ctx, recipeID)
+func (r *recipeRepository) AddToMealPlan(ctx context.Context, recipeID uuid.UUID, plannedFor *time.Time) error {
+	var day sql.NullTime
+	if plannedFor != nil {
+		day = sql.NullTime{Time: *plannedFor, Valid: true}
+	}
+	return r.db.AddToMealPlan(ctx, db.AddToMealPlanParams{
+		RecipeID:   recipeID,
+		PlannedFor: day,
+	})
 }
 
 func (r *recipeRepository) RemoveFromMealPlan(ctx context.Context, recipeID uuid.UUID) error {
@@ -787,6 +961,12 @@ func (r *recipeRepository) ListMealPlanRecipes(ctx context.Context) ([]*recipe.R
 			return nil, err
 		}
 		rec.IsInMealPlan = row.IsInMealPlan
+		rec.CookStats = cookStats(row.TimesCooked, row.LastCooked, row.AverageRating)
+		if row.PlannedFor.Valid {
+			plannedFor := row.PlannedFor.Time
+			rec.PlannedFor = &plannedFor
+		}
+		rec.MealPlanAddedAt = row.AddedAt
 		recipes[i] = rec
 	}
 
@@ -800,31 +980,20 @@ func (r *recipeRepository) ListLabels(ctx context.Context) ([]recipe.LabelSummar
 	}
 	out := make([]recipe.LabelSummary, len(rows))
 	for i, row := range rows {
+		display := row.DisplayName
+		if display == "" {
+			display = recipe.DefaultDisplayName(row.Name)
+		}
 		out[i] = recipe.LabelSummary{
-			Type: row.Type,
-			Name: row.Name,
-			Uses: int(row.Uses),
+			Type:        row.Type,
+			Name:        row.Name,
+			DisplayName: display,
+			Uses:        int(row.Uses),
 		}
 	}
 	return out, nil
 }
 
-func (r *recipeRepository) ListUnits(ctx context.Context) ([]recipe.Unit, error) {
-	rows, err := r.db.ListUnits(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	units := make([]recipe.Unit, len(rows))
-	for i, row := range rows {
-		units[i] = recipe.Unit{
-			Name:         row.Name,
-			Abbreviation: row.Abbreviation.String,
-		}
-	}
-	return units, nil
-}
-
 func (r *recipeRepository) ListIngredients(ctx context.Context) ([]recipe.Ingredient, error) {
 	rows, err := r.db.ListIngredients(ctx)
 	if err != nil {
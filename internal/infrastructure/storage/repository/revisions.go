@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+// recordRevision snapshots the recipe as it currently stands in q (normally
+// the transaction that just changed it) as its next revision.
+func (r *recipeRepository) recordRevision(ctx context.Context, q *db.Queries, recipeID uuid.UUID, source, note string) error {
+	row, err := q.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return err
+	}
+	rec, err := r.buildRecipeFromRows(ctx, q, row.Uuid, row.Name, row.Description,
+		row.CookTime, row.PrepTime, row.Servings, row.Url,
+		row.CreatedAt, row.UpdatedAt, row.MainPhotoUuid, row.MainPhotoUrl)
+	if err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	rev, err := q.CreateRecipeRevision(ctx, db.CreateRecipeRevisionParams{
+		RecipeID: recipeID,
+		Source:   source,
+		Note:     sql.NullString{String: note, Valid: note != ""},
+		Snapshot: snapshot,
+	})
+	if err != nil {
+		return err
+	}
+	r.logger.Info().Str("recipe_id", recipeID.String()).Int32("revision", rev.Revision).Str("source", source).Msg("Recorded recipe revision")
+	return nil
+}
+
+// recordBaselineIfMissing snapshots a recipe that predates revision history
+// before its first edit, so that edit can be undone too.
+func (r *recipeRepository) recordBaselineIfMissing(ctx context.Context, q *db.Queries, recipeID uuid.UUID) error {
+	count, err := q.CountRecipeRevisions(ctx, recipeID)
+	if err != nil || count > 0 {
+		return err
+	}
+	err = r.recordRevision(ctx, q, recipeID, recipe.RevisionSourceBaseline, "")
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing to snapshot; UpdateRecipe reports the missing recipe itself.
+		return nil
+	}
+	return err
+}
+
+func (r *recipeRepository) ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]recipe.Revision, error) {
+	rows, err := r.db.ListRecipeRevisions(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]recipe.Revision, len(rows))
+	for i, row := range rows {
+		revisions[i] = recipe.Revision{
+			Number:    int(row.Revision),
+			Source:    row.Source,
+			Note:      row.Note.String,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return revisions, nil
+}
+
+func (r *recipeRepository) GetRevision(ctx context.Context, recipeID uuid.UUID, number int) (*recipe.Revision, error) {
+	row, err := r.db.GetRecipeRevision(ctx, db.GetRecipeRevisionParams{
+		RecipeID: recipeID,
+		Revision: int32(number),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, recipe.RevisionNotFoundError{RecipeID: recipeID, Revision: number}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot recipe.Recipe
+	if err := json.Unmarshal(row.Snapshot, &snapshot); err != nil {
+		return nil, err
+	}
+	return &recipe.Revision{
+		Number:    int(row.Revision),
+		Source:    row.Source,
+		Note:      row.Note.String,
+		CreatedAt: row.CreatedAt,
+		Recipe:    &snapshot,
+	}, nil
+}
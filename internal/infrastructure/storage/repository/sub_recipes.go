@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+// saveSubRecipeLine stores an ingredient line that refers to another recipe.
+// position is the line's index in the recipe's ingredient list. The referenced
+// recipe must exist and must not already use recipeID, at any depth, or the
+// recipe would contain itself.
+func (r *recipeRepository) saveSubRecipeLine(ctx context.Context, q *db.Queries, recipeID uuid.UUID, position int, ri recipe.RecipeIngredient, now time.Time) error {
+	subID := ri.SubRecipe.UUID
+	reject := func(reason string) error {
+		return recipe.SubRecipeError{RecipeID: recipeID, SubRecipeID: subID, Reason: reason}
+	}
+
+	unit, ok := recipe.NormalizeSubRecipeUnit(ri.Unit.Name)
+	if !ok {
+		return reject("quantity must be in servings or batches, not " + ri.Unit.Name)
+	}
+	if subID == recipeID {
+		return reject("a recipe can't use itself")
+	}
+	if _, err := q.GetRecipeByID(ctx, subID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reject("no such recipe")
+		}
+		return err
+	}
+	cycle, err := q.SubRecipeReaches(ctx, db.SubRecipeReachesParams{SubRecipeID: subID, Target: recipeID})
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return reject("it already uses this recipe, so this would be circular")
+	}
+
+	err = q.CreateRecipeSubRecipe(ctx, db.CreateRecipeSubRecipeParams{
+		RecipeID:    recipeID,
+		Position:    int32(position),
+		SubRecipeID: subID,
+		Quantity:    ri.Quantity,
+		Unit:        unit,
+		Component:   sql.NullString{String: ri.Component, Valid: ri.Component != ""},
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return err
+	}
+	r.logger.Info().Msgf("Linked sub-recipe %s to recipe %s", subID, recipeID)
+	return nil
+}
+
+// spliceSubRecipeLines puts a recipe's sub-recipe lines back among its
+// ingredients at the positions they were saved in.
+func spliceSubRecipeLines(ctx context.Context, q *db.Queries, recipeID uuid.UUID, ingredients []recipe.RecipeIngredient) ([]recipe.RecipeIngredient, error) {
+	rows, err := q.GetSubRecipesByRecipeID(ctx, recipeID)
+	if err != nil || len(rows) == 0 {
+		return ingredients, err
+	}
+
+	for _, row := range rows {
+		line := recipe.RecipeIngredient{
+			Ingredient: recipe.Ingredient{Name: row.SubRecipeName},
+			Unit:       recipe.Unit{Name: row.Unit},
+			Quantity:   row.Quantity,
+			Component:  row.Component.String,
+			SubRecipe:  &recipe.SubRecipe{UUID: row.SubRecipeID},
+		}
+		at := min(int(row.Position), len(ingredients))
+		ingredients = append(ingredients[:at], append([]recipe.RecipeIngredient{line}, ingredients[at:]...)...)
+	}
+	return ingredients, nil
+}
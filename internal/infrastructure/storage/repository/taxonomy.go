@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+func (r *recipeRepository) ListTaxonomy(ctx context.Context) (recipe.Taxonomy, error) {
+	types, err := r.db.ListLabelTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.ListLabelDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := map[string][]recipe.LabelDefinition{}
+	for _, row := range rows {
+		d := labelDefinitionFromRow(db.Label{
+			Type:        row.Type,
+			Name:        row.Name,
+			DisplayName: row.DisplayName,
+			Description: row.Description,
+			Aliases:     row.Aliases,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		})
+		d.Uses = int(row.Uses)
+		byType[row.Type] = append(byType[row.Type], d)
+	}
+
+	taxonomy := make(recipe.Taxonomy, len(types))
+	for i, row := range types {
+		lt := labelTypeFromRow(row)
+		lt.Labels = byType[row.Name]
+		if lt.Labels == nil {
+			lt.Labels = []recipe.LabelDefinition{}
+		}
+		taxonomy[i] = lt
+	}
+	return taxonomy, nil
+}
+
+func (r *recipeRepository) SaveLabelType(ctx context.Context, lt recipe.LabelType) (*recipe.LabelType, error) {
+	row, err := r.db.UpsertLabelType(ctx, db.UpsertLabelTypeParams{
+		Name:        lt.Name,
+		DisplayName: lt.DisplayName,
+		Description: lt.Description,
+		Multiple:    lt.Multiple,
+		Position:    int32(lt.Position),
+	})
+	if err != nil {
+		return nil, err
+	}
+	saved := labelTypeFromRow(row)
+	return &saved, nil
+}
+
+func (r *recipeRepository) SaveLabel(ctx context.Context, d recipe.LabelDefinition) (*recipe.LabelDefinition, error) {
+	row, err := r.db.UpsertLabel(ctx, db.UpsertLabelParams{
+		Uuid:        uuid.New(),
+		Type:        d.Type,
+		Name:        d.Name,
+		DisplayName: d.DisplayName,
+		Description: d.Description,
+		Aliases:     nonNilStrings(d.Aliases),
+	})
+	if err != nil {
+		return nil, err
+	}
+	saved := labelDefinitionFromRow(row)
+	return &saved, nil
+}
+
+// RenameLabel renames the label typ:from to.Name in place, so recipes
+// carrying it keep it, and sets its display name and aliases.
+func (r *recipeRepository) RenameLabel(ctx context.Context, typ, from string, to recipe.LabelDefinition) (*recipe.LabelDefinition, error) {
+	row, err := r.db.RenameLabel(ctx, db.RenameLabelParams{
+		Type:        typ,
+		Name:        from,
+		Name_2:      to.Name,
+		DisplayName: to.DisplayName,
+		Aliases:     nonNilStrings(to.Aliases),
+	})
+	if err == sql.ErrNoRows {
+		return nil, recipe.LabelNotFoundError{Type: typ, Name: from}
+	}
+	if err != nil {
+		return nil, err
+	}
+	saved := labelDefinitionFromRow(row)
+	return &saved, nil
+}
+
+func (r *recipeRepository) ListLabelRecipes(ctx context.Context, label recipe.Label) ([]recipe.RecipeRef, error) {
+	row, err := r.db.GetLabelByTypeAndName(ctx, db.GetLabelByTypeAndNameParams{Type: label.Type, Name: label.Name})
+	if err == sql.ErrNoRows {
+		return nil, recipe.LabelNotFoundError{Type: label.Type, Name: label.Name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return listLabelRecipes(ctx, r.db, row.Uuid)
+}
+
+// MergeLabels moves every recipe labelled from onto into, deletes from, and
+// sets into's aliases, all or nothing. A recipe already carrying both keeps a
+// single into. It returns the recipes that carried from.
+func (r *recipeRepository) MergeLabels(ctx context.Context, from, into recipe.Label, aliases []string) (recipes []recipe.RecipeRef, err error) {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	var fromRow, intoRow db.Label
+	fromRow, err = q.GetLabelByTypeAndName(ctx, db.GetLabelByTypeAndNameParams{Type: from.Type, Name: from.Name})
+	if err == sql.ErrNoRows {
+		err = recipe.LabelNotFoundError{Type: from.Type, Name: from.Name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	intoRow, err = q.GetLabelByTypeAndName(ctx, db.GetLabelByTypeAndNameParams{Type: into.Type, Name: into.Name})
+	if err == sql.ErrNoRows {
+		err = recipe.LabelNotFoundError{Type: into.Type, Name: into.Name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if recipes, err = listLabelRecipes(ctx, q, fromRow.Uuid); err != nil {
+		return nil, err
+	}
+
+	if err = q.CopyRecipeLabels(ctx, db.CopyRecipeLabelsParams{IntoID: intoRow.Uuid, FromID: fromRow.Uuid}); err != nil {
+		return nil, err
+	}
+	if err = q.DeleteRecipeLabelsByLabelID(ctx, fromRow.Uuid); err != nil {
+		return nil, err
+	}
+	if err = q.DeleteLabel(ctx, fromRow.Uuid); err != nil {
+		return nil, err
+	}
+	err = q.SetLabelAliases(ctx, db.SetLabelAliasesParams{Uuid: intoRow.Uuid, Aliases: nonNilStrings(aliases)})
+	if err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+func listLabelRecipes(ctx context.Context, q *db.Queries, labelID uuid.UUID) ([]recipe.RecipeRef, error) {
+	rows, err := q.ListLabelRecipes(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	recipes := make([]recipe.RecipeRef, len(rows))
+	for i, row := range rows {
+		recipes[i] = recipe.RecipeRef{UUID: row.Uuid, Name: row.Name}
+	}
+	return recipes, nil
+}
+
+func labelTypeFromRow(row db.LabelType) recipe.LabelType {
+	return recipe.LabelType{
+		Name:        row.Name,
+		DisplayName: row.DisplayName,
+		Description: row.Description,
+		Multiple:    row.Multiple,
+		Position:    int(row.Position),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+// labelDefinitionFromRow fills in the display name of a label created by a
+// recipe save, which has none.
+func labelDefinitionFromRow(row db.Label) recipe.LabelDefinition {
+	display := row.DisplayName
+	if display == "" {
+		display = recipe.DefaultDisplayName(row.Name)
+	}
+	return recipe.LabelDefinition{
+		Type:        row.Type,
+		Name:        row.Name,
+		DisplayName: display,
+		Description: row.Description,
+		Aliases:     nonNilStrings(row.Aliases),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+// resolveUnit finds the unit a recipe line's unit text means. Text that
+// resolves to no unit is returned to be kept on the line as written, and is
+// queued for review rather than becoming a unit.
+func (r *recipeRepository) resolveUnit(ctx context.Context, q *db.Queries, text string) (uuid.NullUUID, sql.NullString, error) {
+	text = recipe.NormalizeUnitName(text)
+	if text == "" {
+		return uuid.NullUUID{}, sql.NullString{}, nil
+	}
+	row, err := q.ResolveUnit(ctx, db.ResolveUnitParams{Name: text, Forms: recipe.UnitNameForms(text)})
+	if err == nil {
+		return uuidToNullUUID(&row.Uuid), sql.NullString{}, nil
+	}
+	if err != sql.ErrNoRows {
+		return uuid.NullUUID{}, sql.NullString{}, err
+	}
+	if err = q.QueueUnitReview(ctx, normalizeUnitName(text)); err != nil {
+		return uuid.NullUUID{}, sql.NullString{}, err
+	}
+	r.logger.Info().Msgf("Queued unknown unit for review: %s", text)
+	return uuid.NullUUID{}, sql.NullString{String: text, Valid: true}, nil
+}
+
+func unitFromRow(row db.Unit) recipe.Unit {
+	return recipe.Unit{
+		Name:         row.Name,
+		Abbreviation: row.Abbreviation.String,
+		Aliases:      nonNilStrings(row.Aliases),
+		Dimension:    recipe.UnitDimension(row.Dimension),
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
+
+func (r *recipeRepository) ListUnits(ctx context.Context) (recipe.UnitRegistry, error) {
+	rows, err := r.db.ListUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units := make(recipe.UnitRegistry, len(rows))
+	for i, row := range rows {
+		units[i] = unitFromRow(row)
+	}
+	return units, nil
+}
+
+// SaveUnit creates or updates u, then gives it any lines queued with unit
+// text it now matches, all or nothing.
+func (r *recipeRepository) SaveUnit(ctx context.Context, u recipe.Unit) (*recipe.Unit, error) {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	var row db.Unit
+	row, err = q.UpsertUnit(ctx, db.UpsertUnitParams{
+		Name:         u.Name,
+		Abbreviation: sql.NullString{String: u.Abbreviation, Valid: u.Abbreviation != ""},
+		Dimension:    string(u.Dimension),
+		Aliases:      nonNilStrings(u.Aliases),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	forms := nonNilStrings(recipe.UnitSpellings(u))
+	if _, err = q.AdoptUnitText(ctx, db.AdoptUnitTextParams{UnitID: row.Uuid, Forms: forms}); err != nil {
+		return nil, err
+	}
+	if _, err = q.DeleteUnitReviews(ctx, forms); err != nil {
+		return nil, err
+	}
+	saved := unitFromRow(row)
+	return &saved, nil
+}
+
+// MergeUnits moves every recipe line using from onto into, deletes from, and
+// sets into's aliases, all or nothing.
+func (r *recipeRepository) MergeUnits(ctx context.Context, from, into string, aliases []string) error {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	q := db.New(tx)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	var fromRow, intoRow db.Unit
+	fromRow, err = q.GetUnitByName(ctx, from)
+	if err == sql.ErrNoRows {
+		err = recipe.UnitNotFoundError{Name: from}
+	}
+	if err != nil {
+		return err
+	}
+	intoRow, err = q.GetUnitByName(ctx, into)
+	if err == sql.ErrNoRows {
+		err = recipe.UnitNotFoundError{Name: into}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = q.RepointUnit(ctx, db.RepointUnitParams{IntoID: intoRow.Uuid, FromID: fromRow.Uuid}); err != nil {
+		return err
+	}
+	if err = q.DeleteUnit(ctx, fromRow.Uuid); err != nil {
+		return err
+	}
+	_, err = q.SetUnitAliases(ctx, db.SetUnitAliasesParams{Name: into, Aliases: nonNilStrings(aliases)})
+	return err
+}
+
+func (r *recipeRepository) ListUnitReviews(ctx context.Context) ([]recipe.UnitReview, error) {
+	rows, err := r.db.ListUnitReviews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reviews := make([]recipe.UnitReview, len(rows))
+	for i, row := range rows {
+		reviews[i] = recipe.UnitReview{
+			Name:      row.Name,
+			Lines:     int(row.Lines),
+			Recipes:   int(row.Recipes),
+			FirstSeen: row.FirstSeen,
+			LastSeen:  row.LastSeen,
+		}
+	}
+	return reviews, nil
+}
+
+// DismissUnitReview takes name off the review queue for good: saving a line
+// with it again doesn't bring it back. Lines using it keep their unit text.
+func (r *recipeRepository) DismissUnitReview(ctx context.Context, name string) error {
+	n, err := r.db.DismissUnitReview(ctx, normalizeUnitName(name))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return recipe.UnitNotFoundError{Name: name}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/kieranajp/the-bluer-book/internal/domain/recipe"
+	"github.com/kieranajp/the-bluer-book/internal/infrastructure/storage/db"
+)
+
+// parentID is the parent_id column value for a recipe being saved.
+func parentID(parent *recipe.RecipeRef) uuid.NullUUID {
+	if parent == nil || parent.UUID == uuid.Nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: parent.UUID, Valid: true}
+}
+
+// listVariants returns the live recipes forked from recipeID, oldest first.
+func (r *recipeRepository) listVariants(ctx context.Context, q *db.Queries, recipeID uuid.UUID) ([]recipe.RecipeRef, error) {
+	rows, err := q.ListRecipeVariants(ctx, uuid.NullUUID{UUID: recipeID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	variants := make([]recipe.RecipeRef, len(rows))
+	for i, row := range rows {
+		variants[i] = recipe.RecipeRef{UUID: row.Uuid, Name: row.Name}
+	}
+	return variants, nil
+}
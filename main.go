@@ -3,8 +3,12 @@ package main
 import (
 	"os"
 
+	"github.com/kieranajp/the-bluer-book/cmd/buildsite"
+	"github.com/kieranajp/the-bluer-book/cmd/categorise"
 	fetchimages "github.com/kieranajp/the-bluer-book/cmd/fetchimages"
+	"github.com/kieranajp/the-bluer-book/cmd/lintlabels"
 	"github.com/kieranajp/the-bluer-book/cmd/migrate"
+	"github.com/kieranajp/the-bluer-book/cmd/seednutrition"
 	"github.com/kieranajp/the-bluer-book/cmd/server"
 	"github.com/kieranajp/the-bluer-book/cmd/tag"
 	"github.com/kieranajp/the-bluer-book/internal/infrastructure/logger"
@@ -34,6 +38,10 @@ func main() {
 			migrate.Command,
 			tag.Command,
 			fetchimages.Command,
+			buildsite.Command,
+			seednutrition.Command,
+			lintlabels.Command,
+			categorise.Command,
 		},
 	}
 
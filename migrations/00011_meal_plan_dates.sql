@@ -0,0 +1,10 @@
+-- +goose Up
+-- Give meal-plan entries an optional date. The plan itself stays a simple set
+-- of starred recipes; a date just says which evening a recipe is meant for, so
+-- the iCalendar feed can put it on a calendar. NULL means "planned, not yet
+-- scheduled".
+
+ALTER TABLE meal_plan_recipes ADD COLUMN planned_for DATE;
+
+-- +goose Down
+ALTER TABLE meal_plan_recipes DROP COLUMN IF EXISTS planned_for;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Short-lived public links to a read-only rendering of the shopping list or
+-- pantry, for whoever is doing the shop without the app. The token is the
+-- only credential, so it's random and unguessable; the link renders the list
+-- as it is when opened, until expires_at.
+
+CREATE TABLE list_shares (
+  token      VARCHAR PRIMARY KEY,
+  list       VARCHAR NOT NULL CHECK (list IN ('shopping_list', 'pantry')),
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_list_shares_expires_at ON list_shares (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS list_shares;
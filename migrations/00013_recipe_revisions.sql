@@ -0,0 +1,21 @@
+-- +goose Up
+-- Immutable snapshots of a recipe, one per create/update. UpdateRecipe
+-- deletes and re-inserts steps, ingredients and labels, so without this every
+-- edit (including an assistant's silent rewrite) destroys the previous
+-- version. The snapshot is the recipe's API JSON as it stood after the change;
+-- source records who made it (rest / mcp / chat), note anything notable (e.g.
+-- a revert). Recipes that predate this table get a "baseline" revision the
+-- first time they're edited.
+
+CREATE TABLE recipe_revisions (
+  recipe_id  UUID NOT NULL REFERENCES recipes (uuid) ON DELETE CASCADE,
+  revision   INTEGER NOT NULL,
+  source     VARCHAR NOT NULL,
+  note       VARCHAR,
+  snapshot   JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (recipe_id, revision)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS recipe_revisions;
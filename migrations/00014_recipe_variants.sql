@@ -0,0 +1,11 @@
+-- +goose Up
+-- A variant ("vegan version", "Gran's original") is an ordinary recipe that
+-- remembers the recipe it was forked from. Archiving a parent leaves its
+-- variants alone; hard-deleting one just drops the link.
+
+ALTER TABLE recipes ADD COLUMN parent_id UUID REFERENCES recipes (uuid) ON DELETE SET NULL;
+CREATE INDEX idx_recipes_parent_id ON recipes (parent_id) WHERE parent_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_recipes_parent_id;
+ALTER TABLE recipes DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- A recipe line can point at another recipe ("1 batch pizza dough") instead of
+-- an ingredient, so a shared component is written once. These lines live
+-- beside recipe_ingredient rather than in it because they have no
+-- ingredient_id; position is the line's index in the recipe's ingredient list,
+-- so reads can put it back where it was written. Quantity is in servings of
+-- the sub-recipe or whole batches of it.
+
+CREATE TABLE recipe_sub_recipes (
+  recipe_id     UUID NOT NULL REFERENCES recipes (uuid) ON DELETE CASCADE,
+  position      INTEGER NOT NULL,
+  sub_recipe_id UUID NOT NULL REFERENCES recipes (uuid),
+  quantity      DOUBLE PRECISION NOT NULL,
+  unit          VARCHAR NOT NULL CHECK (unit IN ('servings', 'batches')),
+  component     TEXT,
+  created_at    TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (recipe_id, position),
+  CHECK (recipe_id <> sub_recipe_id)
+);
+
+CREATE INDEX idx_recipe_sub_recipes_sub_recipe_id ON recipe_sub_recipes (sub_recipe_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS recipe_sub_recipes;
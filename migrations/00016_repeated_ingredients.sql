@@ -0,0 +1,36 @@
+-- +goose Up
+-- A recipe can use the same ingredient on more than one line ("butter, 100 g"
+-- for the pastry and "butter, 30 g" for the filling). Lines are keyed by their
+-- position in the recipe's ingredient list instead of by ingredient, which
+-- also lets the order they were written in survive a round trip. Existing
+-- rows are numbered in the order they used to be read back.
+
+ALTER TABLE recipe_ingredient ADD COLUMN position INTEGER;
+
+UPDATE recipe_ingredient ri
+SET position = numbered.position
+FROM (
+  SELECT recipe_id, ingredient_id,
+         row_number() OVER (
+           PARTITION BY recipe_id
+           ORDER BY component NULLS FIRST, created_at, ingredient_id
+         ) - 1 AS position
+  FROM recipe_ingredient
+) numbered
+WHERE ri.recipe_id = numbered.recipe_id AND ri.ingredient_id = numbered.ingredient_id;
+
+ALTER TABLE recipe_ingredient ALTER COLUMN position SET NOT NULL;
+ALTER TABLE recipe_ingredient DROP CONSTRAINT recipe_ingredient_pkey;
+ALTER TABLE recipe_ingredient ADD PRIMARY KEY (recipe_id, position);
+
+-- +goose Down
+-- Collapses repeated lines back to one per ingredient, keeping the first.
+DELETE FROM recipe_ingredient ri
+USING recipe_ingredient earlier
+WHERE ri.recipe_id = earlier.recipe_id
+  AND ri.ingredient_id = earlier.ingredient_id
+  AND ri.position > earlier.position;
+
+ALTER TABLE recipe_ingredient DROP CONSTRAINT recipe_ingredient_pkey;
+ALTER TABLE recipe_ingredient ADD PRIMARY KEY (recipe_id, ingredient_id);
+ALTER TABLE recipe_ingredient DROP COLUMN position;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Structured timers and temperatures for each step, so cooking mode and the
+-- assistant can run real timers rather than scrape the text. JSON null means
+-- "never supplied or parsed" (every step written before this), and reads
+-- parse those from the description; an empty array means "none".
+
+ALTER TABLE steps ADD COLUMN timers JSONB NOT NULL DEFAULT 'null';
+ALTER TABLE steps ADD COLUMN temperatures JSONB NOT NULL DEFAULT 'null';
+
+-- +goose Down
+ALTER TABLE steps DROP COLUMN IF EXISTS temperatures;
+ALTER TABLE steps DROP COLUMN IF EXISTS timers;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Which ingredient lines each step uses, as indexes into the recipe's
+-- ingredient lines (their position). JSON null means "never supplied or
+-- inferred", and reads infer them from the step text; an empty array means
+-- "none".
+
+ALTER TABLE steps ADD COLUMN ingredient_lines JSONB NOT NULL DEFAULT 'null';
+
+-- +goose Down
+ALTER TABLE steps DROP COLUMN IF EXISTS ingredient_lines;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Optional section headings on steps ("Make the dough", "Next day: shape and
+-- bake"). A section is the run of consecutive steps sharing a heading, so its
+-- order is the step order.
+
+ALTER TABLE steps ADD COLUMN section TEXT;
+
+-- +goose Down
+ALTER TABLE steps DROP COLUMN IF EXISTS section;
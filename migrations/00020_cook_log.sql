@@ -0,0 +1,32 @@
+-- +goose Up
+-- A log of when a recipe was actually cooked, with how it went. The meal plan
+-- says what we mean to cook; this records what we did, so recipes can be
+-- sorted by "haven't made that in ages" and the assistant can remember which
+-- ones went down well. Photos are plain URLs of images already uploaded.
+
+CREATE TABLE recipe_cooks (
+  uuid       UUID PRIMARY KEY,
+  recipe_id  UUID NOT NULL REFERENCES recipes (uuid) ON DELETE CASCADE,
+  cooked_on  DATE NOT NULL,
+  rating     SMALLINT CHECK (rating BETWEEN 1 AND 5),
+  notes      TEXT,
+  servings   SMALLINT,
+  photos     TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX recipe_cooks_recipe_id_idx ON recipe_cooks (recipe_id, cooked_on DESC);
+
+-- Per-recipe aggregates for summaries and the "not cooked recently" sort.
+-- Recipes never cooked have no row.
+CREATE VIEW recipe_cook_stats AS
+SELECT recipe_id,
+       COUNT(*)::int AS times_cooked,
+       MAX(cooked_on)::date AS last_cooked,
+       AVG(rating)::float8 AS average_rating
+FROM recipe_cooks
+GROUP BY recipe_id;
+
+-- +goose Down
+DROP VIEW IF EXISTS recipe_cook_stats;
+DROP TABLE IF EXISTS recipe_cooks;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Personal notes on a recipe or one of its steps ("use the big pan", "halve
+-- the chilli for the kids"). Kept apart from the recipe body because
+-- UpdateRecipe deletes and re-inserts steps: a step note points at the step's
+-- position (step_order) rather than its row, and UpdateRecipe moves it when
+-- its step moves. NULL step_order is a note on the recipe as a whole.
+
+CREATE TABLE recipe_annotations (
+  uuid       UUID PRIMARY KEY,
+  recipe_id  UUID NOT NULL REFERENCES recipes (uuid) ON DELETE CASCADE,
+  step_order SMALLINT CHECK (step_order > 0),
+  note       TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX recipe_annotations_recipe_id_idx ON recipe_annotations (recipe_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS recipe_annotations;
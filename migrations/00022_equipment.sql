@@ -0,0 +1,60 @@
+-- +goose Up
+-- Kitchen equipment. recipe_equipment is the kit each recipe needs beyond the
+-- everyday (names are lowercase snake_case, like labels); household_equipment
+-- is what we own. A recipe can be cooked here when everything it needs is
+-- owned. Names are free text rather than a lookup table, so "pasta_machine"
+-- needs no setup before a recipe can ask for one.
+
+CREATE TABLE recipe_equipment (
+  recipe_id UUID NOT NULL REFERENCES recipes (uuid) ON DELETE CASCADE,
+  name      TEXT NOT NULL CHECK (name <> ''),
+  PRIMARY KEY (recipe_id, name)
+);
+
+CREATE INDEX idx_recipe_equipment_name ON recipe_equipment (name);
+
+CREATE TABLE household_equipment (
+  name     TEXT PRIMARY KEY CHECK (name <> ''),
+  added_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Backfill existing recipes from their steps. These are the patterns
+-- recipe.SuggestEquipment uses (internal/domain/recipe/equipment.go); keep the
+-- two in sync. "unless" is stripped from the step first, so a stick blender
+-- isn't also a blender.
+CREATE TEMP TABLE equipment_patterns (
+    name    TEXT PRIMARY KEY,
+    pattern TEXT NOT NULL,
+    unless  TEXT
+) ON COMMIT DROP;
+
+INSERT INTO equipment_patterns (name, pattern, unless) VALUES
+    ('slow_cooker',          '\y(slow[- ]?cooker|crock[- ]?pot)\y',                  NULL),
+    ('pressure_cooker',      '\y(pressure[- ]?cooker|instant pot)\y',                NULL),
+    ('stand_mixer',          '\y(stand mixer|dough hook|paddle attachment)\y',       NULL),
+    ('hand_mixer',           '\y(hand mixer|electric whisk|electric beaters?)\y',    NULL),
+    ('food_processor',       '\yfood processor\y',                                   NULL),
+    ('stick_blender',        '\y(stick|hand|immersion) blender\y',                   NULL),
+    ('blender',              '\yblender\y',                                          '\y(stick|hand|immersion) blender\y'),
+    ('wok',                  '\ywok\y',                                              NULL),
+    ('sous_vide_circulator', '\y(sous[- ]vide|immersion circulator)\y',              NULL),
+    ('pasta_machine',        '\ypasta (machine|roller)\y',                           NULL),
+    ('air_fryer',            '\yair[- ]?fryer\y',                                    NULL),
+    ('deep_fryer',           '\ydeep[- ](fat )?fryer\y',                             NULL),
+    ('dutch_oven',           '\ydutch oven\y',                                       NULL),
+    ('ice_cream_maker',      '\yice[- ]cream (maker|machine)\y',                     NULL),
+    ('mandoline',            '\ymandoline?\y',                                       NULL),
+    ('pizza_stone',          '\y(pizza stone|baking steel)\y',                       NULL),
+    ('thermometer',          '\ythermometer\y',                                      NULL),
+    ('smoker',               '\ysmoker\y',                                           NULL);
+
+INSERT INTO recipe_equipment (recipe_id, name)
+SELECT DISTINCT s.recipe_id, e.name
+FROM steps s
+JOIN equipment_patterns e
+  ON regexp_replace(s.description, COALESCE(e.unless, '$^'), '', 'gi') ~* e.pattern
+WHERE s.recipe_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS household_equipment;
+DROP TABLE IF EXISTS recipe_equipment;
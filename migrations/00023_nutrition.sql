@@ -0,0 +1,23 @@
+-- +goose Up
+-- Nutrition reference data: what 100 g of an ingredient contains. Keyed by
+-- lowercase ingredient name rather than ingredients.uuid, so the table can be
+-- seeded (seed-nutrition, from a bundled CSV) before any recipe uses an
+-- ingredient, and survives ingredients being renamed or merged. density
+-- (g/ml) and piece_weight (g per whole one) let lines measured by volume or
+-- by count be weighed; either may be unknown.
+
+CREATE TABLE ingredient_nutrition (
+  ingredient   TEXT PRIMARY KEY CHECK (ingredient = lower(ingredient)),
+  calories     DOUBLE PRECISION NOT NULL CHECK (calories >= 0),
+  protein      DOUBLE PRECISION NOT NULL CHECK (protein >= 0),
+  fat          DOUBLE PRECISION NOT NULL CHECK (fat >= 0),
+  carbs        DOUBLE PRECISION NOT NULL CHECK (carbs >= 0),
+  fibre        DOUBLE PRECISION NOT NULL CHECK (fibre >= 0),
+  salt         DOUBLE PRECISION NOT NULL CHECK (salt >= 0),
+  density      DOUBLE PRECISION CHECK (density > 0),
+  piece_weight DOUBLE PRECISION CHECK (piece_weight > 0),
+  updated_at   TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS ingredient_nutrition;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- The people we cook for. allergens are dietary flags ("gluten", "nuts") and
+-- diets are diet label names ("vegetarian", "low_fodmap"); both are checked
+-- against ingredient names by the rules in internal/domain/recipe/dietary.go,
+-- so they are kept as plain text here. Names are matched case-insensitively.
+
+CREATE TABLE household_members (
+  name       TEXT PRIMARY KEY CHECK (name <> ''),
+  allergens  TEXT[] NOT NULL DEFAULT '{}',
+  diets      TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_household_members_lower_name ON household_members (LOWER(name));
+
+-- Every ingredient a recipe uses, its sub-recipes' (and theirs) included, so
+-- a listing can leave out recipes with something in them someone can't eat.
+-- UNION rather than UNION ALL stops a bad cycle recursing forever.
+CREATE VIEW recipe_all_ingredients AS
+WITH RECURSIVE parts (recipe_id, part_id) AS (
+  SELECT uuid, uuid FROM recipes
+  UNION
+  SELECT p.recipe_id, rsr.sub_recipe_id
+  FROM parts p
+  JOIN recipe_sub_recipes rsr ON rsr.recipe_id = p.part_id
+)
+SELECT DISTINCT p.recipe_id, ri.ingredient_id
+FROM parts p
+JOIN recipe_ingredient ri ON ri.recipe_id = p.part_id;
+
+-- +goose Down
+DROP VIEW IF EXISTS recipe_all_ingredients;
+DROP TABLE IF EXISTS household_members;
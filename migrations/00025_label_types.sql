@@ -0,0 +1,99 @@
+-- +goose Up
+-- The label taxonomy moves out of labels_type_check (00007) and into data, so
+-- a new kind of label ("occasion", "season") is a row rather than a migration.
+-- label_types lists the kinds; every labels row is an allowed name within its
+-- type, now with a display name, a description, and aliases: other spellings
+-- ("veggie", "bbq") that resolve to it when a recipe is saved. Names and
+-- aliases are lowercase snake_case.
+
+CREATE TABLE label_types (
+  name         TEXT PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]*$'),
+  display_name TEXT NOT NULL,
+  description  TEXT NOT NULL DEFAULT '',
+  -- Whether a recipe can carry more than one label of the type. A recipe is
+  -- one course, but can be several cuisines.
+  multiple     BOOLEAN NOT NULL DEFAULT TRUE,
+  position     INTEGER NOT NULL DEFAULT 0,
+  created_at   TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at   TIMESTAMP NOT NULL DEFAULT now()
+);
+
+INSERT INTO label_types (name, display_name, description, multiple, position) VALUES
+    ('course',  'Course',  'The part of a meal the recipe is',               FALSE, 1),
+    ('cuisine', 'Cuisine', 'The culinary tradition the recipe belongs to',   TRUE,  2),
+    ('diet',    'Diet',    'Diets the recipe satisfies as written',          TRUE,  3),
+    ('method',  'Method',  'How the recipe is cooked',                       TRUE,  4);
+
+ALTER TABLE labels DROP CONSTRAINT labels_type_check;
+ALTER TABLE labels ADD CONSTRAINT labels_type_fkey
+    FOREIGN KEY (type) REFERENCES label_types (name) ON UPDATE CASCADE;
+ALTER TABLE labels ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE labels ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE labels ADD COLUMN aliases TEXT[] NOT NULL DEFAULT '{}';
+
+-- The canonical names cmd/tag used to hard-code, not all of which have been
+-- used yet.
+INSERT INTO labels (type, name) VALUES
+    ('course', 'main'), ('course', 'side'), ('course', 'starter'),
+    ('course', 'dessert'), ('course', 'breakfast'), ('course', 'lunch'),
+    ('course', 'snack'), ('course', 'soup'), ('course', 'stew'),
+    ('course', 'salad'), ('course', 'sauce'), ('course', 'bread'),
+    ('course', 'pastry'), ('course', 'drink'), ('course', 'condiment'),
+    ('cuisine', 'british'), ('cuisine', 'irish'), ('cuisine', 'german'),
+    ('cuisine', 'french'), ('cuisine', 'spanish'), ('cuisine', 'italian'),
+    ('cuisine', 'greek'), ('cuisine', 'mediterranean'), ('cuisine', 'middle_eastern'),
+    ('cuisine', 'indian'), ('cuisine', 'thai'), ('cuisine', 'chinese'),
+    ('cuisine', 'korean'), ('cuisine', 'japanese'), ('cuisine', 'vietnamese'),
+    ('cuisine', 'indonesian'), ('cuisine', 'mexican'), ('cuisine', 'american'),
+    ('cuisine', 'moroccan'), ('cuisine', 'african'), ('cuisine', 'georgian'),
+    ('diet', 'vegetarian'), ('diet', 'vegan'), ('diet', 'gluten_free'),
+    ('diet', 'dairy_free'), ('diet', 'egg_free'), ('diet', 'nut_free'),
+    ('diet', 'low_fodmap'), ('diet', 'low_carb'), ('diet', 'low_calorie'),
+    ('method', 'slow_cooked'), ('method', 'baked'), ('method', 'grilled'),
+    ('method', 'fried'), ('method', 'roasted'), ('method', 'raw'),
+    ('method', 'no_cook'), ('method', 'fermented'), ('method', 'microwave'),
+    ('method', 'sous_vide'), ('method', 'stir_fry')
+ON CONFLICT (type, name) DO NOTHING;
+
+UPDATE labels SET display_name = INITCAP(REPLACE(name, '_', ' '));
+UPDATE labels SET display_name = 'Low FODMAP' WHERE type = 'diet' AND name = 'low_fodmap';
+
+-- The spellings 00007 folded into each canonical name, where they are
+-- another word for it rather than a dish that happens to be one.
+CREATE TEMP TABLE label_alias_seed (
+    type  TEXT NOT NULL,
+    name  TEXT NOT NULL,
+    alias TEXT NOT NULL
+) ON COMMIT DROP;
+
+INSERT INTO label_alias_seed (type, name, alias) VALUES
+    ('course', 'main', 'main_course'), ('course', 'main', 'mains'), ('course', 'main', 'dinner'),
+    ('course', 'side', 'sides'), ('course', 'side', 'side_dish'),
+    ('course', 'starter', 'appetizer'), ('course', 'starter', 'appetiser'),
+    ('diet', 'vegetarian', 'veggie'),
+    ('diet', 'low_fodmap', 'onion_free'),
+    ('diet', 'low_calorie', 'low_cal'),
+    ('method', 'slow_cooked', 'slow_cooker'),
+    ('method', 'baked', 'baking'),
+    ('method', 'grilled', 'bbq'),
+    ('method', 'fried', 'deep_fried'),
+    ('method', 'no_cook', 'no_bake');
+
+UPDATE labels l
+SET aliases = seed.aliases
+FROM (
+    SELECT type, name, ARRAY_AGG(alias ORDER BY alias) AS aliases
+    FROM label_alias_seed
+    GROUP BY type, name
+) seed
+WHERE l.type = seed.type AND l.name = seed.name;
+
+-- +goose Down
+-- NB: fails while any label has a type other than the original four.
+ALTER TABLE labels DROP COLUMN aliases;
+ALTER TABLE labels DROP COLUMN description;
+ALTER TABLE labels DROP COLUMN display_name;
+ALTER TABLE labels DROP CONSTRAINT labels_type_fkey;
+ALTER TABLE labels ADD CONSTRAINT labels_type_check
+    CHECK (type IN ('course', 'cuisine', 'diet', 'method'));
+DROP TABLE IF EXISTS label_types;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Other names an ingredient goes by ("scallions" for spring onion). Recipe
+-- saves and pantry lookups resolve a name through these, and through simple
+-- singular/plural forms, before creating a new ingredient, so near-duplicates
+-- stop accumulating. Aliases are stored lowercase; renaming or merging an
+-- ingredient keeps the old name as one.
+
+ALTER TABLE ingredients ADD COLUMN aliases TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_ingredients_aliases ON ingredients USING GIN (aliases);
+CREATE INDEX idx_ingredients_lower_name ON ingredients (LOWER(name));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ingredients_lower_name;
+DROP INDEX IF EXISTS idx_ingredients_aliases;
+ALTER TABLE ingredients DROP COLUMN IF EXISTS aliases;
@@ -0,0 +1,123 @@
+-- +goose Up
+-- Units become a vetted registry. Every unit has a dimension (mass, volume or
+-- a count of things) and aliases: other ways it gets written ("tbsp", "T",
+-- "tablespoons"). Recipe saves resolve a unit through these rather than
+-- creating a row for any new spelling; one that resolves to nothing keeps its
+-- text on the line (recipe_ingredient.unit_text) and is queued in
+-- unit_reviews until it is made a unit, added as an alias, or dismissed.
+-- Aliases match in any case, but an exact match wins: that's what tells "T"
+-- (tablespoon) from "t" (teaspoon).
+--
+-- Like 00008, this is one-way for the data: spellings folded into a
+-- canonical unit stay folded.
+
+ALTER TABLE units ADD COLUMN aliases TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE units ADD COLUMN dimension TEXT;
+ALTER TABLE recipe_ingredient ADD COLUMN unit_text TEXT;
+
+CREATE TABLE unit_reviews (
+  name       TEXT PRIMARY KEY CHECK (name <> ''),
+  first_seen TIMESTAMP NOT NULL DEFAULT now(),
+  last_seen  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TEMP TABLE unit_seed (
+  name         TEXT,
+  abbreviation TEXT,
+  dimension    TEXT,
+  aliases      TEXT[]
+) ON COMMIT DROP;
+
+INSERT INTO unit_seed (name, abbreviation, dimension, aliases) VALUES
+    ('gram',        'g',     'mass',   '{g,gr,gm,gms,gramme}'),
+    ('kilogram',    'kg',    'mass',   '{kg,kgs,kilo}'),
+    ('milligram',   'mg',    'mass',   '{mg}'),
+    ('ounce',       'oz',    'mass',   '{oz}'),
+    ('pound',       'lb',    'mass',   '{lb,lbs}'),
+    ('millilitre',  'ml',    'volume', '{ml,mls,milliliter}'),
+    ('centilitre',  'cl',    'volume', '{cl,centiliter}'),
+    ('decilitre',   'dl',    'volume', '{dl,deciliter}'),
+    ('litre',       'l',     'volume', '{l,liter,ltr}'),
+    ('teaspoon',    'tsp',   'volume', '{tsp,tsps,tspn,t}'),
+    ('tablespoon',  'tbsp',  'volume', '{tbsp,tbsps,tbs,tbl,tblsp,T}'),
+    ('cup',         '',      'volume', '{c}'),
+    ('pint',        '',      'volume', '{pt}'),
+    ('fluid ounce', 'fl oz', 'volume', '{fl oz,fl. oz,floz}'),
+    ('pinch',       '',      'volume', '{}'),
+    ('dash',        '',      'volume', '{}'),
+    ('piece',       '',      'count',  '{pc,pcs,whole,each}'),
+    ('clove',       '',      'count',  '{}'),
+    ('tin',         '',      'count',  '{can}'),
+    ('slice',       '',      'count',  '{}'),
+    ('bunch',       '',      'count',  '{}'),
+    ('handful',     '',      'count',  '{}'),
+    ('sprig',       '',      'count',  '{}'),
+    ('knob',        '',      'count',  '{}'),
+    ('stick',       '',      'count',  '{}'),
+    ('sheet',       '',      'count',  '{}');
+
+INSERT INTO units (name, abbreviation, dimension, aliases)
+SELECT name, NULLIF(abbreviation, ''), dimension, aliases FROM unit_seed
+ON CONFLICT (name) DO UPDATE
+SET abbreviation = EXCLUDED.abbreviation,
+    dimension = EXCLUDED.dimension,
+    aliases = EXCLUDED.aliases,
+    updated_at = now();
+
+-- Fold existing spellings ("tablespoons", "tbsp", "g") into their unit.
+WITH folded AS (
+  SELECT u.uuid AS old_uuid, c.uuid AS canonical_uuid
+  FROM units u
+  JOIN units c ON c.dimension IS NOT NULL AND c.uuid <> u.uuid
+  WHERE u.dimension IS NULL
+    AND (rtrim(u.name, '.') = ANY(c.aliases) OR rtrim(u.name, '.') IN (c.name || 's', c.name || 'es'))
+)
+UPDATE recipe_ingredient ri
+SET unit_id = f.canonical_uuid
+FROM folded f
+WHERE ri.unit_id = f.old_uuid;
+
+DELETE FROM units u
+WHERE u.dimension IS NULL
+  AND NOT EXISTS (SELECT 1 FROM recipe_ingredient ri WHERE ri.unit_id = u.uuid)
+  AND EXISTS (
+    SELECT 1 FROM units c
+    WHERE c.dimension IS NOT NULL
+      AND (rtrim(u.name, '.') = ANY(c.aliases) OR rtrim(u.name, '.') IN (c.name || 's', c.name || 'es'))
+  );
+
+-- Whatever is left was never vetted: queue it, keeping the text on its lines.
+UPDATE recipe_ingredient ri
+SET unit_text = u.name, unit_id = NULL
+FROM units u
+WHERE ri.unit_id = u.uuid AND u.dimension IS NULL;
+
+INSERT INTO unit_reviews (name)
+SELECT name FROM units WHERE dimension IS NULL AND name <> '';
+
+DELETE FROM units WHERE dimension IS NULL;
+
+ALTER TABLE units ALTER COLUMN dimension SET NOT NULL;
+ALTER TABLE units ADD CONSTRAINT units_dimension_check
+    CHECK (dimension IN ('mass', 'volume', 'count'));
+
+CREATE INDEX idx_recipe_ingredient_unit_text ON recipe_ingredient (unit_text)
+    WHERE unit_text IS NOT NULL;
+
+-- +goose Down
+-- Queued spellings come back as units of their own; folded ones don't.
+INSERT INTO units (name)
+SELECT DISTINCT unit_text FROM recipe_ingredient WHERE unit_text IS NOT NULL
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE recipe_ingredient ri
+SET unit_id = u.uuid
+FROM units u
+WHERE ri.unit_text = u.name;
+
+DROP INDEX IF EXISTS idx_recipe_ingredient_unit_text;
+ALTER TABLE recipe_ingredient DROP COLUMN unit_text;
+DROP TABLE IF EXISTS unit_reviews;
+ALTER TABLE units DROP CONSTRAINT units_dimension_check;
+ALTER TABLE units DROP COLUMN dimension;
+ALTER TABLE units DROP COLUMN aliases;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Grocery categories, so the shopping list can be walked in the order the
+-- shop is laid out rather than alphabetically. An ingredient or custom item
+-- without one is guessed from its name when the list is built (see
+-- pantry.GuessCategory); a stored category comes from an edit or from the
+-- categorise-ingredients command.
+
+ALTER TABLE ingredients ADD COLUMN category TEXT
+    CHECK (category IN ('produce', 'bakery', 'meat', 'dairy', 'frozen', 'tins', 'spices', 'household', 'other'));
+ALTER TABLE shopping_list_items ADD COLUMN category TEXT
+    CHECK (category IN ('produce', 'bakery', 'meat', 'dairy', 'frozen', 'tins', 'spices', 'household', 'other'));
+
+-- The walking order through the shop. A single row: there's one shop. No row
+-- means the default order.
+CREATE TABLE store_layout (
+  id         BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+  categories TEXT[] NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS store_layout;
+ALTER TABLE shopping_list_items DROP COLUMN category;
+ALTER TABLE ingredients DROP COLUMN category;
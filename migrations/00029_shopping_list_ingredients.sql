@@ -0,0 +1,27 @@
+-- +goose Up
+-- Shopping list items can be known ingredients, not just free text: "eggs"
+-- added by hand or read off a photo resolves to the egg ingredient, so
+-- stocking the pantry with it takes it off the list like a meal-plan item.
+-- name stays as the ingredient's name when it was added, which keeps the
+-- case-insensitive dedupe working for both kinds; listings show the
+-- ingredient's current name.
+
+ALTER TABLE shopping_list_items
+    ADD COLUMN ingredient_id UUID REFERENCES ingredients(uuid) ON DELETE CASCADE;
+
+CREATE INDEX idx_shopping_list_items_ingredient ON shopping_list_items (ingredient_id)
+    WHERE ingredient_id IS NOT NULL;
+
+-- Free-text items already on the list that name an ingredient exactly become
+-- that ingredient.
+UPDATE shopping_list_items s
+SET ingredient_id = (
+  SELECT i.uuid FROM ingredients i
+  WHERE lower(i.name) = lower(s.name)
+  ORDER BY i.created_at, i.uuid
+  LIMIT 1
+);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_shopping_list_items_ingredient;
+ALTER TABLE shopping_list_items DROP COLUMN ingredient_id;
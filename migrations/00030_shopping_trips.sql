@@ -0,0 +1,26 @@
+-- +goose Up
+-- Shopping trips. Lines on the shopping list can be checked off as they go in
+-- the basket, and everyone looking at the list sees the same ticks. Meal-plan
+-- lines aren't stored anywhere (they're derived from the plan and the pantry),
+-- so checks are kept by line name, lowercased, rather than on
+-- shopping_list_items. Completing a trip stocks the pantry with the checked
+-- ingredients, deletes the checked custom items, clears the checks and records
+-- what was bought.
+
+CREATE TABLE shopping_list_checks (
+  name       TEXT PRIMARY KEY CHECK (name = lower(name)),
+  checked_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE shopping_trips (
+  uuid         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  items        TEXT[] NOT NULL,
+  stocked      TEXT[] NOT NULL,
+  completed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_shopping_trips_completed_at ON shopping_trips (completed_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS shopping_trips;
+DROP TABLE IF EXISTS shopping_list_checks;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Staples: ingredients assumed to be in the pantry whether or not anyone
+-- stocked them, so salt and olive oil stop landing on every shopping list. A
+-- staple counts as in stock until someone says it's run out (staple_out_at),
+-- and comes back when it's stocked again. The flag is on the ingredient, not
+-- the pantry entry: unticking a staple in the pantry is the very thing that
+-- used to put it back on the list.
+
+ALTER TABLE ingredients ADD COLUMN staple BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE ingredients ADD COLUMN staple_out_at TIMESTAMP;
+
+CREATE INDEX idx_ingredients_staple ON ingredients (lower(name)) WHERE staple;
+
+-- The usual suspects, where the book already has them.
+UPDATE ingredients SET staple = true
+WHERE lower(name) IN ('salt', 'sea salt', 'pepper', 'black pepper', 'water', 'oil', 'olive oil', 'vegetable oil');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ingredients_staple;
+ALTER TABLE ingredients DROP COLUMN staple_out_at;
+ALTER TABLE ingredients DROP COLUMN staple;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- The pantry can hold things no recipe uses yet ("tinned tomatoes"): stocking
+-- an unknown name creates the ingredient, flagged pantry_only. When a recipe
+-- later uses it — by name, a plural or an alias, as recipe saves resolve
+-- ingredients — the recipe line links to that same row and the flag clears.
+-- It stays cleared: pantry_only records where the ingredient came from, not
+-- whether a recipe uses it today (the catalogue's uses count says that).
+
+ALTER TABLE ingredients ADD COLUMN pantry_only BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE ingredients DROP COLUMN pantry_only;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- A dismissed unit review is kept, marked, rather than deleted: the lines
+-- using the text are re-saved on every edit of their recipe, and would queue
+-- it again straight away. Making the text a unit or an alias still removes
+-- the row.
+
+ALTER TABLE unit_reviews ADD COLUMN dismissed_at TIMESTAMP;
+
+-- +goose Down
+DELETE FROM unit_reviews WHERE dismissed_at IS NOT NULL;
+ALTER TABLE unit_reviews DROP COLUMN dismissed_at;